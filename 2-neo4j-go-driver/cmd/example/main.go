@@ -1,54 +1,64 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
-	"io"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"graphconnect/go-driver/mapping"
 	"os"
+	"os/signal"
 	"strings"
+	"time"
 )
 
+const timeout = 30 * time.Second
+
 func main() {
-	driver, err := neo4j.NewDriver(os.Args[1], neo4j.BasicAuth(os.Args[2], os.Args[3], ""))
+	// the program stops on Ctrl+C or after the timeout, whichever comes first
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	driver, err := neo4j.NewDriverWithContext(os.Args[1], neo4j.BasicAuth(os.Args[2], os.Args[3], ""))
 	if err != nil {
 		panic(err)
 	}
-	defer handleClose(driver)
-	session := driver.NewSession(neo4j.SessionConfig{})
-	defer handleClose(session)
-	result, err := session.ReadTransaction(sayHello)
+	defer handleClose(ctx, driver)
+	session := driver.NewSession(ctx, neo4j.SessionConfig{})
+	defer handleClose(ctx, session)
+	result, err := session.ExecuteRead(ctx, sayHello(ctx))
 	if err != nil {
 		panic(err)
 	}
 	fmt.Printf("Program says: %q", result)
 }
 
-func sayHello(tx neo4j.Transaction) (any, error) {
-	result, err := tx.Run(
-		`RETURN reduce(acc = "", letter IN $letters | acc + letter) AS hello`,
-		map[string]interface{}{
-			"letters": strings.Split("Hello, GraphConnect!", ""),
+func sayHello(ctx context.Context) neo4j.ManagedTransactionWork {
+	return func(tx neo4j.ManagedTransaction) (any, error) {
+		query := `RETURN reduce(acc = "", letter IN $letters | acc + letter) AS hello`
+		parameters, err := mapping.Parameters(query, struct {
+			Letters []string `neo4j:"letters"`
+		}{
+			Letters: strings.Split("Hello, GraphConnect!", ""),
 		})
-	if err != nil {
-		return nil, err
-	}
-	record, err := result.Single()
-	if err != nil {
-		return nil, err
+		if err != nil {
+			return nil, err
+		}
+		result, err := tx.Run(ctx, query, parameters)
+		if err != nil {
+			return nil, err
+		}
+		return mapping.SingleAs[string](ctx, result)
 	}
-	rawHello, found := record.Get("hello")
-	if !found {
-		return nil, fmt.Errorf("expected 'hello' column in result, none found")
-	}
-	hello, ok := rawHello.(string)
-	if !ok {
-		return nil, fmt.Errorf("expected 'hello' column to be a string, but was not")
-	}
-	return hello, nil
 }
 
-func handleClose(closer io.Closer) {
-	if err := closer.Close(); err != nil {
+// closer is implemented by the drivers and sessions of the 5.x driver, which close with a context
+type closer interface {
+	Close(ctx context.Context) error
+}
+
+func handleClose(ctx context.Context, closer closer) {
+	if err := closer.Close(ctx); err != nil {
 		panic(err)
 	}
 }
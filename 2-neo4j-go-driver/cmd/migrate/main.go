@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"graphconnect/go-driver/graph"
+	"graphconnect/go-driver/migrate"
+	"graphconnect/go-driver/schema"
+	"os"
+	"os/signal"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Run `go run ./2-neo4j-go-driver/cmd/migrate neo4j://localhost neo4j <password> [up|dry-run|status|schema]`
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	if len(os.Args) < 4 {
+		fmt.Fprintln(os.Stderr, "usage: migrate <uri> <username> <password> [up|dry-run|status|schema]")
+		os.Exit(2)
+	}
+	command := "up"
+	if len(os.Args) > 4 {
+		command = os.Args[4]
+	}
+	driver, err := neo4j.NewDriverWithContext(os.Args[1], neo4j.BasicAuth(os.Args[2], os.Args[3], ""))
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		if err := driver.Close(ctx); err != nil {
+			panic(err)
+		}
+	}()
+	if err := run(ctx, driver, command); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, driver neo4j.DriverWithContext, command string) error {
+	switch command {
+	case "status":
+		statuses, err := migrate.New(driver, graph.Migrations).Status(ctx)
+		for _, status := range statuses {
+			fmt.Println(status)
+		}
+		return err
+	case "up", "dry-run":
+		var options []migrate.Option
+		if command == "dry-run" {
+			options = append(options, migrate.WithDryRun())
+		}
+		migrations, err := migrate.New(driver, graph.Migrations, options...).Migrate(ctx)
+		for _, migration := range migrations {
+			fmt.Printf("%s: %d schema and %d data statements\n", migration, len(migration.Schema), len(migration.Data))
+		}
+		return err
+	case "schema":
+		// compares the indexes and constraints of the database to the ones the repositories rely on
+		diff, err := schema.NewInspector(driver).Diff(ctx, graph.Schema)
+		if err != nil {
+			return err
+		}
+		fmt.Println(diff)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q, expected one of: up, dry-run, status, schema", command)
+}
@@ -0,0 +1,150 @@
+// Package cypher tokenizes Cypher scripts, splits them into statements and quotes names in generated ones
+//
+// It has no dependency, so that the packages of the module do not depend on the test-support module
+// graphconnect/neo4jtest, which keeps a splitter of its own to seed test databases
+package cypher
+
+import (
+	"regexp"
+	"strings"
+)
+
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var schemaStatement = regexp.MustCompile(`(?i)^(CREATE|DROP)\s+(OR\s+REPLACE\s+)?(\w+\s+)?(INDEX|CONSTRAINT)\b`)
+
+// TokenKind tells what a Token of a Cypher script is
+type TokenKind int
+
+const (
+	// Code is the text between the other tokens, including the semicolons separating statements
+	Code TokenKind = iota
+	// String is a string literal, along with its quotes
+	String
+	// QuotedName is a name escaped with backticks, along with them
+	QuotedName
+	// Comment is a line comment, without its line break, or a block comment
+	Comment
+)
+
+// Token is a piece of a Cypher script
+type Token struct {
+	Kind TokenKind
+	Text string
+}
+
+// Tokenize cuts a Cypher script into code, strings, quoted names and comments, so that the semicolons or the $ signs of
+// strings and comments are not taken for statement separators or parameters
+// Unterminated strings, quoted names and block comments extend to the end of the script
+func Tokenize(script string) []Token {
+	var tokens []Token
+	runes := []rune(script)
+	code := 0
+	for i := 0; i < len(runes); i++ {
+		kind, end := String, i
+		switch char := runes[i]; {
+		case char == '\'' || char == '"':
+			end = closingQuote(runes, i)
+		case char == '`':
+			kind, end = QuotedName, closingQuote(runes, i)
+		case char == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			kind, end = Comment, i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+		case char == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			// note: the closing */ cannot share the * of the opening /*, e.g. in /*/
+			kind, end = Comment, len(runes)
+			for j := i + 3; j < len(runes); j++ {
+				if runes[j-1] == '*' && runes[j] == '/' {
+					end = j + 1
+					break
+				}
+			}
+		default:
+			continue
+		}
+		if code < i {
+			tokens = append(tokens, Token{Kind: Code, Text: string(runes[code:i])})
+		}
+		tokens = append(tokens, Token{Kind: kind, Text: string(runes[i:end])})
+		code, i = end, end-1
+	}
+	if code < len(runes) {
+		tokens = append(tokens, Token{Kind: Code, Text: string(runes[code:])})
+	}
+	return tokens
+}
+
+// Split splits a Cypher script on semicolons, ignoring the ones in strings, quoted names and comments
+// Comments are removed and blank statements are skipped
+func Split(script string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+	for _, token := range Tokenize(script) {
+		switch token.Kind {
+		case Code:
+			for i, part := range strings.Split(token.Text, ";") {
+				if i > 0 {
+					flush()
+				}
+				current.WriteString(part)
+			}
+		case Comment:
+			// note: block comments may separate words, line comments are followed by their line break
+			if strings.HasPrefix(token.Text, "/*") {
+				current.WriteRune(' ')
+			}
+		default:
+			current.WriteString(token.Text)
+		}
+	}
+	flush()
+	return statements
+}
+
+// closingQuote returns the index following the quote closing the one at start, backslashes escape quotes in strings
+func closingQuote(runes []rune, start int) int {
+	quote := runes[start]
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && quote != '`':
+			i++
+		case runes[i] == quote:
+			return i + 1
+		}
+	}
+	return len(runes)
+}
+
+// IsSchema reports whether the statement creates or drops an index or a constraint
+func IsSchema(statement string) bool {
+	return schemaStatement.MatchString(statement)
+}
+
+// Partition separates the schema statements, creating or dropping indexes and constraints, from the data statements
+// Neo4j cannot run both kinds of statements in the same transaction
+func Partition(statements []string) (schema []string, data []string) {
+	for _, statement := range statements {
+		if IsSchema(statement) {
+			schema = append(schema, statement)
+		} else {
+			data = append(data, statement)
+		}
+	}
+	return schema, data
+}
+
+// Quote escapes the label, relationship type, property or variable name with backticks, unless it is a plain identifier
+func Quote(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
@@ -0,0 +1,86 @@
+package cypher_test
+
+import (
+	"graphconnect/go-driver/cypher"
+	"reflect"
+	"testing"
+)
+
+func TestCypher(outer *testing.T) {
+	outer.Run("splits scripts into statements", func(t *testing.T) {
+		statements := cypher.Split(`
+// a comment; with a semicolon
+CREATE CONSTRAINT person_name FOR (p:Person) REQUIRE p.name IS UNIQUE;
+CREATE (:Person {name: "Semi;colon", quote: 'it\'s'}) /* block; comment */;;
+MATCH (` + "`odd;label`" + `) RETURN count(*)
+`)
+
+		expected := []string{
+			"CREATE CONSTRAINT person_name FOR (p:Person) REQUIRE p.name IS UNIQUE",
+			`CREATE (:Person {name: "Semi;colon", quote: 'it\'s'})`,
+			"MATCH (`odd;label`) RETURN count(*)",
+		}
+		if !reflect.DeepEqual(statements, expected) {
+			t.Errorf("Expected %q, got: %q", expected, statements)
+		}
+	})
+	outer.Run("does not close block comments on their opening star", func(t *testing.T) {
+		statements := cypher.Split("RETURN 1 /*/ still; a comment */; RETURN 2")
+
+		expected := []string{"RETURN 1", "RETURN 2"}
+		if !reflect.DeepEqual(statements, expected) {
+			t.Errorf("Expected %q, got: %q", expected, statements)
+		}
+	})
+	outer.Run("tokenizes scripts", func(t *testing.T) {
+		tokens := cypher.Tokenize("MATCH (p {name: 'it\\'s'}) // $comment\nRETURN p.`odd` /* unterminated")
+
+		expected := []cypher.Token{
+			{Kind: cypher.Code, Text: "MATCH (p {name: "},
+			{Kind: cypher.String, Text: `'it\'s'`},
+			{Kind: cypher.Code, Text: "}) "},
+			{Kind: cypher.Comment, Text: "// $comment"},
+			{Kind: cypher.Code, Text: "\nRETURN p."},
+			{Kind: cypher.QuotedName, Text: "`odd`"},
+			{Kind: cypher.Code, Text: " "},
+			{Kind: cypher.Comment, Text: "/* unterminated"},
+		}
+		if !reflect.DeepEqual(tokens, expected) {
+			t.Errorf("Expected %q, got: %q", expected, tokens)
+		}
+	})
+	outer.Run("separates schema statements from data statements", func(t *testing.T) {
+		schema, data := cypher.Partition([]string{
+			"CREATE INDEX FOR (t:Topic) ON (t.name)",
+			"MERGE (:Topic {name: 'Neo4j'})",
+			"create text index topic_name if not exists for (t:Topic) on (t.name)",
+			"DROP CONSTRAINT person_name",
+			"CREATE (:Index {name: 'not schema'})",
+		})
+
+		expectedSchema := []string{
+			"CREATE INDEX FOR (t:Topic) ON (t.name)",
+			"create text index topic_name if not exists for (t:Topic) on (t.name)",
+			"DROP CONSTRAINT person_name",
+		}
+		if !reflect.DeepEqual(schema, expectedSchema) {
+			t.Errorf("Expected schema statements %q, got: %q", expectedSchema, schema)
+		}
+		if len(data) != 2 {
+			t.Errorf("Expected 2 data statements, got: %q", data)
+		}
+	})
+	outer.Run("quotes names", func(t *testing.T) {
+		for name, expected := range map[string]string{
+			"Person":    "Person",
+			"WORKS_ON":  "WORKS_ON",
+			"full name": "`full name`",
+			"1st":       "`1st`",
+			"odd`name":  "`odd``name`",
+		} {
+			if quoted := cypher.Quote(name); quoted != expected {
+				t.Errorf("Expected %s, got: %s", expected, quoted)
+			}
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package exporter
+
+import (
+	"bufio"
+	"fmt"
+	"graphconnect/go-driver/cypher"
+	"io"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// exportLabel and exportID tag the created nodes, so that relationships can match them until the end of the script
+const (
+	exportLabel = "__Export"
+	exportID    = "__exportId"
+)
+
+// CypherWriter writes a Cypher script recreating the entities, one statement per entity, which neo4jtest.LoadSeed can run
+//
+//	CREATE INDEX __export_id IF NOT EXISTS FOR (n:__Export) ON (n.__exportId);
+//	CREATE (:Person:__Export {__exportId: "4:...:0", name: "Eric"});
+//	CREATE (:Project:__Export {__exportId: "4:...:1", name: "GoGM"});
+//	MATCH (a:__Export {__exportId: "4:...:0"}), (b:__Export {__exportId: "4:...:1"}) CREATE (a)-[:WORKS_ON]->(b);
+//	MATCH (n:__Export) REMOVE n:__Export, n.__exportId;
+//
+// The index is left in place since the seed loader runs the schema statements before the data ones
+// Byte arrays have no Cypher literal, exporting them fails
+type CypherWriter struct {
+	writer *bufio.Writer
+}
+
+// NewCypherWriter returns a CypherWriter writing to the writer
+func NewCypherWriter(writer io.Writer) *CypherWriter {
+	return &CypherWriter{writer: bufio.NewWriter(writer)}
+}
+
+func (writer *CypherWriter) Begin([]string) error {
+	writer.writer.WriteString("// Exported graph, replay it with neo4jtest.LoadSeed\n\n")
+	_, err := fmt.Fprintf(writer.writer, "CREATE INDEX __export_id IF NOT EXISTS FOR (n:%s) ON (n.%s);\n\n", exportLabel, exportID)
+	return err
+}
+
+func (writer *CypherWriter) Node(node neo4j.Node) error {
+	properties, err := propertyEntries(node.Props)
+	if err != nil {
+		return fmt.Errorf("node %s: %w", node.ElementId, err)
+	}
+	var labels strings.Builder
+	for _, label := range node.Labels {
+		labels.WriteString(":" + cypher.Quote(label))
+	}
+	_, err = fmt.Fprintf(writer.writer, "CREATE (%s:%s {%s});\n", labels.String(), exportLabel, withExportID(node.ElementId, properties))
+	return err
+}
+
+func (writer *CypherWriter) Relationship(relationship neo4j.Relationship) error {
+	properties, err := propertyEntries(relationship.Props)
+	if err != nil {
+		return fmt.Errorf("relationship %s: %w", relationship.ElementId, err)
+	}
+	if properties != "" {
+		properties = " {" + properties + "}"
+	}
+	_, err = fmt.Fprintf(writer.writer, "MATCH (a:%s {%s}), (b:%s {%s}) CREATE (a)-[:%s%s]->(b);\n",
+		exportLabel, withExportID(relationship.StartElementId, ""),
+		exportLabel, withExportID(relationship.EndElementId, ""),
+		cypher.Quote(relationship.Type), properties)
+	return err
+}
+
+func (writer *CypherWriter) End() error {
+	fmt.Fprintf(writer.writer, "\nMATCH (n:%s) REMOVE n:%s, n.%s;\n", exportLabel, exportLabel, exportID)
+	return writer.writer.Flush()
+}
+
+func withExportID(id, properties string) string {
+	entries := exportID + ": " + stringLiteral(id)
+	if properties == "" {
+		return entries
+	}
+	return entries + ", " + properties
+}
@@ -0,0 +1,108 @@
+package exporter_test
+
+import (
+	"bytes"
+	"graphconnect/go-driver/cypher"
+	"graphconnect/go-driver/exporter"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+func TestCypherWriter(outer *testing.T) {
+	outer.Run("writes a script the seed loader can run", func(t *testing.T) {
+		var output bytes.Buffer
+		writer := exporter.NewCypherWriter(&output)
+
+		write(t, writer,
+			[]neo4j.Node{
+				{ElementId: "1", Labels: []string{"Person"}, Props: map[string]any{"name": "Eric; the \"lead\""}},
+				{ElementId: "3", Labels: []string{"Project", "Open Source"}, Props: map[string]any{"name": "GoGM", "stars": int64(42)}},
+			},
+			[]neo4j.Relationship{
+				{ElementId: "10", StartElementId: "1", EndElementId: "3", Type: "WORKS_ON", Props: map[string]any{"role": "Lead"}},
+			})
+
+		schema, data := cypher.Partition(cypher.Split(output.String()))
+		expectedSchema := []string{"CREATE INDEX __export_id IF NOT EXISTS FOR (n:__Export) ON (n.__exportId)"}
+		expectedData := []string{
+			`CREATE (:Person:__Export {__exportId: "1", name: "Eric; the \"lead\""})`,
+			`CREATE (:Project:` + "`Open Source`" + `:__Export {__exportId: "3", name: "GoGM", stars: 42})`,
+			`MATCH (a:__Export {__exportId: "1"}), (b:__Export {__exportId: "3"}) CREATE (a)-[:WORKS_ON {role: "Lead"}]->(b)`,
+			`MATCH (n:__Export) REMOVE n:__Export, n.__exportId`,
+		}
+		if strings.Join(schema, "\n") != strings.Join(expectedSchema, "\n") {
+			t.Errorf("Expected schema statements %q, got: %q", expectedSchema, schema)
+		}
+		if strings.Join(data, "\n") != strings.Join(expectedData, "\n") {
+			t.Errorf("Expected data statements:\n%s\ngot:\n%s", strings.Join(expectedData, "\n"), strings.Join(data, "\n"))
+		}
+	})
+	outer.Run("writes the literals of the property types", func(t *testing.T) {
+		paris := time.FixedZone("", 2*60*60)
+		for _, test := range []struct {
+			value    any
+			expected string
+		}{
+			{nil, "null"},
+			{true, "true"},
+			{int64(-7), "-7"},
+			{2.0, "2.0"},
+			{1.5e21, "1.5e21"},
+			{math.Inf(-1), "-1.0/0.0"},
+			{"tab\tand \\", `"tab\tand \\"`},
+			{[]any{int64(1), "two"}, `[1, "two"]`},
+			{time.Date(2022, 10, 5, 9, 30, 0, 0, paris), `datetime("2022-10-05T09:30:00+02:00")`},
+			{dbtype.Date(time.Date(2022, 10, 5, 0, 0, 0, 0, time.UTC)), `date("2022-10-05")`},
+			{dbtype.LocalDateTime(time.Date(2022, 10, 5, 9, 30, 0, 0, time.UTC)), `localdatetime("2022-10-05T09:30:00")`},
+			{dbtype.Duration{Days: 1, Seconds: 90}, `duration("P0M1DT90S")`},
+			{dbtype.Point2D{X: 2.35, Y: 48.85, SpatialRefId: 4326}, "point({srid: 4326, x: 2.35, y: 48.85})"},
+		} {
+			var output bytes.Buffer
+			write(t, exporter.NewCypherWriter(&output), []neo4j.Node{{ElementId: "1", Props: map[string]any{"value": test.value}}}, nil)
+
+			expected := `CREATE (:__Export {__exportId: "1", value: ` + test.expected + `})`
+			if !strings.Contains(output.String(), expected) {
+				t.Errorf("Expected %s, got:\n%s", expected, output.String())
+			}
+		}
+	})
+	outer.Run("fails on byte arrays", func(t *testing.T) {
+		writer := exporter.NewCypherWriter(&bytes.Buffer{})
+		if err := writer.Begin(nil); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+
+		err := writer.Node(neo4j.Node{ElementId: "1", Props: map[string]any{"picture": []byte{0xCA, 0xFE}}})
+
+		expected := "node 1: property picture: no Cypher literal for values of type []uint8"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
+
+// write writes the entities between Begin and End, failing the test on errors
+func write(t *testing.T, writer exporter.Writer, nodes []neo4j.Node, relationships []neo4j.Relationship) {
+	t.Helper()
+	if err := writer.Begin([]string{"name", "role", "stars", "value"}); err != nil {
+		t.Fatalf("Could not begin: %v", err)
+	}
+	for _, node := range nodes {
+		if err := writer.Node(node); err != nil {
+			t.Fatalf("Could not write node: %v", err)
+		}
+	}
+	for _, relationship := range relationships {
+		if err := writer.Relationship(relationship); err != nil {
+			t.Fatalf("Could not write relationship: %v", err)
+		}
+	}
+	if err := writer.End(); err != nil {
+		t.Fatalf("Could not end: %v", err)
+	}
+}
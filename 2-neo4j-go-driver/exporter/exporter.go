@@ -0,0 +1,219 @@
+// Package exporter writes the nodes and relationships of a database, or of a query result, as JSON, GraphML or Cypher
+package exporter
+
+import (
+	"context"
+	"fmt"
+	"graphconnect/go-driver/mapping"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const propertyKeysQuery = "CALL db.propertyKeys() YIELD propertyKey RETURN propertyKey ORDER BY propertyKey"
+
+// Writer writes the exported entities in a given format, all the nodes are written before the relationships
+type Writer interface {
+	// Begin starts the output, propertyKeys lists all the property names of the database
+	Begin(propertyKeys []string) error
+	Node(node neo4j.Node) error
+	Relationship(relationship neo4j.Relationship) error
+	// End completes the output
+	End() error
+}
+
+// Stats counts the exported entities
+type Stats struct {
+	Nodes         int
+	Relationships int
+	// SkippedRelationships counts the relationships of a query result whose nodes were not exported, see ExportQuery
+	SkippedRelationships int
+}
+
+func (stats Stats) String() string {
+	if stats.SkippedRelationships > 0 {
+		return fmt.Sprintf("%d nodes, %d relationships, %d skipped relationships",
+			stats.Nodes, stats.Relationships, stats.SkippedRelationships)
+	}
+	return fmt.Sprintf("%d nodes, %d relationships", stats.Nodes, stats.Relationships)
+}
+
+// Exporter reads entities with the driver and writes them with a Writer
+// Records are streamed from the server, by batches of the fetch size of the driver
+type Exporter struct {
+	driver neo4j.DriverWithContext
+	config neo4j.SessionConfig
+}
+
+// Option customizes the Exporter
+type Option func(*Exporter)
+
+// WithDatabase exports the given database, instead of the default one
+func WithDatabase(name string) Option {
+	return func(exporter *Exporter) {
+		exporter.config.DatabaseName = name
+	}
+}
+
+// WithFetchSize sets the number of records pulled from the server at once, see neo4j.SessionConfig
+func WithFetchSize(size int) Option {
+	return func(exporter *Exporter) {
+		exporter.config.FetchSize = size
+	}
+}
+
+// New returns an Exporter reading entities with the driver
+func New(driver neo4j.DriverWithContext, options ...Option) *Exporter {
+	exporter := &Exporter{driver: driver, config: neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead}}
+	for _, option := range options {
+		option(exporter)
+	}
+	return exporter
+}
+
+// Export writes all the nodes and relationships of the database
+func (exporter *Exporter) Export(ctx context.Context, writer Writer) (Stats, error) {
+	return exporter.export(ctx, writer, func(tx neo4j.ExplicitTransaction, stats *Stats) error {
+		if err := forEach(ctx, tx, "MATCH (n) RETURN n", nil, func(node neo4j.Node) error {
+			stats.Nodes++
+			return writer.Node(node)
+		}); err != nil {
+			return err
+		}
+		return forEach(ctx, tx, "MATCH ()-[r]->() RETURN r", nil, func(relationship neo4j.Relationship) error {
+			stats.Relationships++
+			return writer.Relationship(relationship)
+		})
+	})
+}
+
+// ExportQuery writes the nodes and relationships returned by the query, including the ones in paths, lists and maps
+// The query runs twice in the same transaction, first to write the nodes and then to write the relationships, hence it
+// must return the same entities both times, e.g. ORDER BY before LIMIT or SKIP
+// Relationships are only written along with both their nodes, the other ones are counted in Stats.SkippedRelationships
+// The IDs of the written entities are kept in memory, so that entities returned several times are written once
+func (exporter *Exporter) ExportQuery(ctx context.Context, writer Writer, query string, parameters map[string]any) (Stats, error) {
+	return exporter.export(ctx, writer, func(tx neo4j.ExplicitTransaction, stats *Stats) error {
+		nodes := map[string]bool{}
+		if err := forEachRecord(ctx, tx, query, parameters, func(record *neo4j.Record) error {
+			return walk(record.Values, func(entity any) error {
+				node, ok := entity.(neo4j.Node)
+				if !ok || nodes[node.ElementId] {
+					return nil
+				}
+				nodes[node.ElementId] = true
+				stats.Nodes++
+				return writer.Node(node)
+			})
+		}); err != nil {
+			return err
+		}
+		relationships := map[string]bool{}
+		return forEachRecord(ctx, tx, query, parameters, func(record *neo4j.Record) error {
+			return walk(record.Values, func(entity any) error {
+				relationship, ok := entity.(neo4j.Relationship)
+				if !ok || relationships[relationship.ElementId] {
+					return nil
+				}
+				relationships[relationship.ElementId] = true
+				if !nodes[relationship.StartElementId] || !nodes[relationship.EndElementId] {
+					stats.SkippedRelationships++
+					return nil
+				}
+				stats.Relationships++
+				return writer.Relationship(relationship)
+			})
+		})
+	})
+}
+
+// export runs the work in a single read transaction, between the beginning and the end of the output
+// note: an explicit transaction is used since a transaction function could be retried after writing entities
+func (exporter *Exporter) export(ctx context.Context, writer Writer, work func(neo4j.ExplicitTransaction, *Stats) error) (stats Stats, err error) {
+	session := exporter.driver.NewSession(ctx, exporter.config)
+	defer func() {
+		if closeErr := session.Close(ctx); err == nil {
+			err = closeErr
+		}
+	}()
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		return stats, err
+	}
+	defer tx.Close(ctx)
+	var keys []string
+	if err := forEach(ctx, tx, propertyKeysQuery, nil, func(key string) error {
+		keys = append(keys, key)
+		return nil
+	}); err != nil {
+		return stats, err
+	}
+	if err := writer.Begin(keys); err != nil {
+		return stats, err
+	}
+	if err := work(tx, &stats); err != nil {
+		return stats, err
+	}
+	if err := writer.End(); err != nil {
+		return stats, err
+	}
+	return stats, tx.Commit(ctx)
+}
+
+// forEach decodes the records of the query one at a time, see mapping.Decode
+func forEach[T any](ctx context.Context, tx neo4j.ExplicitTransaction, query string, parameters map[string]any, consume func(T) error) error {
+	return forEachRecord(ctx, tx, query, parameters, func(record *neo4j.Record) error {
+		value, err := mapping.Decode[T](record)
+		if err != nil {
+			return err
+		}
+		return consume(value)
+	})
+}
+
+// forEachRecord pulls the records of the query one at a time, by batches of the fetch size
+func forEachRecord(ctx context.Context, tx neo4j.ExplicitTransaction, query string, parameters map[string]any, consume func(*neo4j.Record) error) error {
+	result, err := tx.Run(ctx, query, parameters)
+	if err != nil {
+		return err
+	}
+	for result.Next(ctx) {
+		if err := consume(result.Record()); err != nil {
+			return err
+		}
+	}
+	return result.Err()
+}
+
+// walk calls visit with every value, including the entities of paths and the elements of lists and maps
+func walk(values []any, visit func(any) error) error {
+	for _, value := range values {
+		switch value := value.(type) {
+		case neo4j.Path:
+			for _, node := range value.Nodes {
+				if err := visit(node); err != nil {
+					return err
+				}
+			}
+			for _, relationship := range value.Relationships {
+				if err := visit(relationship); err != nil {
+					return err
+				}
+			}
+		case []any:
+			if err := walk(value, visit); err != nil {
+				return err
+			}
+		case map[string]any:
+			for _, element := range value {
+				if err := walk([]any{element}, visit); err != nil {
+					return err
+				}
+			}
+		default:
+			if err := visit(value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package exporter_test
+
+import (
+	"bytes"
+	"context"
+	"graphconnect/go-driver/exporter"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/neo4jtest/fakebolt"
+	"testing"
+)
+
+const worksOnQuery = "MATCH (p:Person)-[w:WORKS_ON]->(pr:Project) RETURN p, w, pr"
+
+var (
+	eric              = fakebolt.Node{ID: 1, Labels: []string{"Person"}, Props: map[string]any{"name": "Eric"}}
+	nikita            = fakebolt.Node{ID: 2, Labels: []string{"Person"}, Props: map[string]any{"name": "Nikita"}}
+	gogm              = fakebolt.Node{ID: 3, Labels: []string{"Project"}, Props: map[string]any{"name": "GoGM"}}
+	ericWorksOnGogm   = fakebolt.Relationship{ID: 10, StartID: 1, EndID: 3, Type: "WORKS_ON", Props: map[string]any{"role": "Lead"}}
+	nikitaWorksOnGogm = fakebolt.Relationship{ID: 11, StartID: 2, EndID: 3, Type: "WORKS_ON", Props: map[string]any{}}
+)
+
+var script = fakebolt.Script{
+	"CALL db.propertyKeys() YIELD propertyKey RETURN propertyKey ORDER BY propertyKey": {
+		Keys:    []string{"propertyKey"},
+		Records: [][]any{{"name"}, {"role"}},
+	},
+	"MATCH (n) RETURN n": {
+		Keys:    []string{"n"},
+		Records: [][]any{{eric}, {nikita}, {gogm}},
+	},
+	"MATCH ()-[r]->() RETURN r": {
+		Keys:    []string{"r"},
+		Records: [][]any{{ericWorksOnGogm}, {nikitaWorksOnGogm}},
+	},
+	worksOnQuery: {
+		Keys:    []string{"p", "w", "pr"},
+		Records: [][]any{{eric, ericWorksOnGogm, gogm}, {nikita, nikitaWorksOnGogm, []any{gogm}}},
+	},
+}
+
+const expectedJSON = `{"nodes":[
+{"id":"1","labels":["Person"],"properties":{"name":"Eric"}},
+{"id":"2","labels":["Person"],"properties":{"name":"Nikita"}},
+{"id":"3","labels":["Project"],"properties":{"name":"GoGM"}}
+],"relationships":[
+{"id":"10","type":"WORKS_ON","start":"1","end":"3","properties":{"role":"Lead"}},
+{"id":"11","type":"WORKS_ON","start":"2","end":"3","properties":{}}
+]}
+`
+
+func TestExporter(outer *testing.T) {
+	ctx := context.Background()
+
+	outer.Run("exports the database", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		var output bytes.Buffer
+
+		stats, err := exporter.New(driver, exporter.WithFetchSize(1)).Export(ctx, exporter.NewJSONWriter(&output))
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if stats != (exporter.Stats{Nodes: 3, Relationships: 2}) {
+			t.Errorf("Expected 3 nodes and 2 relationships, got: %v", stats)
+		}
+		if output.String() != expectedJSON {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expectedJSON, output.String())
+		}
+	})
+	outer.Run("exports each entity of a query result once", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, script)
+		var output bytes.Buffer
+
+		stats, err := exporter.New(driver).ExportQuery(ctx, exporter.NewJSONWriter(&output), worksOnQuery, nil)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if stats != (exporter.Stats{Nodes: 3, Relationships: 2}) {
+			t.Errorf("Expected 3 nodes and 2 relationships, got: %v", stats)
+		}
+		expected := `{"nodes":[
+{"id":"1","labels":["Person"],"properties":{"name":"Eric"}},
+{"id":"3","labels":["Project"],"properties":{"name":"GoGM"}},
+{"id":"2","labels":["Person"],"properties":{"name":"Nikita"}}
+],"relationships":[
+{"id":"10","type":"WORKS_ON","start":"1","end":"3","properties":{"role":"Lead"}},
+{"id":"11","type":"WORKS_ON","start":"2","end":"3","properties":{}}
+]}
+`
+		if output.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, output.String())
+		}
+		if queries := workshoptest.ReceivedQueries(server); len(queries) != 3 {
+			t.Errorf("Expected the property keys query and the query run twice, got: %v", queries)
+		}
+	})
+	outer.Run("skips the relationships whose nodes are not exported", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		var output bytes.Buffer
+
+		stats, err := exporter.New(driver).ExportQuery(ctx, exporter.NewJSONWriter(&output), "MATCH ()-[r]->() RETURN r", nil)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if stats != (exporter.Stats{SkippedRelationships: 2}) {
+			t.Errorf("Expected 2 skipped relationships, got: %v", stats)
+		}
+		if expected := `{"nodes":[],"relationships":[]}` + "\n"; output.String() != expected {
+			t.Errorf("Expected %q, got: %q", expected, output.String())
+		}
+	})
+	outer.Run("exports an empty database", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"CALL db.propertyKeys() YIELD propertyKey RETURN propertyKey ORDER BY propertyKey": {Keys: []string{"propertyKey"}},
+			"MATCH (n) RETURN n":        {Keys: []string{"n"}},
+			"MATCH ()-[r]->() RETURN r": {Keys: []string{"r"}},
+		})
+		var output bytes.Buffer
+
+		_, err := exporter.New(driver).Export(ctx, exporter.NewJSONWriter(&output))
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if expected := `{"nodes":[],"relationships":[]}` + "\n"; output.String() != expected {
+			t.Errorf("Expected %q, got: %q", expected, output.String())
+		}
+	})
+	outer.Run("fails when the query fails", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+
+		_, err := exporter.New(driver).ExportQuery(ctx, exporter.NewJSONWriter(&bytes.Buffer{}), "MATCH (n) RETURN m", nil)
+
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+}
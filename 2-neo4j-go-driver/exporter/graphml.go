@@ -0,0 +1,106 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// GraphMLWriter writes a GraphML document, readable by Gephi or yEd
+// Labels are written as ":Person:Admin" in the labels attribute of nodes and types in the label attribute of edges
+// All properties are declared as strings, lists and points are written as JSON
+type GraphMLWriter struct {
+	writer *bufio.Writer
+	// keys holds the IDs of the GraphML attributes of the properties
+	keys map[string]string
+}
+
+// NewGraphMLWriter returns a GraphMLWriter writing to the writer
+func NewGraphMLWriter(writer io.Writer) *GraphMLWriter {
+	return &GraphMLWriter{writer: bufio.NewWriter(writer)}
+}
+
+func (writer *GraphMLWriter) Begin(propertyKeys []string) error {
+	writer.keys = make(map[string]string, len(propertyKeys))
+	writer.writer.WriteString(xml.Header)
+	writer.writer.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	writer.writer.WriteString(`<key id="labels" for="node" attr.name="labels" attr.type="string"/>` + "\n")
+	writer.writer.WriteString(`<key id="label" for="edge" attr.name="label" attr.type="string"/>` + "\n")
+	for i, name := range propertyKeys {
+		// note: property names are not valid IDs, e.g. they can contain spaces
+		id := fmt.Sprintf("p%d", i)
+		writer.keys[name] = id
+		fmt.Fprintf(writer.writer, `<key id="%s" for="all" attr.name="%s" attr.type="string"/>`+"\n", id, escape(name))
+	}
+	_, err := writer.writer.WriteString(`<graph id="G" edgedefault="directed">` + "\n")
+	return err
+}
+
+func (writer *GraphMLWriter) Node(node neo4j.Node) error {
+	fmt.Fprintf(writer.writer, `<node id="%s">`, escape(node.ElementId))
+	labels := ""
+	if len(node.Labels) > 0 {
+		labels = ":" + strings.Join(node.Labels, ":")
+	}
+	writer.data("labels", labels)
+	if err := writer.properties(node.Props); err != nil {
+		return fmt.Errorf("node %s: %w", node.ElementId, err)
+	}
+	_, err := writer.writer.WriteString("</node>\n")
+	return err
+}
+
+func (writer *GraphMLWriter) Relationship(relationship neo4j.Relationship) error {
+	fmt.Fprintf(writer.writer, `<edge id="%s" source="%s" target="%s">`,
+		escape(relationship.ElementId), escape(relationship.StartElementId), escape(relationship.EndElementId))
+	writer.data("label", relationship.Type)
+	if err := writer.properties(relationship.Props); err != nil {
+		return fmt.Errorf("relationship %s: %w", relationship.ElementId, err)
+	}
+	_, err := writer.writer.WriteString("</edge>\n")
+	return err
+}
+
+func (writer *GraphMLWriter) End() error {
+	writer.writer.WriteString("</graph>\n</graphml>\n")
+	return writer.writer.Flush()
+}
+
+func (writer *GraphMLWriter) properties(properties map[string]any) error {
+	for _, name := range sortedNames(properties) {
+		id, found := writer.keys[name]
+		if !found {
+			return fmt.Errorf("property %s is not a property key of the database", name)
+		}
+		value, err := text(properties[name])
+		if err != nil {
+			return fmt.Errorf("property %s: %w", name, err)
+		}
+		writer.data(id, value)
+	}
+	return nil
+}
+
+func (writer *GraphMLWriter) data(key, value string) {
+	fmt.Fprintf(writer.writer, `<data key="%s">%s</data>`, key, escape(value))
+}
+
+// text returns strings as is, and the JSON of the other values
+func text(value any) (string, error) {
+	if value, ok := value.(string); ok {
+		return value, nil
+	}
+	encoded, err := json.Marshal(plain(value))
+	return string(encoded), err
+}
+
+func escape(value string) string {
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
@@ -0,0 +1,67 @@
+package exporter_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"graphconnect/go-driver/exporter"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestGraphMLWriter(outer *testing.T) {
+	outer.Run("writes a GraphML document", func(t *testing.T) {
+		var output bytes.Buffer
+
+		write(t, exporter.NewGraphMLWriter(&output),
+			[]neo4j.Node{
+				{ElementId: "1", Labels: []string{"Person"}, Props: map[string]any{"name": "Eric & co"}},
+				{ElementId: "3", Labels: []string{"Project"}, Props: map[string]any{"name": "GoGM", "stars": int64(42)}},
+			},
+			[]neo4j.Relationship{
+				{ElementId: "10", StartElementId: "1", EndElementId: "3", Type: "WORKS_ON", Props: map[string]any{"role": "Lead"}},
+			})
+
+		expected := xml.Header + `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+<key id="labels" for="node" attr.name="labels" attr.type="string"/>
+<key id="label" for="edge" attr.name="label" attr.type="string"/>
+<key id="p0" for="all" attr.name="name" attr.type="string"/>
+<key id="p1" for="all" attr.name="role" attr.type="string"/>
+<key id="p2" for="all" attr.name="stars" attr.type="string"/>
+<key id="p3" for="all" attr.name="value" attr.type="string"/>
+<graph id="G" edgedefault="directed">
+<node id="1"><data key="labels">:Person</data><data key="p0">Eric &amp; co</data></node>
+<node id="3"><data key="labels">:Project</data><data key="p0">GoGM</data><data key="p2">42</data></node>
+<edge id="10" source="1" target="3"><data key="label">WORKS_ON</data><data key="p1">Lead</data></edge>
+</graph>
+</graphml>
+`
+		if output.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, output.String())
+		}
+	})
+	outer.Run("writes lists as JSON", func(t *testing.T) {
+		var output bytes.Buffer
+
+		write(t, exporter.NewGraphMLWriter(&output),
+			[]neo4j.Node{{ElementId: "1", Props: map[string]any{"value": []any{"a", int64(1)}}}}, nil)
+
+		expected := `<node id="1"><data key="labels"></data><data key="p3">[&#34;a&#34;,1]</data></node>`
+		if !bytes.Contains(output.Bytes(), []byte(expected)) {
+			t.Errorf("Expected %s, got:\n%s", expected, output.String())
+		}
+	})
+	outer.Run("fails on undeclared properties", func(t *testing.T) {
+		writer := exporter.NewGraphMLWriter(&bytes.Buffer{})
+		if err := writer.Begin([]string{"name"}); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+
+		err := writer.Node(neo4j.Node{ElementId: "1", Props: map[string]any{"age": int64(42)}})
+
+		expected := "node 1: property age is not a property key of the database"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
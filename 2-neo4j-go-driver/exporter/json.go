@@ -0,0 +1,121 @@
+package exporter
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+type jsonNode struct {
+	ID         string         `json:"id"`
+	Labels     []string       `json:"labels"`
+	Properties map[string]any `json:"properties"`
+}
+
+type jsonRelationship struct {
+	ID         string         `json:"id"`
+	Type       string         `json:"type"`
+	Start      string         `json:"start"`
+	End        string         `json:"end"`
+	Properties map[string]any `json:"properties"`
+}
+
+// JSONWriter writes a JSON object with the nodes and relationships arrays, one entity per line
+//
+//	{"nodes":[
+//	{"id":"4:...:0","labels":["Person"],"properties":{"name":"Eric"}}
+//	],"relationships":[
+//	{"id":"5:...:0","type":"WORKS_ON","start":"4:...:0","end":"4:...:1","properties":{}}
+//	]}
+//
+// Entities are identified by their element ID, temporal values are written as ISO-8601 strings and points as maps
+type JSONWriter struct {
+	writer io.Writer
+	// section is the array being written, nodes or relationships
+	section string
+	empty   bool
+}
+
+// NewJSONWriter returns a JSONWriter writing to the writer
+func NewJSONWriter(writer io.Writer) *JSONWriter {
+	return &JSONWriter{writer: writer}
+}
+
+func (writer *JSONWriter) Begin([]string) error {
+	writer.section, writer.empty = "nodes", true
+	_, err := io.WriteString(writer.writer, `{"nodes":[`)
+	return err
+}
+
+func (writer *JSONWriter) Node(node neo4j.Node) error {
+	return writer.write(jsonNode{ID: node.ElementId, Labels: nonNil(node.Labels), Properties: plainProperties(node.Props)})
+}
+
+func (writer *JSONWriter) Relationship(relationship neo4j.Relationship) error {
+	if err := writer.relationships(); err != nil {
+		return err
+	}
+	return writer.write(jsonRelationship{
+		ID:         relationship.ElementId,
+		Type:       relationship.Type,
+		Start:      relationship.StartElementId,
+		End:        relationship.EndElementId,
+		Properties: plainProperties(relationship.Props),
+	})
+}
+
+func (writer *JSONWriter) End() error {
+	if err := writer.relationships(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer.writer, writer.lineEnd()+"]}\n")
+	return err
+}
+
+// relationships closes the nodes array and opens the relationships one, unless it is already open
+func (writer *JSONWriter) relationships() error {
+	if writer.section == "relationships" {
+		return nil
+	}
+	_, err := io.WriteString(writer.writer, writer.lineEnd()+`],"relationships":[`)
+	writer.section, writer.empty = "relationships", true
+	return err
+}
+
+func (writer *JSONWriter) write(entity any) error {
+	encoded, err := json.Marshal(entity)
+	if err != nil {
+		return err
+	}
+	separator := ",\n"
+	if writer.empty {
+		separator = "\n"
+	}
+	writer.empty = false
+	_, err = writer.writer.Write(append([]byte(separator), encoded...))
+	return err
+}
+
+// lineEnd ends the last entity of an array with a new line
+func (writer *JSONWriter) lineEnd() string {
+	if writer.empty {
+		return ""
+	}
+	return "\n"
+}
+
+func plainProperties(properties map[string]any) map[string]any {
+	converted := make(map[string]any, len(properties))
+	for name, value := range properties {
+		converted[name] = plain(value)
+	}
+	return converted
+}
+
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
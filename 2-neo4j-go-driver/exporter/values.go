@@ -0,0 +1,156 @@
+package exporter
+
+import (
+	"fmt"
+	"graphconnect/go-driver/cypher"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j/dbtype"
+)
+
+// plain converts a property value to a value encoding/json can write
+// Temporal values become their ISO-8601 string, points a map of their coordinates, and NaN or infinite numbers a string
+func plain(value any) any {
+	switch value := value.(type) {
+	case float64:
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return strconv.FormatFloat(value, 'g', -1, 64)
+		}
+		return value
+	case time.Time:
+		return value.Format(time.RFC3339Nano)
+	case dbtype.Date, dbtype.LocalTime, dbtype.LocalDateTime, dbtype.Time, dbtype.Duration:
+		return value.(fmt.Stringer).String()
+	case dbtype.Point2D:
+		return map[string]any{"srid": value.SpatialRefId, "x": plain(value.X), "y": plain(value.Y)}
+	case dbtype.Point3D:
+		return map[string]any{"srid": value.SpatialRefId, "x": plain(value.X), "y": plain(value.Y), "z": plain(value.Z)}
+	case []any:
+		converted := make([]any, len(value))
+		for i, element := range value {
+			converted[i] = plain(element)
+		}
+		return converted
+	}
+	return value
+}
+
+// literal returns the Cypher literal of a property value
+func literal(value any) (string, error) {
+	switch value := value.(type) {
+	case nil:
+		return "null", nil
+	case bool:
+		return strconv.FormatBool(value), nil
+	case int64:
+		return strconv.FormatInt(value, 10), nil
+	case float64:
+		return floatLiteral(value), nil
+	case string:
+		return stringLiteral(value), nil
+	case time.Time:
+		return "datetime(" + stringLiteral(value.Format(time.RFC3339Nano)) + ")", nil
+	case dbtype.Date:
+		return "date(" + stringLiteral(value.String()) + ")", nil
+	case dbtype.LocalTime:
+		return "localtime(" + stringLiteral(value.String()) + ")", nil
+	case dbtype.LocalDateTime:
+		return "localdatetime(" + stringLiteral(value.String()) + ")", nil
+	case dbtype.Time:
+		return "time(" + stringLiteral(value.String()) + ")", nil
+	case dbtype.Duration:
+		return "duration(" + stringLiteral(value.String()) + ")", nil
+	case dbtype.Point2D:
+		return fmt.Sprintf("point({srid: %d, x: %s, y: %s})", value.SpatialRefId, floatLiteral(value.X), floatLiteral(value.Y)), nil
+	case dbtype.Point3D:
+		return fmt.Sprintf("point({srid: %d, x: %s, y: %s, z: %s})",
+			value.SpatialRefId, floatLiteral(value.X), floatLiteral(value.Y), floatLiteral(value.Z)), nil
+	case []any:
+		elements := make([]string, len(value))
+		for i, element := range value {
+			var err error
+			if elements[i], err = literal(element); err != nil {
+				return "", err
+			}
+		}
+		return "[" + strings.Join(elements, ", ") + "]", nil
+	}
+	// note: byte arrays are valid properties, but Cypher has no literal for them
+	return "", fmt.Errorf("no Cypher literal for values of type %T", value)
+}
+
+// propertyEntries returns the entries of the Cypher map literal of the properties, in the order of their names
+func propertyEntries(properties map[string]any) (string, error) {
+	names := sortedNames(properties)
+	entries := make([]string, len(names))
+	for i, name := range names {
+		value, err := literal(properties[name])
+		if err != nil {
+			return "", fmt.Errorf("property %s: %w", name, err)
+		}
+		entries[i] = cypher.Quote(name) + ": " + value
+	}
+	return strings.Join(entries, ", "), nil
+}
+
+// floatLiteral keeps a decimal point, so that the number is read back as a float
+func floatLiteral(value float64) string {
+	switch {
+	case math.IsNaN(value):
+		return "0.0/0.0"
+	case math.IsInf(value, 1):
+		return "1.0/0.0"
+	case math.IsInf(value, -1):
+		return "-1.0/0.0"
+	}
+	// note: Cypher does not accept the plus sign of positive exponents
+	formatted := strings.Replace(strconv.FormatFloat(value, 'g', -1, 64), "e+", "e", 1)
+	if !strings.ContainsAny(formatted, ".eE") {
+		formatted += ".0"
+	}
+	return formatted
+}
+
+func stringLiteral(value string) string {
+	var literal strings.Builder
+	literal.WriteByte('"')
+	for _, r := range value {
+		switch r {
+		case '\\':
+			literal.WriteString(`\\`)
+		case '"':
+			literal.WriteString(`\"`)
+		case '\n':
+			literal.WriteString(`\n`)
+		case '\r':
+			literal.WriteString(`\r`)
+		case '\t':
+			literal.WriteString(`\t`)
+		case '\b':
+			literal.WriteString(`\b`)
+		case '\f':
+			literal.WriteString(`\f`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(&literal, `\u%04x`, r)
+			} else {
+				literal.WriteRune(r)
+			}
+		}
+	}
+	literal.WriteByte('"')
+	return literal.String()
+}
+
+func sortedNames(properties map[string]any) []string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -4,7 +4,8 @@ go 1.18
 
 require (
 	github.com/neo4j/neo4j-go-driver/v4 v4.4.2
-	github.com/testcontainers/testcontainers-go v0.13.0
+	github.com/neo4j/neo4j-go-driver/v5 v5.28.4
+	graphconnect/neo4jtest v0.0.0-00010101000000-000000000000
 )
 
 require (
@@ -32,6 +33,7 @@ require (
 	github.com/opencontainers/runc v1.0.2 // indirect
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/sirupsen/logrus v1.8.1 // indirect
+	github.com/testcontainers/testcontainers-go v0.13.0 // indirect
 	go.opencensus.io v0.22.3 // indirect
 	golang.org/x/net v0.0.0-20211108170745-6635138e15ea // indirect
 	golang.org/x/sys v0.0.0-20211124211545-fe61309f8881 // indirect
@@ -40,3 +42,5 @@ require (
 	google.golang.org/protobuf v1.27.1 // indirect
 	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 )
+
+replace graphconnect/neo4jtest => ../neo4jtest
@@ -0,0 +1,77 @@
+// Package graph stores the small graph of persons working on projects related to topics
+//
+//	(:Person)-[:WORKS_ON]->(:Project)-[:RELATES_TO]->(:Topic)
+//
+// Persons, projects and topics are identified by their name
+package graph
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// ErrNotFound is returned, wrapped, when a person, project, topic or relationship does not exist
+var ErrNotFound = errors.New("not found")
+
+// ErrAlreadyExists is matched by the AlreadyExistsError returned when creating a node breaks a uniqueness constraint
+var ErrAlreadyExists = errors.New("already exists")
+
+// AlreadyExistsError is returned when creating a node breaks a uniqueness constraint, it wraps the error of the driver
+// errors.Is(err, ErrAlreadyExists) reports whether err is one
+type AlreadyExistsError struct {
+	Label string
+	Err   error
+}
+
+func (err *AlreadyExistsError) Error() string {
+	return fmt.Sprintf("%s: %v: %v", err.Label, ErrAlreadyExists, err.Err)
+}
+
+func (err *AlreadyExistsError) Unwrap() error {
+	return err.Err
+}
+
+func (err *AlreadyExistsError) Is(target error) bool {
+	return target == ErrAlreadyExists
+}
+
+// Person is a (:Person) node
+type Person struct {
+	Name string `neo4j:"name"`
+}
+
+// Project is a (:Project) node
+type Project struct {
+	Name string `neo4j:"name"`
+}
+
+// Topic is a (:Topic) node
+type Topic struct {
+	Name string `neo4j:"name"`
+}
+
+// ProjectMaintainers is a project along with the number of persons working on it
+type ProjectMaintainers struct {
+	Project     Project `neo4j:"project"`
+	Maintainers int     `neo4j:"maintainers"`
+}
+
+// Option customizes the repositories
+type Option func(*neo4j.SessionConfig)
+
+// WithDatabase makes the repository run its queries against the given database, instead of the default one
+func WithDatabase(name string) Option {
+	return func(config *neo4j.SessionConfig) {
+		config.DatabaseName = name
+	}
+}
+
+func newSessionConfig(options []Option) neo4j.SessionConfig {
+	config := neo4j.SessionConfig{}
+	for _, option := range options {
+		option(&config)
+	}
+	return config
+}
@@ -0,0 +1,15 @@
+package graph_test
+
+import (
+	"graphconnect/neo4jtest/fakebolt"
+	"testing"
+)
+
+// lastQuery returns the last query the fake server received
+func lastQuery(t *testing.T, server *fakebolt.Server) fakebolt.Query {
+	received := server.Received()
+	if len(received) == 0 {
+		t.Fatalf("Expected the server to receive a query, got none")
+	}
+	return received[len(received)-1]
+}
@@ -0,0 +1,20 @@
+package graph
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed migrations/*.cypher
+var embeddedMigrations embed.FS
+
+// Migrations holds the versioned Cypher files setting up the schema of the graph, run them with the migrate package
+var Migrations fs.FS = mustSub(embeddedMigrations, "migrations")
+
+func mustSub(fsys fs.FS, directory string) fs.FS {
+	sub, err := fs.Sub(fsys, directory)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,4 @@
+// Persons, projects and topics are looked up by name
+CREATE INDEX person_name IF NOT EXISTS FOR (pe:Person) ON (pe.name);
+CREATE INDEX project_name IF NOT EXISTS FOR (p:Project) ON (p.name);
+CREATE INDEX topic_name IF NOT EXISTS FOR (t:Topic) ON (t.name);
@@ -0,0 +1,18 @@
+package graph_test
+
+import (
+	"graphconnect/go-driver/graph"
+	"graphconnect/go-driver/migrate"
+	"testing"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := migrate.Load(graph.Migrations)
+
+	if err != nil {
+		t.Fatalf("Expected migrations to load, got: %v", err)
+	}
+	if len(migrations) == 0 || len(migrations[0].Schema) != 3 {
+		t.Errorf("Expected the first migration to create the 3 name indexes, got: %v", migrations)
+	}
+}
@@ -0,0 +1,168 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"graphconnect/go-driver/mapping"
+	"graphconnect/go-driver/neo4jerrors"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// nodes implements the operations shared by the repositories of the nodes labelled with label
+// Its exported methods are promoted to the repositories embedding it
+type nodes[T any] struct {
+	driver neo4j.DriverWithContext
+	config neo4j.SessionConfig
+	label  string
+}
+
+// Create creates a node, even if one with the same name already exists: Migrations only creates indexes
+// It returns an AlreadyExistsError when a uniqueness constraint created by other means forbids it
+func (n nodes[T]) Create(ctx context.Context, value T) error {
+	properties, err := mapping.Encode(value)
+	if err != nil {
+		return err
+	}
+	query := fmt.Sprintf("CREATE (n:%s) SET n = $properties", n.label)
+	_, err = n.write(ctx, query, map[string]any{"properties": properties})
+	if neo4jerrors.IsConstraintViolation(err) {
+		return &AlreadyExistsError{Label: n.label, Err: err}
+	}
+	return err
+}
+
+// Merge creates the node with the same name if it does not exist yet, and then updates its properties
+func (n nodes[T]) Merge(ctx context.Context, value T) error {
+	encoded, err := mapping.Encode(value)
+	if err != nil {
+		return err
+	}
+	properties, _ := encoded.(map[string]any)
+	name, ok := properties["name"].(string)
+	if !ok {
+		return fmt.Errorf("cannot merge %s without name", n.label)
+	}
+	query := fmt.Sprintf("MERGE (n:%s {name: $name}) SET n += $properties", n.label)
+	_, err = n.write(ctx, query, map[string]any{"name": name, "properties": properties})
+	return err
+}
+
+// FindByName returns the node with the given name, or an error wrapping ErrNotFound
+func (n nodes[T]) FindByName(ctx context.Context, name string) (T, error) {
+	query := fmt.Sprintf("MATCH (n:%s {name: $name}) RETURN n LIMIT 1", n.label)
+	values, err := read[T](ctx, n, query, map[string]any{"name": name})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if len(values) == 0 {
+		var zero T
+		return zero, fmt.Errorf("%s %q: %w", n.label, name, ErrNotFound)
+	}
+	return values[0], nil
+}
+
+// Delete deletes the node with the given name along with its relationships, or returns an error wrapping ErrNotFound
+func (n nodes[T]) Delete(ctx context.Context, name string) error {
+	query := fmt.Sprintf("MATCH (n:%s {name: $name}) DETACH DELETE n", n.label)
+	summary, err := n.write(ctx, query, map[string]any{"name": name})
+	if err != nil {
+		return err
+	}
+	if summary.Counters().NodesDeleted() == 0 {
+		return fmt.Errorf("%s %q: %w", n.label, name, ErrNotFound)
+	}
+	return nil
+}
+
+// relationship describes the relationships going from nodes of the from label to nodes of the to label
+type relationship struct {
+	from         string
+	relationType string
+	to           string
+}
+
+// link creates the relationship between the named nodes, unless it already exists
+// It returns an error wrapping ErrNotFound when one of the nodes does not exist
+func (n nodes[T]) link(ctx context.Context, rel relationship, from, to string) error {
+	query := fmt.Sprintf("MATCH (from:%s {name: $from}) MATCH (to:%s {name: $to}) MERGE (from)-[:%s]->(to) RETURN count(*) AS linked",
+		rel.from, rel.to, rel.relationType)
+	linked := 0
+	_, err := n.session(ctx, func(session neo4j.SessionWithContext) (any, error) {
+		return session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			result, err := tx.Run(ctx, query, map[string]any{"from": from, "to": to})
+			if err != nil {
+				return nil, err
+			}
+			linked, err = mapping.SingleAs[int](ctx, result)
+			return nil, err
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if linked == 0 {
+		return fmt.Errorf("%s %q or %s %q: %w", rel.from, from, rel.to, to, ErrNotFound)
+	}
+	return nil
+}
+
+// unlink deletes the relationship between the named nodes, or returns an error wrapping ErrNotFound
+func (n nodes[T]) unlink(ctx context.Context, rel relationship, from, to string) error {
+	query := fmt.Sprintf("MATCH (:%s {name: $from})-[r:%s]->(:%s {name: $to}) DELETE r",
+		rel.from, rel.relationType, rel.to)
+	summary, err := n.write(ctx, query, map[string]any{"from": from, "to": to})
+	if err != nil {
+		return err
+	}
+	if summary.Counters().RelationshipsDeleted() == 0 {
+		return fmt.Errorf("%s %q -[:%s]-> %s %q: %w", rel.from, from, rel.relationType, rel.to, to, ErrNotFound)
+	}
+	return nil
+}
+
+// write runs the query in a managed write transaction, the driver retries it on transient errors
+func (n nodes[T]) write(ctx context.Context, query string, parameters map[string]any) (neo4j.ResultSummary, error) {
+	summary, err := n.session(ctx, func(session neo4j.SessionWithContext) (any, error) {
+		return session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			result, err := tx.Run(ctx, query, parameters)
+			if err != nil {
+				return nil, err
+			}
+			return result.Consume(ctx)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary.(neo4j.ResultSummary), nil
+}
+
+// read runs the query in a managed read transaction and decodes all its records into V
+func read[V any, T any](ctx context.Context, n nodes[T], query string, parameters map[string]any) ([]V, error) {
+	values, err := n.session(ctx, func(session neo4j.SessionWithContext) (any, error) {
+		return session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			result, err := tx.Run(ctx, query, parameters)
+			if err != nil {
+				return nil, err
+			}
+			return mapping.CollectAs[V](ctx, result)
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return values.([]V), nil
+}
+
+// session runs the work in a new session, closed afterwards
+func (n nodes[T]) session(ctx context.Context, work func(neo4j.SessionWithContext) (any, error)) (result any, err error) {
+	session := n.driver.NewSession(ctx, n.config)
+	defer func() {
+		if closeErr := session.Close(ctx); err == nil && closeErr != nil {
+			err = closeErr
+		}
+	}()
+	return work(session)
+}
@@ -0,0 +1,29 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+var worksOn = relationship{from: "Person", relationType: "WORKS_ON", to: "Project"}
+
+// PersonRepository stores the (:Person) nodes and the projects they work on
+type PersonRepository struct {
+	nodes[Person]
+}
+
+// NewPersonRepository returns a repository running its queries with the driver
+func NewPersonRepository(driver neo4j.DriverWithContext, options ...Option) *PersonRepository {
+	return &PersonRepository{nodes[Person]{driver: driver, config: newSessionConfig(options), label: "Person"}}
+}
+
+// WorkOn makes the person work on the project, unless they already do
+func (repository *PersonRepository) WorkOn(ctx context.Context, person, project string) error {
+	return repository.link(ctx, worksOn, person, project)
+}
+
+// StopWorkingOn makes the person stop working on the project
+func (repository *PersonRepository) StopWorkingOn(ctx context.Context, person, project string) error {
+	return repository.unlink(ctx, worksOn, person, project)
+}
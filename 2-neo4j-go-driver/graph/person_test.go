@@ -0,0 +1,72 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"graphconnect/go-driver/graph"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/neo4jerrors"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+)
+
+const linkPersonQuery = "MATCH (from:Person {name: $from}) MATCH (to:Project {name: $to}) MERGE (from)-[:WORKS_ON]->(to) RETURN count(*) AS linked"
+const unlinkPersonQuery = "MATCH (:Person {name: $from})-[r:WORKS_ON]->(:Project {name: $to}) DELETE r"
+
+func TestPersonRepository(outer *testing.T) {
+	ctx := context.Background()
+
+	outer.Run("makes persons work on projects", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			linkPersonQuery:   {Keys: []string{"linked"}, Records: [][]any{{1}}, Counters: map[string]int{"relationships-created": 1}},
+			unlinkPersonQuery: {Counters: map[string]int{"relationships-deleted": 1}},
+		})
+		repository := graph.NewPersonRepository(driver)
+
+		if err := repository.WorkOn(ctx, "Florent", "Go Driver"); err != nil {
+			t.Fatalf("Expected Florent to work on the Go Driver, got: %v", err)
+		}
+		if expected := map[string]any{"from": "Florent", "to": "Go Driver"}; !reflect.DeepEqual(lastQuery(t, server).Params, expected) {
+			t.Errorf("Expected parameters %v, got: %v", expected, lastQuery(t, server).Params)
+		}
+		if err := repository.StopWorkingOn(ctx, "Florent", "Go Driver"); err != nil {
+			t.Errorf("Expected Florent to stop working on the Go Driver, got: %v", err)
+		}
+	})
+	outer.Run("reports missing persons, projects and relationships", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			linkPersonQuery:   {Keys: []string{"linked"}, Records: [][]any{{0}}},
+			unlinkPersonQuery: {},
+		})
+		repository := graph.NewPersonRepository(driver)
+
+		linkErr := repository.WorkOn(ctx, "Eric", "Unknown")
+		unlinkErr := repository.StopWorkingOn(ctx, "Eric", "Go Driver")
+
+		if expected := `Person "Eric" or Project "Unknown": not found`; linkErr == nil || linkErr.Error() != expected {
+			t.Errorf("Expected error %q, got: %v", expected, linkErr)
+		}
+		if !errors.Is(unlinkErr, graph.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, got: %v", unlinkErr)
+		}
+	})
+	outer.Run("reports failed writes", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"CREATE (n:Person) SET n = $properties": {Failure: &fakebolt.Failure{
+				Code:    "Neo.ClientError.Schema.ConstraintValidationFailed",
+				Message: "Node already exists with label `Person` and property `name` = 'Eric'",
+			}},
+		})
+		repository := graph.NewPersonRepository(driver)
+
+		err := repository.Create(ctx, graph.Person{Name: "Eric"})
+
+		if !errors.Is(err, graph.ErrAlreadyExists) {
+			t.Errorf("Expected ErrAlreadyExists, got: %v", err)
+		}
+		if !neo4jerrors.IsConstraintViolation(err) {
+			t.Errorf("Expected the constraint violation to be wrapped, got: %v", err)
+		}
+	})
+}
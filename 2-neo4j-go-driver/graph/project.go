@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+var relatesTo = relationship{from: "Project", relationType: "RELATES_TO", to: "Topic"}
+
+// ProjectRepository stores the (:Project) nodes and the topics they relate to
+type ProjectRepository struct {
+	nodes[Project]
+}
+
+// NewProjectRepository returns a repository running its queries with the driver
+func NewProjectRepository(driver neo4j.DriverWithContext, options ...Option) *ProjectRepository {
+	return &ProjectRepository{nodes[Project]{driver: driver, config: newSessionConfig(options), label: "Project"}}
+}
+
+// ListByMaintainerCount lists the projects at least one person works on, the most maintained first
+// Projects with the same number of maintainers are sorted by name
+func (repository *ProjectRepository) ListByMaintainerCount(ctx context.Context) ([]ProjectMaintainers, error) {
+	return read[ProjectMaintainers](ctx, repository.nodes,
+		"MATCH (p:Project)<-[:WORKS_ON]-(pe:Person) WITH p, count(pe) AS maintainers "+
+			"RETURN p AS project, maintainers ORDER BY maintainers DESC, p.name ASC", nil)
+}
+
+// RelateTo relates the project to the topic, unless it already is
+func (repository *ProjectRepository) RelateTo(ctx context.Context, project, topic string) error {
+	return repository.link(ctx, relatesTo, project, topic)
+}
+
+// Unrelate removes the relation between the project and the topic
+func (repository *ProjectRepository) Unrelate(ctx context.Context, project, topic string) error {
+	return repository.unlink(ctx, relatesTo, project, topic)
+}
@@ -0,0 +1,62 @@
+package graph_test
+
+import (
+	"context"
+	"graphconnect/go-driver/graph"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+)
+
+func TestProjectRepository(outer *testing.T) {
+	ctx := context.Background()
+
+	outer.Run("lists projects, sorted by maintainer count", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"MATCH (p:Project)<-[:WORKS_ON]-(pe:Person) WITH p, count(pe) AS maintainers " +
+				"RETURN p AS project, maintainers ORDER BY maintainers DESC, p.name ASC": {
+				Keys: []string{"project", "maintainers"},
+				Records: [][]any{
+					{fakebolt.Node{ID: 3, Labels: []string{"Project"}, Props: map[string]any{"name": "GoGM"}}, 2},
+					{fakebolt.Node{ID: 2, Labels: []string{"Project"}, Props: map[string]any{"name": "Go Driver"}}, 1},
+				},
+			},
+		})
+		repository := graph.NewProjectRepository(driver)
+
+		projects, err := repository.ListByMaintainerCount(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := []graph.ProjectMaintainers{
+			{Project: graph.Project{Name: "GoGM"}, Maintainers: 2},
+			{Project: graph.Project{Name: "Go Driver"}, Maintainers: 1},
+		}
+		if !reflect.DeepEqual(projects, expected) {
+			t.Errorf("Expected %v, got: %v", expected, projects)
+		}
+	})
+	outer.Run("relates projects to topics", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"MATCH (from:Project {name: $from}) MATCH (to:Topic {name: $to}) MERGE (from)-[:RELATES_TO]->(to) RETURN count(*) AS linked": {
+				Keys: []string{"linked"}, Records: [][]any{{1}},
+			},
+			"MATCH (:Project {name: $from})-[r:RELATES_TO]->(:Topic {name: $to}) DELETE r": {
+				Counters: map[string]int{"relationships-deleted": 1},
+			},
+		})
+		repository := graph.NewProjectRepository(driver, graph.WithDatabase("neo4j"))
+
+		if err := repository.RelateTo(ctx, "GoGM", "Neo4j"); err != nil {
+			t.Fatalf("Expected GoGM to relate to Neo4j, got: %v", err)
+		}
+		if err := repository.Unrelate(ctx, "GoGM", "Neo4j"); err != nil {
+			t.Errorf("Expected GoGM to no longer relate to Neo4j, got: %v", err)
+		}
+		if expected := map[string]any{"from": "GoGM", "to": "Neo4j"}; !reflect.DeepEqual(lastQuery(t, server).Params, expected) {
+			t.Errorf("Expected parameters %v, got: %v", expected, lastQuery(t, server).Params)
+		}
+	})
+}
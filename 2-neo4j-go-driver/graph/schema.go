@@ -0,0 +1,12 @@
+package graph
+
+import "graphconnect/go-driver/schema"
+
+// Schema declares the indexes the repositories rely on, Migrations creates them
+var Schema = schema.Schema{
+	Indexes: []schema.Index{
+		schema.NodeIndex("person_name", "Person", "name"),
+		schema.NodeIndex("project_name", "Project", "name"),
+		schema.NodeIndex("topic_name", "Topic", "name"),
+	},
+}
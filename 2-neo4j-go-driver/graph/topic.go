@@ -0,0 +1,15 @@
+package graph
+
+import (
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// TopicRepository stores the (:Topic) nodes, see ProjectRepository to relate projects to them
+type TopicRepository struct {
+	nodes[Topic]
+}
+
+// NewTopicRepository returns a repository running its queries with the driver
+func NewTopicRepository(driver neo4j.DriverWithContext, options ...Option) *TopicRepository {
+	return &TopicRepository{nodes[Topic]{driver: driver, config: newSessionConfig(options), label: "Topic"}}
+}
@@ -0,0 +1,75 @@
+package graph_test
+
+import (
+	"context"
+	"errors"
+	"graphconnect/go-driver/graph"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+)
+
+func TestTopicRepository(outer *testing.T) {
+	ctx := context.Background()
+
+	outer.Run("creates, finds and deletes topics", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"CREATE (n:Topic) SET n = $properties": {Counters: map[string]int{"nodes-created": 1, "labels-added": 1, "properties-set": 1}},
+			"MATCH (n:Topic {name: $name}) RETURN n LIMIT 1": {
+				Keys:    []string{"n"},
+				Records: [][]any{{fakebolt.Node{ID: 1, Labels: []string{"Topic"}, Props: map[string]any{"name": "Neo4j"}}}},
+			},
+			"MATCH (n:Topic {name: $name}) DETACH DELETE n": {Counters: map[string]int{"nodes-deleted": 1}},
+		})
+		repository := graph.NewTopicRepository(driver)
+
+		if err := repository.Create(ctx, graph.Topic{Name: "Neo4j"}); err != nil {
+			t.Fatalf("Expected topic to be created, got: %v", err)
+		}
+		if expected := map[string]any{"name": "Neo4j"}; !reflect.DeepEqual(lastQuery(t, server).Params["properties"], expected) {
+			t.Errorf("Expected properties %v, got: %v", expected, lastQuery(t, server).Params)
+		}
+		topic, err := repository.FindByName(ctx, "Neo4j")
+		if err != nil || topic.Name != "Neo4j" {
+			t.Errorf("Expected to find topic Neo4j, got: %v (%v)", topic, err)
+		}
+		if err := repository.Delete(ctx, "Neo4j"); err != nil {
+			t.Errorf("Expected topic to be deleted, got: %v", err)
+		}
+	})
+	outer.Run("merges topics by name", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"MERGE (n:Topic {name: $name}) SET n += $properties": {Counters: map[string]int{"properties-set": 1}},
+		})
+		repository := graph.NewTopicRepository(driver)
+
+		err := repository.Merge(ctx, graph.Topic{Name: "Go"})
+
+		if err != nil {
+			t.Fatalf("Expected topic to be merged, got: %v", err)
+		}
+		if name := lastQuery(t, server).Params["name"]; name != "Go" {
+			t.Errorf("Expected to merge on name Go, got: %v", name)
+		}
+	})
+	outer.Run("reports missing topics", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"MATCH (n:Topic {name: $name}) RETURN n LIMIT 1": {Keys: []string{"n"}},
+			"MATCH (n:Topic {name: $name}) DETACH DELETE n":  {},
+		})
+		repository := graph.NewTopicRepository(driver)
+
+		_, findErr := repository.FindByName(ctx, "Rust")
+		deleteErr := repository.Delete(ctx, "Rust")
+
+		for _, err := range []error{findErr, deleteErr} {
+			if !errors.Is(err, graph.ErrNotFound) {
+				t.Errorf("Expected ErrNotFound, got: %v", err)
+			}
+		}
+		if expected := `Topic "Rust": not found`; findErr == nil || findErr.Error() != expected {
+			t.Errorf("Expected error %q, got: %v", expected, findErr)
+		}
+	})
+}
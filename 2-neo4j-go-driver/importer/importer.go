@@ -0,0 +1,289 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"graphconnect/go-driver/summary"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	defaultBatchSize = 1000
+	defaultWorkers   = 4
+)
+
+// Importer writes the rows of a Source to the graph, as described by a Mapping
+// Rows are sent by batches, each batch being written in its own managed write transaction by one of the workers
+type Importer struct {
+	driver    neo4j.DriverWithContext
+	config    neo4j.SessionConfig
+	mapping   Mapping
+	batchSize int
+	workers   int
+	progress  func(Progress)
+}
+
+// Option customizes the Importer
+type Option func(*Importer)
+
+// WithDatabase imports into the given database, instead of the default one
+func WithDatabase(name string) Option {
+	return func(importer *Importer) {
+		importer.config.DatabaseName = name
+	}
+}
+
+// WithBatchSize sets the number of rows written per transaction, 1000 by default
+func WithBatchSize(size int) Option {
+	return func(importer *Importer) {
+		importer.batchSize = size
+	}
+}
+
+// WithWorkers sets the number of batches written concurrently, 4 by default
+// Concurrent batches merging the same nodes may deadlock, the driver then retries them
+func WithWorkers(workers int) Option {
+	return func(importer *Importer) {
+		importer.workers = workers
+	}
+}
+
+// WithProgress registers the function called after each batch, it is never called concurrently
+func WithProgress(progress func(Progress)) Option {
+	return func(importer *Importer) {
+		importer.progress = progress
+	}
+}
+
+// New returns an Importer writing rows with the driver
+func New(driver neo4j.DriverWithContext, mapping Mapping, options ...Option) *Importer {
+	importer := &Importer{
+		driver:    driver,
+		config:    neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite},
+		mapping:   mapping,
+		batchSize: defaultBatchSize,
+		workers:   defaultWorkers,
+	}
+	for _, option := range options {
+		option(importer)
+	}
+	if importer.batchSize < 1 {
+		importer.batchSize = 1
+	}
+	if importer.workers < 1 {
+		importer.workers = 1
+	}
+	return importer
+}
+
+// Progress counts the rows and batches written so far
+type Progress struct {
+	Rows          int
+	Batches       int
+	FailedRows    int
+	FailedBatches int
+	Elapsed       time.Duration
+}
+
+func (progress Progress) String() string {
+	return fmt.Sprintf("%d rows in %d batches, %d failed rows in %d batches, in %s",
+		progress.Rows, progress.Batches, progress.FailedRows, progress.FailedBatches, progress.Elapsed.Round(time.Millisecond))
+}
+
+// BatchFailure is a batch whose transaction failed, even after the retries of the driver
+type BatchFailure struct {
+	// Batch is the index of the batch, starting at 0
+	Batch int
+	// FirstRow and LastRow are the indexes of the first and last rows of the batch in the source, starting at 0
+	// The batch holds the rows in between, except the rejected ones
+	FirstRow int
+	LastRow  int
+	Rows     int
+	Err      error
+}
+
+func (failure BatchFailure) Error() string {
+	return fmt.Sprintf("batch %d (rows %d to %d): %v", failure.Batch, failure.FirstRow, failure.LastRow, failure.Err)
+}
+
+func (failure BatchFailure) Unwrap() error {
+	return failure.Err
+}
+
+// RowFailure is a row rejected before being written, e.g. because the key of one of its nodes is missing
+type RowFailure struct {
+	// Row is the index of the row in the source, starting at 0
+	Row int
+	Err error
+}
+
+func (failure RowFailure) Error() string {
+	return fmt.Sprintf("row %d: %v", failure.Row, failure.Err)
+}
+
+func (failure RowFailure) Unwrap() error {
+	return failure.Err
+}
+
+// Report summarizes an import
+type Report struct {
+	Progress
+	// Counters sums the summary counters of the written batches
+	Counters summary.Counters
+	// Failures lists the failed batches, by increasing index
+	Failures []BatchFailure
+	// Rejected lists the rows that were not written, by increasing index
+	Rejected []RowFailure
+}
+
+type batch struct {
+	index    int
+	firstRow int
+	lastRow  int
+	rows     []map[string]any
+}
+
+type batchResult struct {
+	batch    batch
+	counters summary.Counters
+	err      error
+}
+
+// Import writes all the rows of the source and reports what was written
+// Failed batches and rejected rows do not stop the import, they are listed in the report
+// The returned error is set when the source cannot be read, when the context is done, or when a session cannot be closed
+func (importer *Importer) Import(ctx context.Context, source Source) (Report, error) {
+	query, err := importer.mapping.Query()
+	if err != nil {
+		return Report{}, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	batches := make(chan batch)
+	results := make(chan batchResult)
+	closeErrs := make(chan error, importer.workers)
+	var workers sync.WaitGroup
+	for i := 0; i < importer.workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			closeErrs <- importer.work(ctx, query, batches, results)
+		}()
+	}
+	var rejected []RowFailure
+	readErr := make(chan error, 1)
+	go func() {
+		defer close(batches)
+		var err error
+		rejected, err = importer.read(ctx, source, batches)
+		readErr <- err
+	}()
+	go func() {
+		workers.Wait()
+		close(results)
+	}()
+
+	start := time.Now()
+	var report Report
+	for result := range results {
+		rows := len(result.batch.rows)
+		if result.err != nil {
+			report.FailedRows += rows
+			report.FailedBatches++
+			report.Failures = append(report.Failures, BatchFailure{
+				Batch:    result.batch.index,
+				FirstRow: result.batch.firstRow,
+				LastRow:  result.batch.lastRow,
+				Rows:     rows,
+				Err:      result.err,
+			})
+		} else {
+			report.Rows += rows
+			report.Batches++
+			report.Counters.Add(result.counters)
+		}
+		report.Elapsed = time.Since(start)
+		if importer.progress != nil {
+			importer.progress(report.Progress)
+		}
+	}
+	// batches complete in any order
+	sort.Slice(report.Failures, func(i, j int) bool {
+		return report.Failures[i].Batch < report.Failures[j].Batch
+	})
+	err = <-readErr
+	report.Rejected = rejected
+	close(closeErrs)
+	for closeErr := range closeErrs {
+		if err == nil && closeErr != nil {
+			err = fmt.Errorf("could not close session: %w", closeErr)
+		}
+	}
+	return report, err
+}
+
+// read sends the rows of the source by batches, until the end of the source or the context is done
+// The rows the mapping cannot merge are rejected instead, since a single one would fail its whole batch
+func (importer *Importer) read(ctx context.Context, source Source, batches chan<- batch) ([]RowFailure, error) {
+	var rejected []RowFailure
+	current := batch{}
+	send := func() bool {
+		select {
+		case batches <- current:
+			current = batch{index: current.index + 1}
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	for index := 0; ; index++ {
+		row, err := source.Next()
+		if err == io.EOF {
+			if len(current.rows) > 0 && !send() {
+				return rejected, ctx.Err()
+			}
+			return rejected, nil
+		}
+		if err != nil {
+			return rejected, fmt.Errorf("could not read row %d: %w", index, err)
+		}
+		if err := importer.mapping.check(row); err != nil {
+			rejected = append(rejected, RowFailure{Row: index, Err: err})
+			continue
+		}
+		if len(current.rows) == 0 {
+			current.firstRow = index
+		}
+		current.lastRow = index
+		current.rows = append(current.rows, row)
+		if len(current.rows) == importer.batchSize && !send() {
+			return rejected, ctx.Err()
+		}
+	}
+}
+
+// work writes the batches with a session of its own, since sessions cannot be used concurrently
+// It returns the error closing the session, the errors of the batches are sent with their results
+func (importer *Importer) work(ctx context.Context, query string, batches <-chan batch, results chan<- batchResult) error {
+	session := importer.driver.NewSession(ctx, importer.config)
+	for batch := range batches {
+		resultSummary, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			result, err := tx.Run(ctx, query, map[string]any{"rows": batch.rows})
+			if err != nil {
+				return nil, err
+			}
+			return result.Consume(ctx)
+		})
+		result := batchResult{batch: batch, err: err}
+		if err == nil {
+			result.counters = summary.CountersOf(resultSummary.(neo4j.ResultSummary).Counters())
+		}
+		results <- result
+	}
+	return session.Close(ctx)
+}
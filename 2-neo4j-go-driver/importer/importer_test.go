@@ -0,0 +1,121 @@
+package importer_test
+
+import (
+	"context"
+	"fmt"
+	"graphconnect/go-driver/importer"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/summary"
+	"graphconnect/neo4jtest/fakebolt"
+	"strings"
+	"testing"
+)
+
+func TestImporter(outer *testing.T) {
+	ctx := context.Background()
+	query, err := worksOn.Query()
+	if err != nil {
+		outer.Fatalf("Could not generate query: %v", err)
+	}
+
+	outer.Run("writes rows by batches", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			query: {Counters: map[string]int{"nodes-created": 2, "relationships-created": 1, "properties-set": 3}},
+		})
+		var progress []importer.Progress
+
+		report, err := importer.New(driver, worksOn,
+			importer.WithBatchSize(2),
+			importer.WithWorkers(3),
+			importer.WithProgress(func(current importer.Progress) {
+				progress = append(progress, current)
+			}),
+		).Import(ctx, jsonLines(5))
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if report.Rows != 5 || report.Batches != 3 || len(report.Failures) != 0 {
+			t.Errorf("Expected 5 rows in 3 batches, got: %v", report)
+		}
+		if expected := (summary.Counters{NodesCreated: 6, RelationshipsCreated: 3, PropertiesSet: 9}); report.Counters != expected {
+			t.Errorf("Expected counters %+v, got: %+v", expected, report.Counters)
+		}
+		if len(progress) != 3 || progress[2].Rows != 5 {
+			t.Errorf("Expected progress after each of the 3 batches, got: %v", progress)
+		}
+		rows := 0
+		for _, received := range server.Received() {
+			batch, _ := received.Params["rows"].([]any)
+			rows += len(batch)
+		}
+		if rows != 5 {
+			t.Errorf("Expected the server to receive 5 rows, got: %d", rows)
+		}
+	})
+	outer.Run("reports failed batches", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			query: {Failure: &fakebolt.Failure{
+				Code:    "Neo.ClientError.Statement.SemanticError",
+				Message: "Cannot merge the following node because of null property value for 'name'",
+			}},
+		})
+
+		report, err := importer.New(driver, worksOn, importer.WithBatchSize(2)).Import(ctx, jsonLines(3))
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if report.FailedRows != 3 || len(report.Failures) != 2 {
+			t.Fatalf("Expected 3 failed rows in 2 batches, got: %v", report)
+		}
+		failure := report.Failures[1]
+		if !strings.HasPrefix(failure.Error(), "batch 1 (rows 2 to 2): ") {
+			t.Errorf("Expected the second batch to hold the third row, got: %v", failure)
+		}
+	})
+	outer.Run("rejects rows without key", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			query: {Counters: map[string]int{"nodes-created": 2, "relationships-created": 1, "properties-set": 3}},
+		})
+		source := importer.NewCSVSource(strings.NewReader("person,project,since\nEric,GoGM,2019\n,GoGM,2020\nFlorent,Go Driver,2021\n"))
+
+		report, err := importer.New(driver, worksOn, importer.WithBatchSize(2)).Import(ctx, source)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if report.Rows != 2 || report.Batches != 1 || len(report.Failures) != 0 {
+			t.Errorf("Expected the 2 other rows to be written in 1 batch, got: %v", report)
+		}
+		expected := `row 1: column "person" holding the key of node Person is empty`
+		if len(report.Rejected) != 1 || report.Rejected[0].Error() != expected {
+			t.Errorf("Expected rejection %q, got: %v", expected, report.Rejected)
+		}
+		if received := server.Received(); len(received) != 1 {
+			t.Errorf("Expected a single batch to be sent, got: %v", received)
+		}
+	})
+	outer.Run("stops on source errors", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{query: {}})
+		source := importer.NewJSONLinesSource(strings.NewReader("{\"person\": \"Eric\", \"project\": \"GoGM\"}\nnot json\n"))
+
+		report, err := importer.New(driver, worksOn).Import(ctx, source)
+
+		if err == nil || !strings.HasPrefix(err.Error(), "could not read row 1: line 2: ") {
+			t.Errorf("Expected row 1 to be unreadable, got: %v", err)
+		}
+		if report.Rows != 0 {
+			t.Errorf("Expected no row to be written, got: %v", report)
+		}
+	})
+}
+
+// jsonLines returns a source of count rows
+func jsonLines(count int) importer.Source {
+	var lines strings.Builder
+	for i := 0; i < count; i++ {
+		fmt.Fprintf(&lines, "{\"person\": \"Person %d\", \"project\": \"GoGM\", \"since\": %d}\n", i, 2000+i)
+	}
+	return importer.NewJSONLinesSource(strings.NewReader(lines.String()))
+}
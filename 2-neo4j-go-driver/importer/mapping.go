@@ -0,0 +1,126 @@
+// Package importer loads large CSV or JSON Lines files into the graph, by batches of rows
+package importer
+
+import (
+	"fmt"
+	"graphconnect/go-driver/cypher"
+	"sort"
+	"strings"
+)
+
+// Mapping describes the nodes and relationships each row creates or updates
+//
+//	importer.Mapping{
+//		Nodes: []importer.NodeMapping{
+//			{Label: "Person", Key: "name", Properties: map[string]string{"name": "person"}},
+//			{Label: "Project", Key: "name", Properties: map[string]string{"name": "project"}},
+//		},
+//		Relationships: []importer.RelationshipMapping{{Type: "WORKS_ON", From: "Person", To: "Project"}},
+//	}
+type Mapping struct {
+	Nodes         []NodeMapping
+	Relationships []RelationshipMapping
+}
+
+// NodeMapping maps the columns of a row to a node, merged on its key property
+type NodeMapping struct {
+	// Name identifies the node in the relationship mappings, it defaults to the label
+	Name  string
+	Label string
+	// Key is the property the node is merged on, it must be mapped in Properties
+	Key string
+	// Properties maps the properties of the node to the columns holding their value
+	Properties map[string]string
+}
+
+// RelationshipMapping maps the columns of a row to a relationship, merged between the nodes of the row
+type RelationshipMapping struct {
+	Type string
+	// From and To are the names of node mappings, see NodeMapping.Name
+	From string
+	To   string
+	// Properties maps the properties of the relationship to the columns holding their value
+	Properties map[string]string
+}
+
+// Query returns the query writing a batch of rows, sent as the $rows parameter
+func (mapping Mapping) Query() (string, error) {
+	if len(mapping.Nodes) == 0 {
+		return "", fmt.Errorf("mapping has no node")
+	}
+	variables := map[string]string{}
+	lines := []string{"UNWIND $rows AS row"}
+	for i, node := range mapping.Nodes {
+		name := node.name()
+		if _, found := variables[name]; found {
+			return "", fmt.Errorf("node mappings share the name %s", name)
+		}
+		column, found := node.Properties[node.Key]
+		if node.Key == "" || !found {
+			return "", fmt.Errorf("key of node %s must be one of its properties", name)
+		}
+		variable := fmt.Sprintf("n%d", i)
+		variables[name] = variable
+		lines = append(lines, fmt.Sprintf("MERGE (%s:%s {%s: row[%s]})", variable, cypher.Quote(node.Label), cypher.Quote(node.Key), quoteString(column)))
+		if set := setClause(variable, node.Properties, node.Key); set != "" {
+			lines = append(lines, set)
+		}
+	}
+	for i, relationship := range mapping.Relationships {
+		from, fromFound := variables[relationship.From]
+		to, toFound := variables[relationship.To]
+		if !fromFound || !toFound {
+			return "", fmt.Errorf("relationship %s must go from and to mapped nodes, got %q and %q",
+				relationship.Type, relationship.From, relationship.To)
+		}
+		variable := fmt.Sprintf("r%d", i)
+		lines = append(lines, fmt.Sprintf("MERGE (%s)-[%s:%s]->(%s)", from, variable, cypher.Quote(relationship.Type), to))
+		if set := setClause(variable, relationship.Properties, ""); set != "" {
+			lines = append(lines, set)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// check returns an error when the row lacks the key of a node, MERGE fails on null keys
+// Empty strings are rejected too, as CSV files cannot tell them from missing values
+func (mapping Mapping) check(row map[string]any) error {
+	for _, node := range mapping.Nodes {
+		column := node.Properties[node.Key]
+		if value, found := row[column]; !found || value == nil || value == "" {
+			return fmt.Errorf("column %q holding the key of node %s is empty", column, node.name())
+		}
+	}
+	return nil
+}
+
+func (node NodeMapping) name() string {
+	if node.Name == "" {
+		return node.Label
+	}
+	return node.Name
+}
+
+// setClause sets the mapped properties, except the key one, in the order of their names
+func setClause(variable string, properties map[string]string, key string) string {
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		if name != key {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	assignments := make([]string, len(names))
+	for i, name := range names {
+		assignments[i] = fmt.Sprintf("%s.%s = row[%s]", variable, cypher.Quote(name), quoteString(properties[name]))
+	}
+	return "SET " + strings.Join(assignments, ", ")
+}
+
+// quoteString returns the Cypher string literal of the column name
+func quoteString(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
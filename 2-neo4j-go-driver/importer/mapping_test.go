@@ -0,0 +1,56 @@
+package importer_test
+
+import (
+	"graphconnect/go-driver/importer"
+	"testing"
+)
+
+// worksOn maps rows such as {"person": "Eric", "project": "GoGM", "since": 2021} to the small graph
+var worksOn = importer.Mapping{
+	Nodes: []importer.NodeMapping{
+		{Label: "Person", Key: "name", Properties: map[string]string{"name": "person"}},
+		{Label: "Project", Key: "name", Properties: map[string]string{"name": "project", "full name": "project's full name"}},
+	},
+	Relationships: []importer.RelationshipMapping{
+		{Type: "WORKS_ON", From: "Person", To: "Project", Properties: map[string]string{"since": "since"}},
+	},
+}
+
+func TestMapping(outer *testing.T) {
+	outer.Run("generates the batch query", func(t *testing.T) {
+		query, err := worksOn.Query()
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := `UNWIND $rows AS row
+MERGE (n0:Person {name: row['person']})
+MERGE (n1:Project {name: row['project']})
+SET n1.` + "`full name`" + ` = row['project\'s full name']
+MERGE (n0)-[r0:WORKS_ON]->(n1)
+SET r0.since = row['since']`
+		if query != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, query)
+		}
+	})
+	outer.Run("rejects invalid mappings", func(t *testing.T) {
+		for _, testCase := range []struct {
+			mapping  importer.Mapping
+			expected string
+		}{
+			{importer.Mapping{}, "mapping has no node"},
+			{importer.Mapping{Nodes: []importer.NodeMapping{{Label: "Person", Key: "name"}}},
+				"key of node Person must be one of its properties"},
+			{importer.Mapping{
+				Nodes:         []importer.NodeMapping{{Label: "Person", Key: "name", Properties: map[string]string{"name": "person"}}},
+				Relationships: []importer.RelationshipMapping{{Type: "WORKS_ON", From: "Person", To: "Project"}},
+			}, `relationship WORKS_ON must go from and to mapped nodes, got "Person" and "Project"`},
+		} {
+			_, err := testCase.mapping.Query()
+
+			if err == nil || err.Error() != testCase.expected {
+				t.Errorf("Expected error %q, got: %v", testCase.expected, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Source reads rows one at a time, Next returns io.EOF after the last row
+type Source interface {
+	Next() (map[string]any, error)
+}
+
+// CSVSource reads the rows of a CSV file whose first line names the columns
+// Values are strings, empty values are null unless a converter is registered for their column
+type CSVSource struct {
+	reader     *csv.Reader
+	header     []string
+	converters map[string]func(string) (any, error)
+}
+
+// NewCSVSource returns a Source reading the CSV content, see CSVSource
+func NewCSVSource(reader io.Reader) *CSVSource {
+	csvReader := csv.NewReader(reader)
+	csvReader.ReuseRecord = true
+	return &CSVSource{reader: csvReader, converters: map[string]func(string) (any, error){}}
+}
+
+// Convert registers the function converting the values of the column, e.g. strconv.Atoi wrapped to return an int64
+func (source *CSVSource) Convert(column string, converter func(string) (any, error)) *CSVSource {
+	source.converters[column] = converter
+	return source
+}
+
+func (source *CSVSource) Next() (map[string]any, error) {
+	if source.header == nil {
+		header, err := source.reader.Read()
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read CSV header: %w", err)
+		}
+		source.header = append([]string(nil), header...)
+	}
+	record, err := source.reader.Read()
+	if err != nil {
+		return nil, err
+	}
+	row := make(map[string]any, len(source.header))
+	for i, column := range source.header {
+		converter, found := source.converters[column]
+		switch {
+		case found:
+			line, _ := source.reader.FieldPos(i)
+			value, err := converter(record[i])
+			if err != nil {
+				return nil, fmt.Errorf("line %d, column %q: %w", line, column, err)
+			}
+			row[column] = value
+		case record[i] == "":
+			row[column] = nil
+		default:
+			row[column] = record[i]
+		}
+	}
+	return row, nil
+}
+
+// JSONLinesSource reads the rows of a JSON Lines file, i.e. a JSON object per line
+// Integral numbers are read as int64, other numbers as float64
+type JSONLinesSource struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewJSONLinesSource returns a Source reading the JSON Lines content, see JSONLinesSource
+func NewJSONLinesSource(reader io.Reader) *JSONLinesSource {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 16*1024*1024)
+	return &JSONLinesSource{scanner: scanner}
+}
+
+func (source *JSONLinesSource) Next() (map[string]any, error) {
+	for source.scanner.Scan() {
+		source.line++
+		line := bytes.TrimSpace(source.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		decoder := json.NewDecoder(bytes.NewReader(line))
+		decoder.UseNumber()
+		var row map[string]any
+		if err := decoder.Decode(&row); err != nil {
+			return nil, fmt.Errorf("line %d: %w", source.line, err)
+		}
+		for column, value := range row {
+			row[column] = convertNumbers(value)
+		}
+		return row, nil
+	}
+	if err := source.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// convertNumbers replaces the json.Number values with the numbers the driver accepts
+func convertNumbers(value any) any {
+	switch value := value.(type) {
+	case json.Number:
+		if integer, err := value.Int64(); err == nil {
+			return integer
+		}
+		float, _ := value.Float64()
+		return float
+	case []any:
+		for i, element := range value {
+			value[i] = convertNumbers(element)
+		}
+	case map[string]any:
+		for key, element := range value {
+			value[key] = convertNumbers(element)
+		}
+	}
+	return value
+}
@@ -0,0 +1,83 @@
+package importer_test
+
+import (
+	"graphconnect/go-driver/importer"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSources(outer *testing.T) {
+	outer.Run("reads CSV rows", func(t *testing.T) {
+		source := importer.NewCSVSource(strings.NewReader("person,project,since\nEric,GoGM,2021\nFlorent,,2020\n")).
+			Convert("since", func(value string) (any, error) {
+				return strconv.ParseInt(value, 10, 64)
+			})
+
+		rows := readAll(t, source)
+
+		expected := []map[string]any{
+			{"person": "Eric", "project": "GoGM", "since": int64(2021)},
+			{"person": "Florent", "project": nil, "since": int64(2020)},
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Errorf("Expected %v, got: %v", expected, rows)
+		}
+	})
+	outer.Run("reports CSV conversion errors", func(t *testing.T) {
+		source := importer.NewCSVSource(strings.NewReader("person,since\nEric,2021\nNikita,soon\n")).
+			Convert("since", func(value string) (any, error) {
+				return strconv.ParseInt(value, 10, 64)
+			})
+
+		_, _ = source.Next()
+		_, err := source.Next()
+
+		expected := `line 3, column "since": strconv.ParseInt: parsing "soon": invalid syntax`
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got: %v", expected, err)
+		}
+	})
+	outer.Run("reads JSON Lines rows", func(t *testing.T) {
+		source := importer.NewJSONLinesSource(strings.NewReader(`{"person": "Eric", "since": 2021, "score": 4.5}
+
+{"person": "Nikita", "tags": [1, "go"]}
+`))
+
+		rows := readAll(t, source)
+
+		expected := []map[string]any{
+			{"person": "Eric", "since": int64(2021), "score": 4.5},
+			{"person": "Nikita", "tags": []any{int64(1), "go"}},
+		}
+		if !reflect.DeepEqual(rows, expected) {
+			t.Errorf("Expected %v, got: %v", expected, rows)
+		}
+	})
+	outer.Run("reports invalid JSON lines", func(t *testing.T) {
+		source := importer.NewJSONLinesSource(strings.NewReader("{\"person\": \"Eric\"}\n[1, 2]\n"))
+
+		_, _ = source.Next()
+		_, err := source.Next()
+
+		if err == nil || !strings.HasPrefix(err.Error(), "line 2: ") {
+			t.Errorf("Expected error on line 2, got: %v", err)
+		}
+	})
+}
+
+func readAll(t *testing.T, source importer.Source) []map[string]any {
+	var rows []map[string]any
+	for {
+		row, err := source.Next()
+		if err == io.EOF {
+			return rows
+		}
+		if err != nil {
+			t.Fatalf("Could not read row: %v", err)
+		}
+		rows = append(rows, row)
+	}
+}
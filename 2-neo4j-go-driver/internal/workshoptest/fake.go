@@ -0,0 +1,43 @@
+package workshoptest
+
+import (
+	"context"
+	"graphconnect/neo4jtest/fakebolt"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// StartFakeServer starts a fake server answering the scripted queries, and a 5.x driver connected to it
+// Both are closed at the end of the test
+func StartFakeServer(t testing.TB, script fakebolt.Script) (*fakebolt.Server, neo4j.DriverWithContext) {
+	t.Helper()
+	server, err := fakebolt.Start(script)
+	if err != nil {
+		t.Fatalf("Could not start fake server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := server.Close(); err != nil {
+			t.Errorf("Could not stop fake server: %v", err)
+		}
+	})
+	driver, err := neo4j.NewDriverWithContext(server.BoltURI(), neo4j.NoAuth())
+	if err != nil {
+		t.Fatalf("Could not create driver: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := driver.Close(context.Background()); err != nil {
+			t.Errorf("Could not close driver: %v", err)
+		}
+	})
+	return server, driver
+}
+
+// ReceivedQueries returns the text of the queries the fake server received, in order
+func ReceivedQueries(server *fakebolt.Server) []string {
+	var queries []string
+	for _, query := range server.Received() {
+		queries = append(queries, query.Text)
+	}
+	return queries
+}
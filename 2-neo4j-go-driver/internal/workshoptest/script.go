@@ -0,0 +1,91 @@
+// Package workshoptest holds the test helpers and data shared by the packages of the module, and by the 4.x and 5.x driver lessons
+package workshoptest
+
+import "graphconnect/neo4jtest/fakebolt"
+
+// Script answers the queries of the exercises like a Neo4j server holding the small graph would
+// The queries sent by the 4.x and 5.x drivers are the same, so are their answers
+var Script = fakebolt.Script{
+	"CALL { RETURN 42 AS answer } IN TRANSACTIONS RETURN answer": {
+		Keys:    []string{"answer"},
+		Records: [][]any{{42}},
+	},
+	"CALL { RETURN 42 AS answer } IN TRANSACTIONS RETURN answer AS count": {
+		Keys:    []string{"count"},
+		Records: [][]any{{42}},
+	},
+	"MATCH (p:Person) WHERE exists(p.name) RETURN count(p) AS count": {
+		Keys:    []string{"count"},
+		Records: [][]any{{0}},
+	},
+	"MATCH (p:Person) WHERE p.name IS NOT NULL RETURN count(p) AS count": {
+		Keys:    []string{"count"},
+		Records: [][]any{{0}},
+	},
+	"RETURN REDUCE(sum=0, power IN $powersOfTwo | sum+power) AS answer": {
+		Keys:    []string{"answer"},
+		Records: [][]any{{42}},
+	},
+	"MATCH (p:Person)-[:WORKS_ON]->(:Project) RETURN p ORDER BY p.name ASC": {
+		Keys: []string{"p"},
+		Records: [][]any{
+			{personNode(5, "Eric")},
+			{personNode(7, "Florent")},
+			{personNode(6, "Nikita")},
+		},
+	},
+	"MATCH (p:Project)<-[:WORKS_ON]-(pe:Person) WITH p, size(collect(pe)) AS count RETURN p ORDER BY count DESC": {
+		Keys: []string{"p"},
+		Records: [][]any{
+			{projectNode(3, "GoGM")},
+			{projectNode(2, "Go Driver")},
+		},
+	},
+	// the queries of the migrate package, against a database without migrations
+	"MATCH (m:__Migration) " +
+		"RETURN m.version AS version, m.description AS description, m.checksum AS checksum, " +
+		"m.appliedAt IS NOT NULL AS applied, m.appliedAt AS appliedAt " +
+		"ORDER BY m.version": {
+		Keys: []string{"version", "description", "checksum", "applied", "appliedAt"},
+	},
+	"CREATE CONSTRAINT __migration_version IF NOT EXISTS FOR (m:__Migration) REQUIRE m.version IS UNIQUE": {
+		Counters: map[string]int{"constraints-added": 1},
+	},
+	"CREATE (m:__Migration {version: $version, description: $description, checksum: $checksum, startedAt: datetime()})": {
+		Counters: map[string]int{"nodes-created": 1, "labels-added": 1, "properties-set": 4},
+	},
+	"MATCH (m:__Migration {version: $version}) SET m.appliedAt = datetime()": {
+		Counters: map[string]int{"properties-set": 1},
+	},
+	// the statements of graph/migrations
+	"CREATE INDEX person_name IF NOT EXISTS FOR (pe:Person) ON (pe.name)": {Counters: map[string]int{"indexes-added": 1}},
+	"CREATE INDEX project_name IF NOT EXISTS FOR (p:Project) ON (p.name)": {Counters: map[string]int{"indexes-added": 1}},
+	"CREATE INDEX topic_name IF NOT EXISTS FOR (t:Topic) ON (t.name)":     {Counters: map[string]int{"indexes-added": 1}},
+	// the statements of neo4jtest/fixtures/small_graph.cypher
+	`
+MERGE (neo4j:Topic {name: "Neo4j"})
+MERGE (goDriver:Project {name: "Go Driver"})
+MERGE (gogm:Project {name: "GoGM"})
+MERGE (album:MusicProject {name: "TBD"})
+MERGE (eric:Person {name: "Eric"})
+MERGE (nikita:Person {name: "Nikita"})
+MERGE (florent:Person {name: "Florent"})
+MERGE (john:Person {name: "John"})
+MERGE (gogm)-[:RELATES_TO]->(neo4j)
+MERGE (eric)-[:WORKS_ON]->(gogm)
+MERGE (nikita)-[:WORKS_ON]->(gogm)
+MERGE (goDriver)-[:RELATES_TO]->(neo4j)
+MERGE (florent)-[:WORKS_ON]->(goDriver)
+MERGE (john)-[:WORKS_ON]->(album)
+`: {
+		Counters: map[string]int{"nodes-created": 8, "labels-added": 8, "properties-set": 8, "relationships-created": 6},
+	},
+}
+
+func personNode(id int64, name string) fakebolt.Node {
+	return fakebolt.Node{ID: id, Labels: []string{"Person"}, Props: map[string]any{"name": name}}
+}
+
+func projectNode(id int64, name string) fakebolt.Node {
+	return fakebolt.Node{ID: id, Labels: []string{"Project"}, Props: map[string]any{"name": name}}
+}
@@ -0,0 +1,93 @@
+package mapping
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// CollectAs decodes all the remaining records of the result, see Decode
+func CollectAs[T any](ctx context.Context, result neo4j.ResultWithContext) ([]T, error) {
+	var values []T
+	for result.Next(ctx) {
+		value, err := Decode[T](result.Record())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// SingleAs decodes the only record of the result, it fails when there are no or several records, see Decode
+func SingleAs[T any](ctx context.Context, result neo4j.ResultWithContext) (T, error) {
+	record, err := result.Single(ctx)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return Decode[T](record)
+}
+
+// Iterator decodes the records of a result one at a time
+//
+//	iterator := mapping.Iterate[Person](ctx, result)
+//	for iterator.Next() {
+//		fmt.Println(iterator.Value().Name)
+//	}
+//	return iterator.Err()
+//
+// Records are only pulled from the server when the iterator needs them, by batches of the configured fetch size
+// A slow consumer therefore holds the server back instead of accumulating records in memory
+type Iterator[T any] struct {
+	ctx context.Context
+	// next moves to the next record and returns its keys and values, or the error ending the result
+	next  func(ctx context.Context) (keys []string, values []any, found bool, err error)
+	value T
+	err   error
+}
+
+// Iterate returns an iterator over the remaining records of the result
+// The iteration stops when the context is cancelled, Err then returns the context error
+func Iterate[T any](ctx context.Context, result neo4j.ResultWithContext) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, next: func(ctx context.Context) ([]string, []any, bool, error) {
+		if !result.Next(ctx) {
+			return nil, nil, false, result.Err()
+		}
+		record := result.Record()
+		return record.Keys, record.Values, true, nil
+	}}
+}
+
+// Next decodes the next record and reports whether there was one
+// It returns false at the end of the result or at the first error
+func (iterator *Iterator[T]) Next() bool {
+	if iterator.err != nil {
+		return false
+	}
+	if err := iterator.ctx.Err(); err != nil {
+		iterator.err = fmt.Errorf("iteration stopped: %w", err)
+		return false
+	}
+	keys, values, found, err := iterator.next(iterator.ctx)
+	if !found {
+		iterator.err = err
+		return false
+	}
+	iterator.value, iterator.err = decodeRecord[T](keys, values)
+	return iterator.err == nil
+}
+
+// Value returns the record decoded by the last call to Next
+func (iterator *Iterator[T]) Value() T {
+	return iterator.value
+}
+
+// Err returns the error that stopped the iteration, if any
+func (iterator *Iterator[T]) Err() error {
+	return iterator.err
+}
@@ -0,0 +1,144 @@
+package mapping_test
+
+import (
+	"context"
+	"errors"
+	"graphconnect/go-driver/mapping"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestCollect(outer *testing.T) {
+	outer.Run("collects typed values", func(t *testing.T) {
+		result := &fakeResult{records: []*neo4j.Record{record("name", "Eric"), record("name", "Nikita")}}
+
+		names, err := mapping.CollectAs[string](context.Background(), result)
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if expected := []string{"Eric", "Nikita"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v, got: %v", expected, names)
+		}
+	})
+	outer.Run("reports result errors when collecting", func(t *testing.T) {
+		failure := errors.New("connection lost")
+		result := &fakeResult{records: []*neo4j.Record{record("name", "Eric")}, err: failure}
+
+		_, err := mapping.CollectAs[string](context.Background(), result)
+
+		if !errors.Is(err, failure) {
+			t.Errorf("Expected %v, got: %v", failure, err)
+		}
+	})
+	outer.Run("decodes single values", func(t *testing.T) {
+		result := &fakeResult{records: []*neo4j.Record{record("answer", int64(42))}}
+
+		answer, err := mapping.SingleAs[int](context.Background(), result)
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if answer != 42 {
+			t.Errorf("Expected 42, got: %v", answer)
+		}
+	})
+	outer.Run("requires a single record", func(t *testing.T) {
+		result := &fakeResult{records: []*neo4j.Record{record("answer", int64(42)), record("answer", int64(43))}}
+
+		_, err := mapping.SingleAs[int](context.Background(), result)
+
+		if err == nil {
+			t.Errorf("Expected error, got nil")
+		}
+	})
+	outer.Run("iterates over typed values", func(t *testing.T) {
+		result := &fakeResult{records: []*neo4j.Record{record("answer", int64(41)), record("answer", int64(42))}}
+		iterator := mapping.Iterate[int](context.Background(), result)
+
+		var answers []int
+		for iterator.Next() {
+			answers = append(answers, iterator.Value())
+		}
+
+		if err := iterator.Err(); err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if expected := []int{41, 42}; !reflect.DeepEqual(answers, expected) {
+			t.Errorf("Expected %v, got: %v", expected, answers)
+		}
+	})
+	outer.Run("pulls records only when needed", func(t *testing.T) {
+		result := &fakeResult{records: []*neo4j.Record{record("answer", int64(41)), record("answer", int64(42))}}
+		iterator := mapping.Iterate[int](context.Background(), result)
+
+		iterator.Next()
+
+		if result.index != 1 {
+			t.Errorf("Expected 1 record pulled, got: %d", result.index)
+		}
+	})
+	outer.Run("stops iterating when the context is cancelled", func(t *testing.T) {
+		result := &fakeResult{records: []*neo4j.Record{record("answer", int64(41)), record("answer", int64(42))}}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		iterator := mapping.Iterate[int](ctx, result)
+
+		var answers []int
+		for iterator.Next() {
+			answers = append(answers, iterator.Value())
+			cancel()
+		}
+
+		if err := iterator.Err(); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected %v, got: %v", context.Canceled, err)
+		}
+		if expected := []int{41}; !reflect.DeepEqual(answers, expected) {
+			t.Errorf("Expected %v, got: %v", expected, answers)
+		}
+	})
+	outer.Run("stops iterating at the first decoding error", func(t *testing.T) {
+		result := &fakeResult{records: []*neo4j.Record{record("answer", "forty-two"), record("answer", int64(42))}}
+		iterator := mapping.Iterate[int](context.Background(), result)
+
+		if iterator.Next() || iterator.Next() {
+			t.Errorf("Expected iteration to stop, got: %v", iterator.Value())
+		}
+		if iterator.Err() == nil {
+			t.Errorf("Expected decoding error, got nil")
+		}
+	})
+}
+
+// fakeResult streams the given records, then fails with err if set
+type fakeResult struct {
+	neo4j.ResultWithContext
+	records []*neo4j.Record
+	index   int
+	err     error
+}
+
+func (result *fakeResult) Next(context.Context) bool {
+	if result.index >= len(result.records) {
+		return false
+	}
+	result.index++
+	return true
+}
+
+func (result *fakeResult) Err() error {
+	return result.err
+}
+
+func (result *fakeResult) Record() *neo4j.Record {
+	return result.records[result.index-1]
+}
+
+func (result *fakeResult) Single(context.Context) (*neo4j.Record, error) {
+	if len(result.records) != 1 {
+		return nil, errors.New("expected a single record")
+	}
+	return result.records[0], nil
+}
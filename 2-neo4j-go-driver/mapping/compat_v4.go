@@ -0,0 +1,69 @@
+package mapping
+
+import (
+	"context"
+	"fmt"
+
+	neo4j4 "github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// The functions below are the 4.x driver counterparts of Decode, CollectAs, SingleAs and Iterate
+// They keep the 4.x lessons working, 4.x results do not take a context
+
+// DecodeV4 converts a record of the 4.x driver to a T, see Decode
+func DecodeV4[T any](record *neo4j4.Record) (T, error) {
+	if record == nil {
+		var result T
+		return result, fmt.Errorf("cannot decode nil record into %T", result)
+	}
+	return decodeRecord[T](record.Keys, record.Values)
+}
+
+// CollectAsV4 decodes all the remaining records of a 4.x driver result, see CollectAs
+func CollectAsV4[T any](result neo4j4.Result) ([]T, error) {
+	var values []T
+	for result.Next() {
+		value, err := DecodeV4[T](result.Record())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if err := result.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// SingleAsV4 decodes the only record of a 4.x driver result, see SingleAs
+func SingleAsV4[T any](result neo4j4.Result) (T, error) {
+	record, err := result.Single()
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return DecodeV4[T](record)
+}
+
+// IterateV4 returns an iterator over the remaining records of a 4.x driver result, see Iterate
+// The context is only checked between records since 4.x results do not take one
+func IterateV4[T any](ctx context.Context, result neo4j4.Result) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, next: func(context.Context) ([]string, []any, bool, error) {
+		if !result.Next() {
+			return nil, nil, false, result.Err()
+		}
+		record := result.Record()
+		return record.Keys, record.Values, true, nil
+	}}
+}
+
+// propertiesOfV4 returns the properties of the nodes and relationships of the 4.x driver
+func propertiesOfV4(value any) (map[string]any, bool) {
+	switch entity := value.(type) {
+	case neo4j4.Node:
+		return entity.Props, true
+	case neo4j4.Relationship:
+		return entity.Props, true
+	}
+	return nil, false
+}
@@ -0,0 +1,68 @@
+package mapping_test
+
+import (
+	"context"
+	"graphconnect/go-driver/mapping"
+	"reflect"
+	"testing"
+
+	neo4j4 "github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestCompatV4(outer *testing.T) {
+	outer.Run("decodes 4.x nodes", func(t *testing.T) {
+		node := neo4j4.Node{Id: 1, Labels: []string{"Project"}, Props: map[string]any{"name": "GoGM", "maintainers": int64(2)}}
+
+		project, err := mapping.DecodeV4[Project](&neo4j4.Record{Keys: []string{"p"}, Values: []any{node}})
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if expected := (Project{Name: "GoGM", Maintainers: 2}); project != expected {
+			t.Errorf("Expected %v, got: %v", expected, project)
+		}
+	})
+	outer.Run("collects and iterates over 4.x results", func(t *testing.T) {
+		records := func() *fakeResultV4 {
+			return &fakeResultV4{results: &fakeResult{records: []*neo4j.Record{record("answer", int64(41)), record("answer", int64(42))}}}
+		}
+
+		collected, err := mapping.CollectAsV4[int](records())
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		var iterated []int
+		iterator := mapping.IterateV4[int](context.Background(), records())
+		for iterator.Next() {
+			iterated = append(iterated, iterator.Value())
+		}
+
+		if err := iterator.Err(); err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		expected := []int{41, 42}
+		if !reflect.DeepEqual(collected, expected) || !reflect.DeepEqual(iterated, expected) {
+			t.Errorf("Expected %v, got: %v and %v", expected, collected, iterated)
+		}
+	})
+}
+
+// fakeResultV4 streams the records of a fakeResult through the 4.x driver Result interface
+type fakeResultV4 struct {
+	neo4j4.Result
+	results *fakeResult
+}
+
+func (result *fakeResultV4) Next() bool {
+	return result.results.Next(context.Background())
+}
+
+func (result *fakeResultV4) Err() error {
+	return result.results.Err()
+}
+
+func (result *fakeResultV4) Record() *neo4j4.Record {
+	record := result.results.Record()
+	return &neo4j4.Record{Keys: record.Keys, Values: record.Values}
+}
@@ -0,0 +1,305 @@
+// Package mapping converts Neo4j records to Go values and Go values to query parameters
+package mapping
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// tagName is the struct tag naming the column or property of a field, e.g. `neo4j:"name"`
+// Untagged fields match their name, regardless of case, and fields tagged with "-" are ignored
+// The omitempty option, e.g. `neo4j:"name,omitempty"`, leaves zero values out of encoded parameters
+const tagName = "neo4j"
+
+var anyType = reflect.TypeOf((*any)(nil)).Elem()
+
+// Decode converts the record to a T
+// When T is a struct whose fields match columns of the record, each column is decoded into its field
+// Otherwise, the record must have a single column, decoded as a whole, e.g. a node decoded into a struct from its properties
+// Integers are converted to smaller integer types only when they fit, null values leave the zero value of T
+func Decode[T any](record *neo4j.Record) (T, error) {
+	if record == nil {
+		var result T
+		return result, fmt.Errorf("cannot decode nil record into %T", result)
+	}
+	return decodeRecord[T](record.Keys, record.Values)
+}
+
+// DecodeAll decodes the records, e.g. the ones of an EagerResult returned by neo4j.ExecuteQuery, see Decode
+func DecodeAll[T any](records []*neo4j.Record) ([]T, error) {
+	values := make([]T, len(records))
+	for i, record := range records {
+		value, err := Decode[T](record)
+		if err != nil {
+			return nil, fmt.Errorf("record %d: %w", i, err)
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+// decodeRecord decodes the values of a record, whatever the version of the driver it comes from
+func decodeRecord[T any](keys []string, values []any) (T, error) {
+	var result T
+	target := reflect.ValueOf(&result).Elem()
+	if matchesColumns(target.Type(), keys) {
+		return result, decodeFields("", target, keys, func(key string) (any, bool) {
+			for i, candidate := range keys {
+				if candidate == key {
+					return values[i], true
+				}
+			}
+			return nil, false
+		})
+	}
+	if len(keys) != 1 {
+		return result, fmt.Errorf("cannot decode record with columns %v into %s: expected a single column or struct fields matching the columns",
+			keys, target.Type())
+	}
+	return result, decodeValue(fmt.Sprintf("column %q", keys[0]), values[0], target)
+}
+
+// matchesColumns reports whether the type is a struct with at least one field matching a column
+func matchesColumns(structType reflect.Type, keys []string) bool {
+	if structType.Kind() != reflect.Struct || isDriverType(structType) {
+		return false
+	}
+	for _, field := range structFields(structType) {
+		if _, found := lookup(keys, field.name); found {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeValue decodes the value into the target, the path describes the value in errors, e.g. `column "p".projects[1]`
+func decodeValue(path string, value any, target reflect.Value) error {
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	targetType := target.Type()
+	valueType := reflect.TypeOf(value)
+	if targetType == anyType || valueType == targetType {
+		target.Set(reflect.ValueOf(value))
+		return nil
+	}
+	switch targetType.Kind() {
+	case reflect.Pointer:
+		element := reflect.New(targetType.Elem())
+		if err := decodeValue(path, value, element.Elem()); err != nil {
+			return err
+		}
+		target.Set(element)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		integer, ok := value.(int64)
+		if !ok {
+			return mistyped(path, value, targetType)
+		}
+		if target.OverflowInt(integer) {
+			return fmt.Errorf("cannot decode %s: %d overflows %s", path, integer, targetType)
+		}
+		target.SetInt(integer)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		integer, ok := value.(int64)
+		if !ok {
+			return mistyped(path, value, targetType)
+		}
+		if integer < 0 || target.OverflowUint(uint64(integer)) {
+			return fmt.Errorf("cannot decode %s: %d overflows %s", path, integer, targetType)
+		}
+		target.SetUint(uint64(integer))
+		return nil
+	case reflect.Float32, reflect.Float64:
+		switch number := value.(type) {
+		case float64:
+			if target.OverflowFloat(number) {
+				return fmt.Errorf("cannot decode %s: %g overflows %s", path, number, targetType)
+			}
+			target.SetFloat(number)
+		case int64:
+			target.SetFloat(float64(number))
+		default:
+			return mistyped(path, value, targetType)
+		}
+		return nil
+	case reflect.String:
+		text, ok := value.(string)
+		if !ok {
+			return mistyped(path, value, targetType)
+		}
+		target.SetString(text)
+		return nil
+	case reflect.Bool:
+		boolean, ok := value.(bool)
+		if !ok {
+			return mistyped(path, value, targetType)
+		}
+		target.SetBool(boolean)
+		return nil
+	case reflect.Slice:
+		if valueType.AssignableTo(targetType) {
+			target.Set(reflect.ValueOf(value))
+			return nil
+		}
+		list, ok := value.([]any)
+		if !ok {
+			return mistyped(path, value, targetType)
+		}
+		slice := reflect.MakeSlice(targetType, len(list), len(list))
+		for i, element := range list {
+			if err := decodeValue(fmt.Sprintf("%s[%d]", path, i), element, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		target.Set(slice)
+		return nil
+	case reflect.Map:
+		properties, ok := propertiesOf(value)
+		if !ok || targetType.Key().Kind() != reflect.String {
+			return mistyped(path, value, targetType)
+		}
+		result := reflect.MakeMapWithSize(targetType, len(properties))
+		for key, property := range properties {
+			element := reflect.New(targetType.Elem()).Elem()
+			if err := decodeValue(fmt.Sprintf("%s[%q]", path, key), property, element); err != nil {
+				return err
+			}
+			result.SetMapIndex(reflect.ValueOf(key).Convert(targetType.Key()), element)
+		}
+		target.Set(result)
+		return nil
+	case reflect.Struct:
+		// e.g. neo4j.Date, which is a time.Time
+		if valueType.Kind() == reflect.Struct && valueType.ConvertibleTo(targetType) {
+			target.Set(reflect.ValueOf(value).Convert(targetType))
+			return nil
+		}
+		properties, ok := propertiesOf(value)
+		if !ok || isDriverType(targetType) {
+			return mistyped(path, value, targetType)
+		}
+		keys := make([]string, 0, len(properties))
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		return decodeFields(path, target, keys, func(key string) (any, bool) {
+			property, found := properties[key]
+			return property, found
+		})
+	}
+	if valueType.AssignableTo(targetType) {
+		target.Set(reflect.ValueOf(value))
+		return nil
+	}
+	return mistyped(path, value, targetType)
+}
+
+// decodeFields decodes the struct fields from the values of the matching keys
+// Without parent path, the keys are the columns of the record and they are all required
+// Otherwise, they are properties or map keys and missing ones leave the zero value
+func decodeFields(parentPath string, target reflect.Value, keys []string, get func(string) (any, bool)) error {
+	for _, field := range structFields(target.Type()) {
+		key, found := lookup(keys, field.name)
+		if !found {
+			if parentPath == "" {
+				return fmt.Errorf("cannot decode field %s of %s: missing column %q, got columns %v", field.path(), target.Type(), field.name, keys)
+			}
+			continue
+		}
+		value, _ := get(key)
+		path := fmt.Sprintf("%s.%s", parentPath, key)
+		if parentPath == "" {
+			path = fmt.Sprintf("column %q", key)
+		}
+		if err := decodeValue(path, value, target.FieldByIndex(field.index)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// propertiesOf returns the properties of nodes and relationships, or the entries of maps
+func propertiesOf(value any) (map[string]any, bool) {
+	switch entity := value.(type) {
+	case neo4j.Node:
+		return entity.Props, true
+	case neo4j.Relationship:
+		return entity.Props, true
+	case map[string]any:
+		return entity, true
+	}
+	return propertiesOfV4(value)
+}
+
+// isDriverType reports whether the type is defined by the driver, e.g. neo4j.Node or neo4j.Point2D, they are never decoded field by field
+func isDriverType(structType reflect.Type) bool {
+	return strings.HasPrefix(structType.PkgPath(), "github.com/neo4j/neo4j-go-driver/")
+}
+
+func mistyped(path string, value any, targetType reflect.Type) error {
+	return fmt.Errorf("cannot decode %s: expected a value convertible to %s, got %T", path, targetType, value)
+}
+
+type structField struct {
+	name      string
+	omitEmpty bool
+	index     []int
+	names     []string
+}
+
+func (field structField) path() string {
+	return strings.Join(field.names, ".")
+}
+
+// structFields lists the exported fields of the struct along with their column or property names
+// The fields of untagged embedded structs are listed as if they were fields of the struct itself
+func structFields(structType reflect.Type) []structField {
+	var fields []structField
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag, tagged := field.Tag.Lookup(tagName)
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+		if field.Anonymous && !tagged && field.Type.Kind() == reflect.Struct {
+			for _, embedded := range structFields(field.Type) {
+				embedded.index = append([]int{i}, embedded.index...)
+				embedded.names = append([]string{field.Name}, embedded.names...)
+				fields = append(fields, embedded)
+			}
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+		fields = append(fields, structField{
+			name:      name,
+			omitEmpty: options == "omitempty",
+			index:     []int{i},
+			names:     []string{field.Name},
+		})
+	}
+	return fields
+}
+
+// lookup finds the key with the exact name, or else the key matching the name regardless of case
+func lookup(keys []string, name string) (string, bool) {
+	for _, key := range keys {
+		if key == name {
+			return key, true
+		}
+	}
+	for _, key := range keys {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,190 @@
+package mapping_test
+
+import (
+	"graphconnect/go-driver/mapping"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+type Person struct {
+	Name     string    `neo4j:"name"`
+	Age      int8      `neo4j:"age"`
+	Projects []Project `neo4j:"projects"`
+	Nickname *string   `neo4j:"nickname"`
+	Ignored  string    `neo4j:"-"`
+}
+
+type Project struct {
+	Name        string
+	Maintainers uint
+}
+
+func TestDecode(outer *testing.T) {
+	outer.Run("decodes single values", func(t *testing.T) {
+		answer, err := mapping.Decode[int](record("answer", int64(42)))
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if answer != 42 {
+			t.Errorf("Expected 42, got: %v", answer)
+		}
+	})
+	outer.Run("decodes columns into tagged fields", func(t *testing.T) {
+		type count struct {
+			Project string `neo4j:"project"`
+			Count   int
+		}
+
+		result, err := mapping.Decode[count](record("project", "Neo4j", "count", int64(3)))
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if expected := (count{Project: "Neo4j", Count: 3}); result != expected {
+			t.Errorf("Expected %v, got: %v", expected, result)
+		}
+	})
+	outer.Run("decodes nested nodes, lists and maps", func(t *testing.T) {
+		node := neo4j.Node{Id: 1, Labels: []string{"Person"}, Props: map[string]any{
+			"name": "Eric",
+			"age":  int64(42),
+			"projects": []any{
+				map[string]any{"name": "GoGM", "maintainers": int64(2)},
+				neo4j.Node{Props: map[string]any{"name": "Neo4j"}},
+			},
+			"nickname": "fbiville",
+			"Ignored":  "ignored",
+		}}
+
+		person, err := mapping.Decode[*Person](record("p", node))
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		nickname := "fbiville"
+		expected := &Person{Name: "Eric", Age: 42, Nickname: &nickname, Projects: []Project{
+			{Name: "GoGM", Maintainers: 2},
+			{Name: "Neo4j"},
+		}}
+		if !reflect.DeepEqual(person, expected) {
+			t.Errorf("Expected %v, got: %v", expected, person)
+		}
+	})
+	outer.Run("keeps driver types", func(t *testing.T) {
+		node := neo4j.Node{Id: 1, Labels: []string{"Topic"}}
+		date := neo4j.DateOf(time.Date(2022, 10, 18, 0, 0, 0, 0, time.UTC))
+		type topic struct {
+			Node    neo4j.Node `neo4j:"t"`
+			Created time.Time  `neo4j:"created"`
+		}
+
+		result, err := mapping.Decode[topic](record("t", node, "created", date))
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if !reflect.DeepEqual(result.Node, node) || !result.Created.Equal(date.Time()) {
+			t.Errorf("Expected node %v created on %v, got: %v", node, date, result)
+		}
+	})
+	outer.Run("decodes null values to zero values", func(t *testing.T) {
+		names, err := mapping.Decode[map[string]*string](record("names", map[string]any{"eric": nil}))
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if name, found := names["eric"]; !found || name != nil {
+			t.Errorf("Expected nil name, got: %v", names)
+		}
+	})
+	outer.Run("decodes all records", func(t *testing.T) {
+		names, err := mapping.DecodeAll[string]([]*neo4j.Record{record("name", "Eric"), record("name", "Nikita")})
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if expected := []string{"Eric", "Nikita"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v, got: %v", expected, names)
+		}
+	})
+	outer.Run("reports decoding errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			name     string
+			decode   func() error
+			expected string
+		}{
+			{
+				name: "integer overflow",
+				decode: func() error {
+					_, err := mapping.Decode[int8](record("answer", int64(math.MaxInt8+1)))
+					return err
+				},
+				expected: `cannot decode column "answer": 128 overflows int8`,
+			},
+			{
+				name: "negative unsigned integer",
+				decode: func() error {
+					_, err := mapping.Decode[uint](record("answer", int64(-1)))
+					return err
+				},
+				expected: `cannot decode column "answer": -1 overflows uint`,
+			},
+			{
+				name: "mistyped column",
+				decode: func() error {
+					_, err := mapping.Decode[int64](record("answer", "42"))
+					return err
+				},
+				expected: `cannot decode column "answer": expected a value convertible to int64, got string`,
+			},
+			{
+				name: "mistyped nested property",
+				decode: func() error {
+					_, err := mapping.Decode[Person](record("p", map[string]any{
+						"projects": []any{map[string]any{"name": int64(42)}},
+					}))
+					return err
+				},
+				expected: `cannot decode column "p".projects[0].name: expected a value convertible to string, got int64`,
+			},
+			{
+				name: "missing column",
+				decode: func() error {
+					_, err := mapping.Decode[Person](record("name", "Eric"))
+					return err
+				},
+				expected: `cannot decode field Age of mapping_test.Person: missing column "age", got columns [name]`,
+			},
+			{
+				name: "several columns",
+				decode: func() error {
+					_, err := mapping.Decode[string](record("name", "Eric", "age", int64(42)))
+					return err
+				},
+				expected: "cannot decode record with columns [name age] into string",
+			},
+		} {
+			err := testCase.decode()
+
+			if err == nil || !strings.HasPrefix(err.Error(), testCase.expected) {
+				t.Errorf("Expected %s error %q, got: %v", testCase.name, testCase.expected, err)
+			}
+		}
+	})
+}
+
+// record builds a record from alternating keys and values
+func record(keysAndValues ...any) *neo4j.Record {
+	result := &neo4j.Record{}
+	for i := 0; i < len(keysAndValues); i += 2 {
+		result.Keys = append(result.Keys, keysAndValues[i].(string))
+		result.Values = append(result.Values, keysAndValues[i+1])
+	}
+	return result
+}
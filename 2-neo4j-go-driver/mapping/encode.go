@@ -0,0 +1,200 @@
+package mapping
+
+import (
+	"fmt"
+	"graphconnect/go-driver/cypher"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Valuer is implemented by types encoding themselves as a parameter value, e.g. an enum encoded as its name
+// The returned value is encoded in turn
+type Valuer interface {
+	Value() (any, error)
+}
+
+var valuerType = reflect.TypeOf((*Valuer)(nil)).Elem()
+var timeType = reflect.TypeOf(time.Time{})
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Parameters encodes the struct or map into the parameters of the query
+// It fails when a parameter of the query, e.g. $name, has no value
+func Parameters(query string, value any) (map[string]any, error) {
+	encoded, err := Encode(value)
+	if err != nil {
+		return nil, err
+	}
+	parameters, ok := encoded.(map[string]any)
+	if encoded == nil {
+		parameters, ok = map[string]any{}, true
+	}
+	if !ok {
+		return nil, fmt.Errorf("cannot use %T as query parameters: expected a struct or a map with string keys", value)
+	}
+	var missing []string
+	for _, name := range ParameterNames(query) {
+		if _, found := parameters[name]; !found {
+			missing = append(missing, "$"+name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing query parameters %s, got parameters %v", strings.Join(missing, ", "), sortedKeys(parameters))
+	}
+	return parameters, nil
+}
+
+// Encode converts the value into a value the driver accepts as a parameter
+// Structs become maps, keyed by the names of their fields (see tagName), and slices become lists
+// Integers become int64, unless they overflow, and durations become neo4j.Duration
+// time.Time and the driver types, such as neo4j.Date or neo4j.Point2D, are left as is
+func Encode(value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+	return encodeValue("parameter", reflect.ValueOf(value))
+}
+
+// encodeValue encodes the value, the path describes the value in errors, e.g. `parameter.Projects[1]`
+func encodeValue(path string, value reflect.Value) (any, error) {
+	valueType := value.Type()
+	if valueType.Implements(valuerType) {
+		if (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && value.IsNil() {
+			return nil, nil
+		}
+		custom, err := value.Interface().(Valuer).Value()
+		if err != nil {
+			return nil, fmt.Errorf("cannot encode %s: %w", path, err)
+		}
+		if custom == nil {
+			return nil, nil
+		}
+		return encodeValue(path, reflect.ValueOf(custom))
+	}
+	switch valueType {
+	case timeType:
+		return value.Interface(), nil
+	case durationType:
+		duration := time.Duration(value.Int())
+		return neo4j.DurationOf(0, 0, int64(duration/time.Second), int(duration%time.Second)), nil
+	}
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil, nil
+		}
+		return encodeValue(path, value.Elem())
+	case reflect.Bool:
+		return value.Bool(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int(), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if value.Uint() > math.MaxInt64 {
+			return nil, fmt.Errorf("cannot encode %s: %d overflows int64", path, value.Uint())
+		}
+		return int64(value.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), nil
+	case reflect.String:
+		return value.String(), nil
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil, nil
+		}
+		if value.Kind() == reflect.Slice && valueType.Elem().Kind() == reflect.Uint8 {
+			// byte slices are sent as byte arrays
+			return value.Bytes(), nil
+		}
+		list := make([]any, value.Len())
+		for i := range list {
+			element, err := encodeValue(fmt.Sprintf("%s[%d]", path, i), value.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			list[i] = element
+		}
+		return list, nil
+	case reflect.Map:
+		if valueType.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("cannot encode %s: expected map with string keys, got %s", path, valueType)
+		}
+		if value.IsNil() {
+			return nil, nil
+		}
+		entries := make(map[string]any, value.Len())
+		iterator := value.MapRange()
+		for iterator.Next() {
+			key := iterator.Key().String()
+			entry, err := encodeValue(fmt.Sprintf("%s[%q]", path, key), iterator.Value())
+			if err != nil {
+				return nil, err
+			}
+			entries[key] = entry
+		}
+		return entries, nil
+	case reflect.Struct:
+		if isDriverType(valueType) {
+			return value.Interface(), nil
+		}
+		entries := map[string]any{}
+		for _, field := range structFields(valueType) {
+			fieldValue := value.FieldByIndex(field.index)
+			if field.omitEmpty && fieldValue.IsZero() {
+				continue
+			}
+			entry, err := encodeValue(fmt.Sprintf("%s.%s", path, field.path()), fieldValue)
+			if err != nil {
+				return nil, err
+			}
+			entries[field.name] = entry
+		}
+		return entries, nil
+	}
+	return nil, fmt.Errorf("cannot encode %s: unsupported type %s", path, valueType)
+}
+
+// ParameterNames lists the parameters of the query, e.g. "name" for $name, ignoring strings and comments
+func ParameterNames(query string) []string {
+	var names []string
+	seen := map[string]bool{}
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	tokens := cypher.Tokenize(query)
+	for i, token := range tokens {
+		if token.Kind != cypher.Code {
+			continue
+		}
+		parts := strings.Split(token.Text, "$")
+		for _, part := range parts[1:] {
+			add(part[:len(part)-len(strings.TrimLeftFunc(part, isNameRune))])
+		}
+		// a quoted name follows the trailing $ sign, e.g. $`first name`
+		if len(parts) > 1 && parts[len(parts)-1] == "" && i+1 < len(tokens) && tokens[i+1].Kind == cypher.QuotedName {
+			if quoted := tokens[i+1].Text; len(quoted) > 1 && strings.HasSuffix(quoted, "`") {
+				add(quoted[1 : len(quoted)-1])
+			}
+		}
+	}
+	return names
+}
+
+func isNameRune(char rune) bool {
+	return char == '_' || (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z') || (char >= '0' && char <= '9')
+}
+
+func sortedKeys(entries map[string]any) []string {
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,129 @@
+package mapping_test
+
+import (
+	"errors"
+	"graphconnect/go-driver/mapping"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+type Role int
+
+const (
+	Contributor Role = iota
+	Maintainer
+)
+
+func (role Role) Value() (any, error) {
+	switch role {
+	case Contributor:
+		return "CONTRIBUTOR", nil
+	case Maintainer:
+		return "MAINTAINER", nil
+	}
+	return nil, errors.New("unknown role")
+}
+
+type Contribution struct {
+	Project  string        `neo4j:"project"`
+	Role     Role          `neo4j:"role"`
+	Since    time.Time     `neo4j:"since"`
+	Duration time.Duration `neo4j:"duration"`
+	Tags     []string      `neo4j:"tags,omitempty"`
+}
+
+func TestEncode(outer *testing.T) {
+	outer.Run("encodes structs into parameters", func(t *testing.T) {
+		since := time.Date(2022, 10, 18, 9, 0, 0, 0, time.UTC)
+		type parameters struct {
+			Name          string         `neo4j:"name"`
+			Age           uint8          `neo4j:"age"`
+			Contributions []Contribution `neo4j:"contributions"`
+		}
+
+		result, err := mapping.Parameters(
+			"MERGE (p:Person {name: $name}) SET p.age = $age WITH p UNWIND $contributions AS contribution RETURN p",
+			parameters{
+				Name: "Florent",
+				Age:  42,
+				Contributions: []Contribution{
+					{Project: "GoGM", Role: Maintainer, Since: since, Duration: 90 * time.Minute, Tags: []string{"go"}},
+					{Project: "Neo4j", Role: Contributor, Since: since},
+				},
+			})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := map[string]any{
+			"name": "Florent",
+			"age":  int64(42),
+			"contributions": []any{
+				map[string]any{"project": "GoGM", "role": "MAINTAINER", "since": since,
+					"duration": neo4j.DurationOf(0, 0, 5400, 0), "tags": []any{"go"}},
+				map[string]any{"project": "Neo4j", "role": "CONTRIBUTOR", "since": since,
+					"duration": neo4j.DurationOf(0, 0, 0, 0)},
+			},
+		}
+		if !reflect.DeepEqual(result, expected) {
+			t.Errorf("Expected %v, got: %v", expected, result)
+		}
+	})
+	outer.Run("encodes maps into parameters", func(t *testing.T) {
+		result, err := mapping.Parameters("RETURN REDUCE(sum=0, power IN $powersOfTwo | sum+power) AS answer",
+			map[string][]int{"powersOfTwo": {2, 8, 32}})
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		if expected := map[string]any{"powersOfTwo": []any{int64(2), int64(8), int64(32)}}; !reflect.DeepEqual(result, expected) {
+			t.Errorf("Expected %v, got: %v", expected, result)
+		}
+	})
+	outer.Run("reports missing parameters", func(t *testing.T) {
+		_, err := mapping.Parameters("MATCH (p:Person {name: $name}) WHERE p.age > $age RETURN p", map[string]any{"name": "Eric"})
+
+		expected := "missing query parameters $age, got parameters [name]"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got: %v", expected, err)
+		}
+	})
+	outer.Run("reports encoding errors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			value    any
+			expected string
+		}{
+			{"not a struct", "cannot use string as query parameters"},
+			{Contribution{Role: Role(-1)}, "cannot encode parameter.Role: unknown role"},
+			{[]uint64{math.MaxUint64}, "cannot encode parameter[0]: 18446744073709551615 overflows int64"},
+			{[]map[int]string{{1: "invalid"}}, "cannot encode parameter[0]: expected map with string keys"},
+		} {
+			_, err := mapping.Parameters("RETURN 1", testCase.value)
+
+			if err == nil || !strings.HasPrefix(err.Error(), testCase.expected) {
+				t.Errorf("Expected error %q, got: %v", testCase.expected, err)
+			}
+		}
+	})
+	outer.Run("lists query parameters", func(t *testing.T) {
+		names := mapping.ParameterNames("MATCH (p:Person {name: $name, `$label`: '$ignored'}) // $comment\n" +
+			"WHERE p.age > $age AND p.name <> $name /* $ignored */ RETURN $`quoted name`, $0")
+
+		expected := []string{"name", "age", "quoted name", "0"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v, got: %v", expected, names)
+		}
+	})
+	outer.Run("ignores the parameters of block comments opened with /*/", func(t *testing.T) {
+		names := mapping.ParameterNames("RETURN $name /*/ $ignored */")
+
+		if expected := []string{"name"}; !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v, got: %v", expected, names)
+		}
+	})
+}
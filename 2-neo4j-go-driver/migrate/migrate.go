@@ -0,0 +1,298 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graphconnect/go-driver/mapping"
+	"graphconnect/go-driver/neo4jerrors"
+	"io/fs"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// ErrModified is returned, wrapped, when the file of an applied migration changed since it was applied
+var ErrModified = errors.New("applied migrations were modified")
+
+// ErrInProgress is returned, wrapped, when a migration was started but not applied
+// Either another migrator is applying it, or a migrator stopped halfway: its (:__Migration) node must then be deleted
+var ErrInProgress = errors.New("migrations are in progress")
+
+const appliedQuery = "MATCH (m:__Migration) " +
+	"RETURN m.version AS version, m.description AS description, m.checksum AS checksum, " +
+	"m.appliedAt IS NOT NULL AS applied, m.appliedAt AS appliedAt " +
+	"ORDER BY m.version"
+
+// versionConstraint makes a single migrator claim each version, see claimQuery
+const versionConstraint = "CREATE CONSTRAINT __migration_version IF NOT EXISTS FOR (m:__Migration) REQUIRE m.version IS UNIQUE"
+
+const claimQuery = "CREATE (m:__Migration {version: $version, description: $description, checksum: $checksum, startedAt: datetime()})"
+
+const recordQuery = "MATCH (m:__Migration {version: $version}) SET m.appliedAt = datetime()"
+
+const releaseQuery = "MATCH (m:__Migration {version: $version}) WHERE m.appliedAt IS NULL DELETE m"
+
+// State tells whether a migration was applied
+type State string
+
+const (
+	Pending State = "pending"
+	Applied State = "applied"
+	// Modified migrations were applied, but their file changed since
+	Modified State = "modified"
+	// Missing migrations were applied, but their file is gone
+	Missing State = "missing"
+	// Started migrations are being applied, or their migrator stopped halfway
+	Started State = "started"
+)
+
+// Status is the state of a migration in the database
+type Status struct {
+	Version     int
+	Description string
+	State       State
+	// AppliedAt is the zero time for pending migrations
+	AppliedAt time.Time
+}
+
+func (status Status) String() string {
+	if status.AppliedAt.IsZero() {
+		return fmt.Sprintf("%04d %s: %s", status.Version, status.Description, status.State)
+	}
+	return fmt.Sprintf("%04d %s: %s, applied at %s", status.Version, status.Description, status.State,
+		status.AppliedAt.Format(time.RFC3339))
+}
+
+// appliedMigration is the (:__Migration) node recording an applied migration
+type appliedMigration struct {
+	Version     int       `neo4j:"version"`
+	Description string    `neo4j:"description"`
+	Checksum    string    `neo4j:"checksum"`
+	Applied     bool      `neo4j:"applied"`
+	AppliedAt   time.Time `neo4j:"appliedAt"`
+}
+
+// Migrator applies the migration files of a file system, see Load
+type Migrator struct {
+	driver neo4j.DriverWithContext
+	fsys   fs.FS
+	config neo4j.SessionConfig
+	dryRun bool
+}
+
+// Option customizes the Migrator
+type Option func(*Migrator)
+
+// WithDatabase applies the migrations to the given database, instead of the default one
+func WithDatabase(name string) Option {
+	return func(migrator *Migrator) {
+		migrator.config.DatabaseName = name
+	}
+}
+
+// WithDryRun makes Migrate return the pending migrations without applying them
+func WithDryRun() Option {
+	return func(migrator *Migrator) {
+		migrator.dryRun = true
+	}
+}
+
+// New returns a Migrator applying the migration files of fsys with the driver
+func New(driver neo4j.DriverWithContext, fsys fs.FS, options ...Option) *Migrator {
+	migrator := &Migrator{driver: driver, fsys: fsys, config: neo4j.SessionConfig{AccessMode: neo4j.AccessModeWrite}}
+	for _, option := range options {
+		option(migrator)
+	}
+	return migrator
+}
+
+// Status returns the state of the migration files and of the applied migrations whose file is missing, sorted by version
+func (migrator *Migrator) Status(ctx context.Context) ([]Status, error) {
+	_, statuses, err := migrator.load(ctx)
+	return statuses, err
+}
+
+// Migrate applies the pending migrations, by increasing version, and returns them
+//
+// Each migration is first claimed by creating its (:__Migration) node, a uniqueness constraint on its version keeps
+// concurrent migrators from applying it twice. Its schema statements then run in a transaction, and its data statements
+// in another one, along with the record of the migration.
+// Neo4j cannot run schema and data statements in the same transaction, so the migration is not atomic: when its data
+// statements fail, its claim is released but its schema statements stay applied. They run again on the next attempt,
+// hence they should use IF NOT EXISTS.
+//
+// It refuses to run when the file of an applied migration was modified, or when a migration is in progress
+func (migrator *Migrator) Migrate(ctx context.Context) ([]Migration, error) {
+	migrations, statuses, err := migrator.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var modified, started []string
+	pending := map[int]bool{}
+	for _, status := range statuses {
+		switch status.State {
+		case Modified:
+			modified = append(modified, fmt.Sprintf("%04d %s", status.Version, status.Description))
+		case Started:
+			started = append(started, fmt.Sprintf("%04d %s", status.Version, status.Description))
+		case Pending:
+			pending[status.Version] = true
+		}
+	}
+	if len(modified) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrModified, strings.Join(modified, ", "))
+	}
+	if len(started) > 0 && !migrator.dryRun {
+		return nil, fmt.Errorf("%w: %s", ErrInProgress, strings.Join(started, ", "))
+	}
+	if len(pending) > 0 && !migrator.dryRun {
+		if err := migrator.run(ctx, []string{versionConstraint}, "", nil); err != nil {
+			return nil, fmt.Errorf("could not create the migration version constraint: %w", err)
+		}
+	}
+	var applied []Migration
+	for _, migration := range migrations {
+		if !pending[migration.Version] {
+			continue
+		}
+		if !migrator.dryRun {
+			if err := migrator.apply(ctx, migration); err != nil {
+				return applied, fmt.Errorf("could not apply migration %s: %w", migration, err)
+			}
+		}
+		applied = append(applied, migration)
+	}
+	return applied, nil
+}
+
+// load reads the migration files and the applied migrations, and compares them
+func (migrator *Migrator) load(ctx context.Context) ([]Migration, []Status, error) {
+	migrations, err := Load(migrator.fsys)
+	if err != nil {
+		return nil, nil, err
+	}
+	applied, err := migrator.applied(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	var statuses []Status
+	for _, migration := range migrations {
+		status := Status{Version: migration.Version, Description: migration.Description, State: Pending}
+		if record, found := applied[migration.Version]; found {
+			status.State, status.AppliedAt = Applied, record.AppliedAt
+			switch {
+			case !record.Applied:
+				status.State = Started
+			case record.Checksum != migration.Checksum:
+				status.State = Modified
+			}
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, record := range applied {
+		state := Missing
+		if !record.Applied {
+			state = Started
+		}
+		statuses = append(statuses, Status{
+			Version:     record.Version,
+			Description: record.Description,
+			State:       state,
+			AppliedAt:   record.AppliedAt,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return migrations, statuses, nil
+}
+
+// applied returns the recorded migrations, by version
+func (migrator *Migrator) applied(ctx context.Context) (_ map[int]appliedMigration, err error) {
+	session := migrator.driver.NewSession(ctx, migrator.config)
+	defer func() {
+		if closeErr := session.Close(ctx); err == nil {
+			err = closeErr
+		}
+	}()
+	records, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, appliedQuery, nil)
+		if err != nil {
+			return nil, err
+		}
+		return mapping.CollectAs[appliedMigration](ctx, result)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not read applied migrations: %w", err)
+	}
+	applied := map[int]appliedMigration{}
+	for _, record := range records.([]appliedMigration) {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// apply claims the migration, runs its schema statements and then its data statements, and records it
+// note: with Neo4j, you cannot mix schema and data operations, hence the separate transactions
+// ... the claim is released when a transaction fails, so that the next attempt runs the whole migration again
+func (migrator *Migrator) apply(ctx context.Context, migration Migration) error {
+	record := map[string]any{
+		"version":     migration.Version,
+		"description": migration.Description,
+		"checksum":    migration.Checksum,
+	}
+	if err := migrator.run(ctx, nil, claimQuery, record); err != nil {
+		if neo4jerrors.IsConstraintViolation(err) {
+			return fmt.Errorf("%w: %s was claimed by another migrator", ErrInProgress, migration)
+		}
+		return err
+	}
+	err := migrator.run(ctx, migration.Schema, "", nil)
+	if err == nil {
+		err = migrator.run(ctx, migration.Data, recordQuery, record)
+	}
+	if err != nil {
+		if releaseErr := migrator.run(ctx, nil, releaseQuery, record); releaseErr != nil {
+			return fmt.Errorf("%w (could not release the migration either: %v)", err, releaseErr)
+		}
+	}
+	return err
+}
+
+// run runs the statements of a migration file, then the query of the migrator if any, in a write transaction
+func (migrator *Migrator) run(ctx context.Context, statements []string, query string, parameters map[string]any) (err error) {
+	if len(statements) == 0 && query == "" {
+		return nil
+	}
+	session := migrator.driver.NewSession(ctx, migrator.config)
+	defer func() {
+		if closeErr := session.Close(ctx); err == nil {
+			err = closeErr
+		}
+	}()
+	_, err = session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		for _, statement := range statements {
+			if err := consume(ctx, tx, statement, nil); err != nil {
+				return nil, err
+			}
+		}
+		if query == "" {
+			return nil, nil
+		}
+		return nil, consume(ctx, tx, query, parameters)
+	})
+	return err
+}
+
+func consume(ctx context.Context, tx neo4j.ManagedTransaction, query string, parameters map[string]any) error {
+	result, err := tx.Run(ctx, query, parameters)
+	if err != nil {
+		return err
+	}
+	_, err = result.Consume(ctx)
+	return err
+}
@@ -0,0 +1,230 @@
+package migrate_test
+
+import (
+	"context"
+	"errors"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/migrate"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+const appliedQuery = "MATCH (m:__Migration) " +
+	"RETURN m.version AS version, m.description AS description, m.checksum AS checksum, " +
+	"m.appliedAt IS NOT NULL AS applied, m.appliedAt AS appliedAt " +
+	"ORDER BY m.version"
+
+const versionConstraint = "CREATE CONSTRAINT __migration_version IF NOT EXISTS FOR (m:__Migration) REQUIRE m.version IS UNIQUE"
+
+const claimQuery = "CREATE (m:__Migration {version: $version, description: $description, checksum: $checksum, startedAt: datetime()})"
+
+const recordQuery = "MATCH (m:__Migration {version: $version}) SET m.appliedAt = datetime()"
+
+const releaseQuery = "MATCH (m:__Migration {version: $version}) WHERE m.appliedAt IS NULL DELETE m"
+
+var appliedKeys = []string{"version", "description", "checksum", "applied", "appliedAt"}
+
+var migrations = fstest.MapFS{
+	"0001_create_name_indexes.cypher": {Data: []byte("CREATE INDEX topic_name IF NOT EXISTS FOR (t:Topic) ON (t.name);")},
+	"0002_insert_topics.cypher":       {Data: []byte("MERGE (:Topic {name: 'Neo4j'});")},
+	"0003_insert_persons.cypher":      {Data: []byte("MERGE (:Person {name: 'Eric'});")},
+}
+
+func TestMigrator(outer *testing.T) {
+	ctx := context.Background()
+	loaded, err := migrate.Load(migrations)
+	if err != nil {
+		outer.Fatalf("Could not load migrations: %v", err)
+	}
+
+	outer.Run("applies pending migrations in order", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			appliedQuery: {
+				Keys:    appliedKeys,
+				Records: [][]any{{1, "create name indexes", loaded[0].Checksum, true, nil}},
+			},
+			versionConstraint:                {Counters: map[string]int{"constraints-added": 1}},
+			claimQuery:                       {Counters: map[string]int{"nodes-created": 1}},
+			"MERGE (:Topic {name: 'Neo4j'})": {Counters: map[string]int{"nodes-created": 1}},
+			"MERGE (:Person {name: 'Eric'})": {Counters: map[string]int{"nodes-created": 1}},
+			recordQuery:                      {Counters: map[string]int{"properties-set": 1}},
+		})
+
+		applied, err := migrate.New(driver, migrations).Migrate(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(applied) != 2 || applied[0].Version != 2 || applied[1].Version != 3 {
+			t.Errorf("Expected migrations 2 and 3 to be applied, got: %v", applied)
+		}
+		expected := []string{
+			appliedQuery,
+			versionConstraint,
+			claimQuery, "MERGE (:Topic {name: 'Neo4j'})", recordQuery,
+			claimQuery, "MERGE (:Person {name: 'Eric'})", recordQuery,
+		}
+		if queries := workshoptest.ReceivedQueries(server); !reflect.DeepEqual(queries, expected) {
+			t.Errorf("Expected queries %q, got: %q", expected, queries)
+		}
+		received := server.Received()
+		if version := received[2].Params["version"]; version != int64(2) {
+			t.Errorf("Expected version 2 to be claimed, got: %v", version)
+		}
+		if version := received[4].Params["version"]; version != int64(2) {
+			t.Errorf("Expected version 2 to be recorded, got: %v", version)
+		}
+	})
+	outer.Run("runs schema statements before data statements", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			appliedQuery:      {Keys: appliedKeys},
+			versionConstraint: {Counters: map[string]int{"constraints-added": 1}},
+			claimQuery:        {Counters: map[string]int{"nodes-created": 1}},
+			"CREATE INDEX topic_name IF NOT EXISTS FOR (t:Topic) ON (t.name)": {Counters: map[string]int{"indexes-added": 1}},
+			recordQuery: {Counters: map[string]int{"properties-set": 1}},
+		})
+
+		_, err := migrate.New(driver, fstest.MapFS{"0001_create_name_indexes.cypher": migrations["0001_create_name_indexes.cypher"]}).Migrate(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := []string{
+			appliedQuery, versionConstraint,
+			claimQuery, "CREATE INDEX topic_name IF NOT EXISTS FOR (t:Topic) ON (t.name)", recordQuery,
+		}
+		if queries := workshoptest.ReceivedQueries(server); !reflect.DeepEqual(queries, expected) {
+			t.Errorf("Expected queries %q, got: %q", expected, queries)
+		}
+	})
+	outer.Run("lists pending migrations on dry run", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			appliedQuery: {Keys: appliedKeys},
+		})
+
+		pending, err := migrate.New(driver, migrations, migrate.WithDryRun()).Migrate(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(pending) != 3 {
+			t.Errorf("Expected 3 pending migrations, got: %v", pending)
+		}
+		if queries := workshoptest.ReceivedQueries(server); len(queries) != 1 {
+			t.Errorf("Expected only the applied migrations to be read, got: %q", queries)
+		}
+	})
+	outer.Run("reports the status of each migration", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			appliedQuery: {
+				Keys: appliedKeys,
+				Records: [][]any{
+					{1, "create name indexes", loaded[0].Checksum, true, nil},
+					{2, "insert topics", "edited", true, nil},
+					{3, "insert persons", loaded[2].Checksum, false, nil},
+					{4, "insert projects", "gone", true, nil},
+				},
+			},
+		})
+
+		statuses, err := migrate.New(driver, migrations).Status(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		var states []string
+		for _, status := range statuses {
+			states = append(states, status.String())
+		}
+		expected := []string{
+			"0001 create name indexes: applied",
+			"0002 insert topics: modified",
+			"0003 insert persons: started",
+			"0004 insert projects: missing",
+		}
+		if !reflect.DeepEqual(states, expected) {
+			t.Errorf("Expected %q, got: %q", expected, states)
+		}
+	})
+	outer.Run("refuses to run when applied migrations were modified", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			appliedQuery: {
+				Keys:    appliedKeys,
+				Records: [][]any{{1, "create name indexes", "edited", true, nil}},
+			},
+		})
+
+		applied, err := migrate.New(driver, migrations).Migrate(ctx)
+
+		if !errors.Is(err, migrate.ErrModified) {
+			t.Errorf("Expected ErrModified, got: %v", err)
+		}
+		if expected := "applied migrations were modified: 0001 create name indexes"; err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got: %v", expected, err)
+		}
+		if len(applied) != 0 || len(workshoptest.ReceivedQueries(server)) != 1 {
+			t.Errorf("Expected no migration to run, got: %q", workshoptest.ReceivedQueries(server))
+		}
+	})
+	outer.Run("refuses to run when migrations are in progress", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			appliedQuery: {
+				Keys:    appliedKeys,
+				Records: [][]any{{1, "create name indexes", loaded[0].Checksum, false, nil}},
+			},
+		})
+
+		_, err := migrate.New(driver, migrations).Migrate(ctx)
+
+		if !errors.Is(err, migrate.ErrInProgress) {
+			t.Errorf("Expected ErrInProgress, got: %v", err)
+		}
+		if len(workshoptest.ReceivedQueries(server)) != 1 {
+			t.Errorf("Expected no migration to run, got: %q", workshoptest.ReceivedQueries(server))
+		}
+	})
+	outer.Run("stops when another migrator claimed a migration", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			appliedQuery:      {Keys: appliedKeys},
+			versionConstraint: {},
+			claimQuery: {Failure: &fakebolt.Failure{
+				Code:    "Neo.ClientError.Schema.ConstraintValidationFailed",
+				Message: "Node already exists with label `__Migration` and property `version` = 1",
+			}},
+		})
+
+		applied, err := migrate.New(driver, migrations).Migrate(ctx)
+
+		if !errors.Is(err, migrate.ErrInProgress) {
+			t.Errorf("Expected ErrInProgress, got: %v", err)
+		}
+		expected := []string{appliedQuery, versionConstraint, claimQuery}
+		if queries := workshoptest.ReceivedQueries(server); len(applied) != 0 || !reflect.DeepEqual(queries, expected) {
+			t.Errorf("Expected queries %q, got: %q", expected, queries)
+		}
+	})
+	outer.Run("releases the claim of a failed migration", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			appliedQuery:      {Keys: appliedKeys},
+			versionConstraint: {},
+			claimQuery:        {Counters: map[string]int{"nodes-created": 1}},
+			"MERGE (:Topic {name: 'Neo4j'})": {Failure: &fakebolt.Failure{
+				Code:    "Neo.ClientError.Statement.SyntaxError",
+				Message: "Invalid input",
+			}},
+			releaseQuery: {Counters: map[string]int{"nodes-deleted": 1}},
+		})
+
+		_, err := migrate.New(driver, fstest.MapFS{"0002_insert_topics.cypher": migrations["0002_insert_topics.cypher"]}).Migrate(ctx)
+
+		if err == nil {
+			t.Errorf("Expected an error, got nil")
+		}
+		expected := []string{appliedQuery, versionConstraint, claimQuery, "MERGE (:Topic {name: 'Neo4j'})", releaseQuery}
+		if queries := workshoptest.ReceivedQueries(server); !reflect.DeepEqual(queries, expected) {
+			t.Errorf("Expected queries %q, got: %q", expected, queries)
+		}
+	})
+}
@@ -0,0 +1,84 @@
+// Package migrate applies versioned Cypher files to a database, once each
+//
+// Migration files are named after their version and description, e.g. 0001_create_name_indexes.cypher
+// The applied versions are recorded in (:__Migration) nodes, along with the checksum of their file
+package migrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"graphconnect/go-driver/cypher"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.cypher$`)
+
+// Migration is a versioned Cypher file
+type Migration struct {
+	Version     int
+	Description string
+	File        string
+	// Checksum is the SHA-256 of the file content, an applied migration must not change
+	Checksum string
+	// Schema holds the statements creating or dropping indexes and constraints, they run before the Data statements
+	Schema []string
+	Data   []string
+}
+
+func (migration Migration) String() string {
+	return fmt.Sprintf("%04d %s", migration.Version, migration.Description)
+}
+
+// Load reads the migration files at the root of fsys, sorted by version
+// Files without the .cypher extension are ignored, the .cypher ones must be named like migrations, and two files cannot
+// share a version
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+	var migrations []Migration
+	versions := map[int]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".cypher" {
+			continue
+		}
+		matches := fileName.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			// note: skipping it would silently leave the migration out, e.g. 0002_add-topics.cypher
+			return nil, fmt.Errorf("invalid migration name %s, expected a name like 0001_create_name_indexes.cypher", entry.Name())
+		}
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid version of migration %s: %w", entry.Name(), err)
+		}
+		if other, found := versions[version]; found {
+			return nil, fmt.Errorf("migrations %s and %s have the same version %d", other, entry.Name(), version)
+		}
+		versions[version] = entry.Name()
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		checksum := sha256.Sum256(content)
+		schema, data := cypher.Partition(cypher.Split(string(content)))
+		migrations = append(migrations, Migration{
+			Version:     version,
+			Description: strings.ReplaceAll(matches[2], "_", " "),
+			File:        entry.Name(),
+			Checksum:    hex.EncodeToString(checksum[:]),
+			Schema:      schema,
+			Data:        data,
+		})
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
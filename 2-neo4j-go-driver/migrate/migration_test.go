@@ -0,0 +1,62 @@
+package migrate_test
+
+import (
+	"graphconnect/go-driver/migrate"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(outer *testing.T) {
+	outer.Run("loads migrations sorted by version", func(t *testing.T) {
+		migrations, err := migrate.Load(fstest.MapFS{
+			"0002_insert_topics.cypher":       {Data: []byte("MERGE (:Topic {name: 'Neo4j'});")},
+			"0001_create_name_indexes.cypher": {Data: []byte("CREATE INDEX topic_name IF NOT EXISTS FOR (t:Topic) ON (t.name);\nMERGE (:Topic {name: 'Go'});")},
+			"README.md":                       {Data: []byte("not a migration")},
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if len(migrations) != 2 {
+			t.Fatalf("Expected 2 migrations, got: %v", migrations)
+		}
+		first := migrations[0]
+		if first.String() != "0001 create name indexes" || first.File != "0001_create_name_indexes.cypher" {
+			t.Errorf("Expected first migration to create the indexes, got: %v (%s)", first, first.File)
+		}
+		if expected := []string{"CREATE INDEX topic_name IF NOT EXISTS FOR (t:Topic) ON (t.name)"}; !reflect.DeepEqual(first.Schema, expected) {
+			t.Errorf("Expected schema statements %q, got: %q", expected, first.Schema)
+		}
+		if expected := []string{"MERGE (:Topic {name: 'Go'})"}; !reflect.DeepEqual(first.Data, expected) {
+			t.Errorf("Expected data statements %q, got: %q", expected, first.Data)
+		}
+		if len(first.Checksum) != 64 || first.Checksum == migrations[1].Checksum {
+			t.Errorf("Expected distinct SHA-256 checksums, got: %s and %s", first.Checksum, migrations[1].Checksum)
+		}
+	})
+	outer.Run("rejects misnamed migrations", func(t *testing.T) {
+		for _, name := range []string{"0002_add-topics.cypher", "add_topics.cypher", "0002.cypher"} {
+			_, err := migrate.Load(fstest.MapFS{
+				"0001_create_name_indexes.cypher": {Data: []byte("RETURN 1;")},
+				name:                              {Data: []byte("MERGE (:Topic {name: 'Neo4j'});")},
+			})
+
+			if err == nil || !strings.Contains(err.Error(), "invalid migration name "+name) {
+				t.Errorf("Expected %s to be rejected, got: %v", name, err)
+			}
+		}
+	})
+	outer.Run("rejects duplicate versions", func(t *testing.T) {
+		_, err := migrate.Load(fstest.MapFS{
+			"1_first.cypher":    {Data: []byte("RETURN 1;")},
+			"0001_again.cypher": {Data: []byte("RETURN 1;")},
+		})
+
+		expected := "migrations 0001_again.cypher and 1_first.cypher have the same version 1"
+		if err == nil || err.Error() != expected {
+			t.Errorf("Expected error %q, got: %v", expected, err)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+// Package neo4jerrors classifies the errors of the 5.x and 4.x drivers, so that code can branch on them
+//
+//	if neo4jerrors.IsConstraintViolation(err) {
+//		return &AlreadyExistsError{Label: "Person", Err: err}
+//	}
+package neo4jerrors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	neo4j4 "github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Category groups errors by what the caller can do about them
+type Category string
+
+const (
+	// Client errors are caused by the request, e.g. syntax errors, constraint violations or bad credentials, retrying does not help
+	Client Category = "client"
+	// Transient errors may not happen again, e.g. deadlocks or cluster leader switches, the transaction can be retried
+	Transient Category = "transient"
+	// Database errors are failures of the server, the administrator should look at its logs
+	Database Category = "database"
+	// Connectivity errors happen when the server cannot be reached, or the connection breaks
+	Connectivity Category = "connectivity"
+)
+
+// Code is a parsed Neo4j status code, e.g. Neo.ClientError.Statement.SyntaxError
+type Code struct {
+	// Classification is ClientError, ClientNotification, TransientError or DatabaseError
+	Classification string
+	// Category is the area of the error, e.g. Statement, Schema, Security or Transaction
+	Category string
+	Title    string
+}
+
+// ParseCode splits a Neo4j status code into its parts, ok is false when the code is malformed
+func ParseCode(code string) (parsed Code, ok bool) {
+	parts := strings.Split(code, ".")
+	if len(parts) != 4 || parts[0] != "Neo" {
+		return Code{}, false
+	}
+	return Code{Classification: parts[1], Category: parts[2], Title: parts[3]}, true
+}
+
+func (code Code) String() string {
+	if code == (Code{}) {
+		return ""
+	}
+	return fmt.Sprintf("Neo.%s.%s.%s", code.Classification, code.Category, code.Title)
+}
+
+// Error is a classified driver error, it wraps the error of the driver
+type Error struct {
+	Category Category
+	// Code is the status code sent by the server, it is zero for connectivity errors
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (err *Error) Error() string {
+	return fmt.Sprintf("%s error: %v", err.Category, err.Err)
+}
+
+func (err *Error) Unwrap() error {
+	return err.Err
+}
+
+// Classify returns the classified error, or nil when err is not an error of the driver
+// Wrapped errors are classified too
+func Classify(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var classified *Error
+	if errors.As(err, &classified) {
+		return classified
+	}
+	var neo4jError *neo4j.Neo4jError
+	if errors.As(err, &neo4jError) {
+		return classifyCode(err, neo4jError.Code, neo4jError.Msg)
+	}
+	var neo4jErrorV4 *neo4j4.Neo4jError
+	if errors.As(err, &neo4jErrorV4) {
+		return classifyCode(err, neo4jErrorV4.Code, neo4jErrorV4.Msg)
+	}
+	var tokenExpiredError *neo4j.TokenExpiredError
+	if errors.As(err, &tokenExpiredError) {
+		return classifyCode(err, tokenExpiredError.Code, tokenExpiredError.Message)
+	}
+	var connectivityError *neo4j.ConnectivityError
+	var connectivityErrorV4 *neo4j4.ConnectivityError
+	if errors.As(err, &connectivityError) || errors.As(err, &connectivityErrorV4) {
+		return &Error{Category: Connectivity, Message: err.Error(), Err: err}
+	}
+	return nil
+}
+
+func classifyCode(err error, status, message string) *Error {
+	code, _ := ParseCode(status)
+	classified := &Error{Code: code, Message: message, Err: err}
+	switch {
+	// note: these transient errors are caused by the client, e.g. when it terminates the transaction
+	case status == "Neo.TransientError.Transaction.Terminated", status == "Neo.TransientError.Transaction.LockClientStopped":
+		classified.Category = Client
+	// note: these client errors happen while the cluster elects a new leader
+	case status == "Neo.ClientError.Cluster.NotALeader", status == "Neo.ClientError.General.ForbiddenOnReadOnlyDatabase":
+		classified.Category = Transient
+	case code.Classification == "TransientError":
+		classified.Category = Transient
+	case code.Classification == "DatabaseError":
+		classified.Category = Database
+	default:
+		classified.Category = Client
+	}
+	return classified
+}
+
+// CodeOf returns the status code of the error, it is zero when the error has none
+func CodeOf(err error) Code {
+	if classified := Classify(err); classified != nil {
+		return classified.Code
+	}
+	return Code{}
+}
+
+// IsClientError reports whether the error is caused by the request, see Client
+func IsClientError(err error) bool {
+	return is(err, Client)
+}
+
+// IsTransient reports whether the transaction can be retried, see Transient
+func IsTransient(err error) bool {
+	return is(err, Transient)
+}
+
+// IsDatabaseError reports whether the server failed, see Database
+func IsDatabaseError(err error) bool {
+	return is(err, Database)
+}
+
+// IsConnectivityError reports whether the server could not be reached, see Connectivity
+func IsConnectivityError(err error) bool {
+	return is(err, Connectivity)
+}
+
+// IsSyntaxError reports whether the query is not valid Cypher
+func IsSyntaxError(err error) bool {
+	return CodeOf(err) == Code{"ClientError", "Statement", "SyntaxError"}
+}
+
+// IsConstraintViolation reports whether the query breaks a constraint, e.g. a uniqueness one
+func IsConstraintViolation(err error) bool {
+	return CodeOf(err) == Code{"ClientError", "Schema", "ConstraintValidationFailed"}
+}
+
+// IsAuthenticationError reports whether the credentials were rejected or expired
+func IsAuthenticationError(err error) bool {
+	code := CodeOf(err)
+	return code.Classification == "ClientError" && code.Category == "Security" &&
+		(code.Title == "Unauthorized" || code.Title == "AuthenticationRateLimit" || code.Title == "TokenExpired")
+}
+
+// IsDeadlock reports whether the transaction was chosen as the victim of a deadlock
+func IsDeadlock(err error) bool {
+	return CodeOf(err) == Code{"TransientError", "Transaction", "DeadlockDetected"}
+}
+
+// IsLeaderSwitch reports whether a write was sent to a cluster member that is not the leader anymore
+func IsLeaderSwitch(err error) bool {
+	code := CodeOf(err).String()
+	return code == "Neo.ClientError.Cluster.NotALeader" || code == "Neo.ClientError.General.ForbiddenOnReadOnlyDatabase"
+}
+
+func is(err error, category Category) bool {
+	classified := Classify(err)
+	return classified != nil && classified.Category == category
+}
@@ -0,0 +1,131 @@
+package neo4jerrors_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/neo4jerrors"
+	"graphconnect/neo4jtest/fakebolt"
+	"testing"
+
+	neo4j4 "github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestClassify(outer *testing.T) {
+	outer.Run("classifies the errors of both drivers", func(t *testing.T) {
+		for _, test := range []struct {
+			err      error
+			category neo4jerrors.Category
+			code     string
+		}{
+			{&neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"}, neo4jerrors.Client, "Neo.ClientError.Statement.SyntaxError"},
+			{&neo4j4.Neo4jError{Code: "Neo.ClientError.Security.Unauthorized"}, neo4jerrors.Client, "Neo.ClientError.Security.Unauthorized"},
+			{&neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"}, neo4jerrors.Transient, "Neo.TransientError.Transaction.DeadlockDetected"},
+			{&neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.Terminated"}, neo4jerrors.Client, "Neo.TransientError.Transaction.Terminated"},
+			{&neo4j.Neo4jError{Code: "Neo.ClientError.Cluster.NotALeader"}, neo4jerrors.Transient, "Neo.ClientError.Cluster.NotALeader"},
+			{&neo4j.Neo4jError{Code: "Neo.DatabaseError.General.UnknownError"}, neo4jerrors.Database, "Neo.DatabaseError.General.UnknownError"},
+			{&neo4j.ConnectivityError{Inner: errors.New("connection refused")}, neo4jerrors.Connectivity, ""},
+			{fmt.Errorf("could not save: %w", &neo4j.Neo4jError{Code: "Neo.TransientError.General.DatabaseUnavailable"}), neo4jerrors.Transient, "Neo.TransientError.General.DatabaseUnavailable"},
+		} {
+			classified := neo4jerrors.Classify(test.err)
+
+			if classified == nil || classified.Category != test.category || classified.Code.String() != test.code {
+				t.Errorf("Expected %v to be a %s error with code %q, got: %+v", test.err, test.category, test.code, classified)
+				continue
+			}
+			if !errors.Is(classified, test.err) {
+				t.Errorf("Expected the classified error to wrap %v", test.err)
+			}
+		}
+	})
+	outer.Run("ignores other errors", func(t *testing.T) {
+		if classified := neo4jerrors.Classify(errors.New("boom")); classified != nil {
+			t.Errorf("Expected nil, got: %v", classified)
+		}
+		if classified := neo4jerrors.Classify(nil); classified != nil {
+			t.Errorf("Expected nil, got: %v", classified)
+		}
+	})
+	outer.Run("parses status codes", func(t *testing.T) {
+		code, ok := neo4jerrors.ParseCode("Neo.ClientError.Schema.ConstraintValidationFailed")
+
+		if !ok || code != (neo4jerrors.Code{Classification: "ClientError", Category: "Schema", Title: "ConstraintValidationFailed"}) {
+			t.Errorf("Expected the parts of the code, got: %+v", code)
+		}
+		if _, ok := neo4jerrors.ParseCode("Neo.ClientError.Schema"); ok {
+			t.Errorf("Expected malformed code to be rejected")
+		}
+	})
+}
+
+func TestPredicates(outer *testing.T) {
+	ctx := context.Background()
+	_, driver := workshoptest.StartFakeServer(outer, fakebolt.Script{
+		"CREATE (p:Person {name: $name})": {Failure: &fakebolt.Failure{
+			Code:    "Neo.ClientError.Schema.ConstraintValidationFailed",
+			Message: "Node(0) already exists with label `Person` and property `name` = 'Eric'",
+		}},
+	})
+	run := func(query string) error {
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+		_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			result, err := tx.Run(ctx, query, map[string]any{"name": "Eric"})
+			if err != nil {
+				return nil, err
+			}
+			return result.Consume(ctx)
+		})
+		return err
+	}
+
+	outer.Run("detects constraint violations", func(t *testing.T) {
+		err := run("CREATE (p:Person {name: $name})")
+
+		if !neo4jerrors.IsConstraintViolation(err) || !neo4jerrors.IsClientError(err) || neo4jerrors.IsSyntaxError(err) {
+			t.Errorf("Expected a constraint violation, got: %v", err)
+		}
+		if classified := neo4jerrors.Classify(err); classified == nil || classified.Message != "Node(0) already exists with label `Person` and property `name` = 'Eric'" {
+			t.Errorf("Expected the message of the server, got: %+v", classified)
+		}
+	})
+	outer.Run("detects syntax errors", func(t *testing.T) {
+		// the fake server answers the queries it does not know with a syntax error
+		err := run("CREATE (p:Person {name: $name)")
+
+		if !neo4jerrors.IsSyntaxError(err) || neo4jerrors.IsConstraintViolation(err) || neo4jerrors.IsTransient(err) {
+			t.Errorf("Expected a syntax error, got: %v", err)
+		}
+	})
+	outer.Run("detects transient errors", func(t *testing.T) {
+		deadlock := &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected"}
+		leaderSwitch := &neo4j4.Neo4jError{Code: "Neo.ClientError.Cluster.NotALeader"}
+
+		if !neo4jerrors.IsDeadlock(deadlock) || !neo4jerrors.IsTransient(deadlock) || neo4jerrors.IsLeaderSwitch(deadlock) {
+			t.Errorf("Expected a deadlock, got: %v", deadlock)
+		}
+		if !neo4jerrors.IsLeaderSwitch(leaderSwitch) || !neo4jerrors.IsTransient(leaderSwitch) || neo4jerrors.IsClientError(leaderSwitch) {
+			t.Errorf("Expected a leader switch, got: %v", leaderSwitch)
+		}
+	})
+	outer.Run("detects authentication errors", func(t *testing.T) {
+		server, err := fakebolt.Start(fakebolt.Script{}, fakebolt.WithAuth("neo4j", "s3cr3t"))
+		if err != nil {
+			t.Fatalf("Could not start fake server: %v", err)
+		}
+		defer server.Close()
+		driver, err := neo4j.NewDriverWithContext(server.BoltURI(), neo4j.BasicAuth("neo4j", "wrong", ""))
+		if err != nil {
+			t.Fatalf("Could not create driver: %v", err)
+		}
+		defer driver.Close(ctx)
+
+		err = driver.VerifyConnectivity(ctx)
+
+		if !neo4jerrors.IsAuthenticationError(err) || neo4jerrors.IsConnectivityError(err) {
+			t.Errorf("Expected an authentication error, got: %v", err)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+package observe
+
+import (
+	"context"
+	"graphconnect/go-driver/summary"
+	"time"
+
+	neo4j4 "github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// The wrappers below are the 4.x driver counterparts of WrapDriver, so that the 4.x lessons using
+// session.ReadTransaction or session.WriteTransaction are observed too
+// 4.x sessions do not take a context, the sinks get context.Background()
+
+// WrapDriverV4 returns a 4.x driver whose sessions send the records of their queries and transaction functions to the sinks
+func WrapDriverV4(driver neo4j4.Driver, sinks ...Sink) neo4j4.Driver {
+	return &observedDriverV4{Driver: driver, sinks: sinks}
+}
+
+type observedDriverV4 struct {
+	neo4j4.Driver
+	sinks []Sink
+}
+
+func (driver *observedDriverV4) NewSession(config neo4j4.SessionConfig) neo4j4.Session {
+	return driver.wrap(driver.Driver.NewSession(config), config.DatabaseName, config.AccessMode)
+}
+
+func (driver *observedDriverV4) Session(mode neo4j4.AccessMode, bookmarks ...string) (neo4j4.Session, error) {
+	session, err := driver.Driver.Session(mode, bookmarks...)
+	if err != nil {
+		return nil, err
+	}
+	return driver.wrap(session, "", mode), nil
+}
+
+func (driver *observedDriverV4) wrap(session neo4j4.Session, database string, mode neo4j4.AccessMode) neo4j4.Session {
+	return &observedSessionV4{Session: session, observer: observer{sinks: driver.sinks, database: database, accessMode: accessModeV4(mode)}}
+}
+
+type observedSessionV4 struct {
+	neo4j4.Session
+	observer observer
+	pending  pending
+}
+
+func (session *observedSessionV4) BeginTransaction(configurers ...func(*neo4j4.TransactionConfig)) (neo4j4.Transaction, error) {
+	tx, err := session.Session.BeginTransaction(configurers...)
+	if err != nil {
+		return nil, err
+	}
+	return &transactionV4{Transaction: tx, observer: session.observer, accessMode: session.observer.accessMode}, nil
+}
+
+func (session *observedSessionV4) ReadTransaction(work neo4j4.TransactionWork, configurers ...func(*neo4j4.TransactionConfig)) (any, error) {
+	return session.execute(Read, session.Session.ReadTransaction, work, configurers)
+}
+
+func (session *observedSessionV4) WriteTransaction(work neo4j4.TransactionWork, configurers ...func(*neo4j4.TransactionConfig)) (any, error) {
+	return session.execute(Write, session.Session.WriteTransaction, work, configurers)
+}
+
+// execute counts the attempts of the transaction function, see observedSession.execute
+func (session *observedSessionV4) execute(accessMode AccessMode,
+	execute func(neo4j4.TransactionWork, ...func(*neo4j4.TransactionConfig)) (any, error),
+	work neo4j4.TransactionWork, configurers []func(*neo4j4.TransactionConfig)) (any, error) {
+
+	start := time.Now()
+	attempts := 0
+	value, err := execute(func(tx neo4j4.Transaction) (any, error) {
+		attempts++
+		observed := &transactionV4{Transaction: tx, observer: session.observer, accessMode: accessMode, attempt: attempts}
+		defer observed.pending.finish()
+		return work(observed)
+	}, configurers...)
+	session.observer.transaction(context.Background(), accessMode, attempts, start, err)
+	return value, err
+}
+
+func (session *observedSessionV4) Run(cypher string, params map[string]any, configurers ...func(*neo4j4.TransactionConfig)) (neo4j4.Result, error) {
+	execution := session.observer.start(context.Background(), session.observer.accessMode, 0, cypher, params)
+	result, err := session.Session.Run(cypher, params, configurers...)
+	return observeResultV4(execution, result, err, &session.pending)
+}
+
+func (session *observedSessionV4) Close() error {
+	session.pending.finish()
+	return session.Session.Close()
+}
+
+// transactionV4 wraps both the explicit transactions and the ones of transaction functions, the 4.x driver has a single type
+type transactionV4 struct {
+	neo4j4.Transaction
+	observer   observer
+	accessMode AccessMode
+	attempt    int
+	pending    pending
+}
+
+func (tx *transactionV4) Run(cypher string, params map[string]any) (neo4j4.Result, error) {
+	execution := tx.observer.start(context.Background(), tx.accessMode, tx.attempt, cypher, params)
+	result, err := tx.Transaction.Run(cypher, params)
+	return observeResultV4(execution, result, err, &tx.pending)
+}
+
+func (tx *transactionV4) Commit() error {
+	tx.pending.finish()
+	return tx.Transaction.Commit()
+}
+
+func (tx *transactionV4) Rollback() error {
+	tx.pending.finish()
+	return tx.Transaction.Rollback()
+}
+
+func (tx *transactionV4) Close() error {
+	tx.pending.finish()
+	return tx.Transaction.Close()
+}
+
+func observeResultV4(execution *execution, result neo4j4.Result, err error, pending *pending) (neo4j4.Result, error) {
+	if err != nil {
+		execution.finish(summary.Counters{}, err)
+		return nil, err
+	}
+	observed := &observedResultV4{Result: result, execution: execution}
+	pending.add(execution, observed)
+	return observed, nil
+}
+
+// observedResultV4 finishes the execution of its query once all its records are read, see observedResult
+type observedResultV4 struct {
+	neo4j4.Result
+	execution *execution
+}
+
+func (result *observedResultV4) Next() bool {
+	if result.Result.Next() {
+		return true
+	}
+	result.finish()
+	return false
+}
+
+func (result *observedResultV4) NextRecord(record **neo4j4.Record) bool {
+	if result.Result.NextRecord(record) {
+		return true
+	}
+	result.finish()
+	return false
+}
+
+func (result *observedResultV4) Collect() ([]*neo4j4.Record, error) {
+	records, err := result.Result.Collect()
+	result.finish()
+	return records, err
+}
+
+func (result *observedResultV4) Single() (*neo4j4.Record, error) {
+	record, err := result.Result.Single()
+	result.finish()
+	return record, err
+}
+
+func (result *observedResultV4) Consume() (neo4j4.ResultSummary, error) {
+	summary, err := result.Result.Consume()
+	result.record(summary, err)
+	return summary, err
+}
+
+func (result *observedResultV4) finish() {
+	if result.execution.finished {
+		return
+	}
+	summary, err := result.Result.Consume()
+	result.record(summary, err)
+}
+
+func (result *observedResultV4) record(resultSummary neo4j4.ResultSummary, err error) {
+	var counters summary.Counters
+	if resultSummary != nil {
+		counters = summary.CountersOf(resultSummary.Counters())
+	}
+	result.execution.finish(counters, err)
+}
+
+func accessModeV4(mode neo4j4.AccessMode) AccessMode {
+	if mode == neo4j4.AccessModeRead {
+		return Read
+	}
+	return Write
+}
@@ -0,0 +1,77 @@
+package observe_test
+
+import (
+	"graphconnect/go-driver/observe"
+	"graphconnect/neo4jtest/fakebolt"
+	"testing"
+
+	neo4j4 "github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+func TestWrapDriverV4(outer *testing.T) {
+	server, err := fakebolt.Start(script)
+	if err != nil {
+		outer.Fatalf("Could not start fake server: %v", err)
+	}
+	defer func() {
+		if err := server.Close(); err != nil {
+			outer.Errorf("Could not stop fake server: %v", err)
+		}
+	}()
+	driver, err := neo4j4.NewDriver(server.BoltURI(), neo4j4.NoAuth())
+	if err != nil {
+		outer.Fatalf("Could not create driver: %v", err)
+	}
+	defer func() {
+		if err := driver.Close(); err != nil {
+			outer.Errorf("Could not close driver: %v", err)
+		}
+	}()
+
+	outer.Run("records the queries of ReadTransaction", func(t *testing.T) {
+		sink := &recordingSink{}
+		session := observe.WrapDriverV4(driver, sink).NewSession(neo4j4.SessionConfig{})
+		defer session.Close()
+
+		count, err := session.ReadTransaction(func(tx neo4j4.Transaction) (any, error) {
+			result, err := tx.Run(countQuery, nil)
+			if err != nil {
+				return nil, err
+			}
+			record, err := result.Single()
+			if err != nil {
+				return nil, err
+			}
+			return record.Values[0], nil
+		})
+
+		if err != nil || count != int64(4) {
+			t.Fatalf("Expected 4, got: %v (error: %v)", count, err)
+		}
+		queries, transactions := sink.records()
+		if len(queries) != 1 || queries[0].Text != countQuery || queries[0].AccessMode != observe.Read || queries[0].Attempt != 1 {
+			t.Errorf("Expected the first attempt of the read query, got: %+v", queries)
+		}
+		if len(transactions) != 1 || transactions[0].Attempts != 1 || transactions[0].Err != nil {
+			t.Errorf("Expected a successful transaction, got: %+v", transactions)
+		}
+	})
+	outer.Run("records the queries of WriteTransaction when the transaction ends", func(t *testing.T) {
+		sink := &recordingSink{}
+		session := observe.WrapDriverV4(driver, sink).NewSession(neo4j4.SessionConfig{})
+		defer session.Close()
+
+		_, err := session.WriteTransaction(func(tx neo4j4.Transaction) (any, error) {
+			_, err := tx.Run(createQuery, map[string]any{"name": "Eric", "since": 2021})
+			return nil, err
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		queries, _ := sink.records()
+		if len(queries) != 1 || queries[0].AccessMode != observe.Write || queries[0].Counters.NodesCreated != 1 {
+			t.Errorf("Expected the write query with its counters, got: %+v", queries)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package observe
+
+import (
+	"context"
+	"graphconnect/go-driver/summary"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// WrapDriver returns a driver whose sessions send the records of their queries and transaction functions to the sinks
+// Queries are recorded once their result is consumed, or when their transaction or session ends
+// note: neo4j.ExecuteQuery does not go through the session methods, its queries are not recorded
+func WrapDriver(driver neo4j.DriverWithContext, sinks ...Sink) neo4j.DriverWithContext {
+	return &observedDriver{DriverWithContext: driver, sinks: sinks}
+}
+
+// the wrappers embed the wrapped values, since the driver interfaces have unexported methods
+type observedDriver struct {
+	neo4j.DriverWithContext
+	sinks []Sink
+}
+
+func (driver *observedDriver) NewSession(ctx context.Context, config neo4j.SessionConfig) neo4j.SessionWithContext {
+	return &observedSession{
+		SessionWithContext: driver.DriverWithContext.NewSession(ctx, config),
+		observer:           observer{sinks: driver.sinks, database: config.DatabaseName, accessMode: accessMode(config.AccessMode)},
+	}
+}
+
+type observedSession struct {
+	neo4j.SessionWithContext
+	observer observer
+	pending  pending
+}
+
+func (session *observedSession) BeginTransaction(ctx context.Context, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ExplicitTransaction, error) {
+	tx, err := session.SessionWithContext.BeginTransaction(ctx, configurers...)
+	if err != nil {
+		return nil, err
+	}
+	return &explicitTransaction{ExplicitTransaction: tx, observer: session.observer}, nil
+}
+
+func (session *observedSession) ExecuteRead(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (any, error) {
+	return session.execute(ctx, Read, session.SessionWithContext.ExecuteRead, work, configurers)
+}
+
+func (session *observedSession) ExecuteWrite(ctx context.Context, work neo4j.ManagedTransactionWork, configurers ...func(*neo4j.TransactionConfig)) (any, error) {
+	return session.execute(ctx, Write, session.SessionWithContext.ExecuteWrite, work, configurers)
+}
+
+// execute counts the attempts of the transaction function, each attempt gets a transaction of its own
+func (session *observedSession) execute(ctx context.Context, accessMode AccessMode,
+	execute func(context.Context, neo4j.ManagedTransactionWork, ...func(*neo4j.TransactionConfig)) (any, error),
+	work neo4j.ManagedTransactionWork, configurers []func(*neo4j.TransactionConfig)) (any, error) {
+
+	start := time.Now()
+	attempts := 0
+	value, err := execute(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		attempts++
+		observed := &managedTransaction{ManagedTransaction: tx, observer: session.observer, accessMode: accessMode, attempt: attempts}
+		defer observed.pending.finish()
+		return work(observed)
+	}, configurers...)
+	session.observer.transaction(ctx, accessMode, attempts, start, err)
+	return value, err
+}
+
+func (session *observedSession) Run(ctx context.Context, cypher string, params map[string]any, configurers ...func(*neo4j.TransactionConfig)) (neo4j.ResultWithContext, error) {
+	execution := session.observer.start(ctx, session.observer.accessMode, 0, cypher, params)
+	result, err := session.SessionWithContext.Run(ctx, cypher, params, configurers...)
+	return observeResult(execution, result, err, &session.pending)
+}
+
+func (session *observedSession) Close(ctx context.Context) error {
+	session.pending.finish()
+	return session.SessionWithContext.Close(ctx)
+}
+
+type managedTransaction struct {
+	neo4j.ManagedTransaction
+	observer   observer
+	accessMode AccessMode
+	attempt    int
+	pending    pending
+}
+
+func (tx *managedTransaction) Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error) {
+	execution := tx.observer.start(ctx, tx.accessMode, tx.attempt, cypher, params)
+	result, err := tx.ManagedTransaction.Run(ctx, cypher, params)
+	return observeResult(execution, result, err, &tx.pending)
+}
+
+type explicitTransaction struct {
+	neo4j.ExplicitTransaction
+	observer observer
+	pending  pending
+}
+
+func (tx *explicitTransaction) Run(ctx context.Context, cypher string, params map[string]any) (neo4j.ResultWithContext, error) {
+	execution := tx.observer.start(ctx, tx.observer.accessMode, 0, cypher, params)
+	result, err := tx.ExplicitTransaction.Run(ctx, cypher, params)
+	return observeResult(execution, result, err, &tx.pending)
+}
+
+func (tx *explicitTransaction) Commit(ctx context.Context) error {
+	tx.pending.finish()
+	return tx.ExplicitTransaction.Commit(ctx)
+}
+
+func (tx *explicitTransaction) Rollback(ctx context.Context) error {
+	tx.pending.finish()
+	return tx.ExplicitTransaction.Rollback(ctx)
+}
+
+func (tx *explicitTransaction) Close(ctx context.Context) error {
+	tx.pending.finish()
+	return tx.ExplicitTransaction.Close(ctx)
+}
+
+func observeResult(execution *execution, result neo4j.ResultWithContext, err error, pending *pending) (neo4j.ResultWithContext, error) {
+	if err != nil {
+		execution.finish(summary.Counters{}, err)
+		return nil, err
+	}
+	observed := &observedResult{ResultWithContext: result, execution: execution}
+	pending.add(execution, observed)
+	return observed, nil
+}
+
+// observedResult finishes the execution of its query once all its records are read
+type observedResult struct {
+	neo4j.ResultWithContext
+	execution *execution
+}
+
+func (result *observedResult) Next(ctx context.Context) bool {
+	if result.ResultWithContext.Next(ctx) {
+		return true
+	}
+	result.end(ctx)
+	return false
+}
+
+func (result *observedResult) NextRecord(ctx context.Context, record **neo4j.Record) bool {
+	if result.ResultWithContext.NextRecord(ctx, record) {
+		return true
+	}
+	result.end(ctx)
+	return false
+}
+
+func (result *observedResult) Collect(ctx context.Context) ([]*neo4j.Record, error) {
+	records, err := result.ResultWithContext.Collect(ctx)
+	result.end(ctx)
+	return records, err
+}
+
+func (result *observedResult) Single(ctx context.Context) (*neo4j.Record, error) {
+	record, err := result.ResultWithContext.Single(ctx)
+	result.end(ctx)
+	return record, err
+}
+
+func (result *observedResult) Consume(ctx context.Context) (neo4j.ResultSummary, error) {
+	summary, err := result.ResultWithContext.Consume(ctx)
+	result.record(summary, err)
+	return summary, err
+}
+
+func (result *observedResult) finish() {
+	result.end(result.execution.ctx)
+}
+
+// end consumes the result to read its summary, the records already read are kept
+func (result *observedResult) end(ctx context.Context) {
+	if result.execution.finished {
+		return
+	}
+	summary, err := result.ResultWithContext.Consume(ctx)
+	result.record(summary, err)
+}
+
+func (result *observedResult) record(resultSummary neo4j.ResultSummary, err error) {
+	var counters summary.Counters
+	if resultSummary != nil {
+		counters = summary.CountersOf(resultSummary.Counters())
+	}
+	result.execution.finish(counters, err)
+}
+
+func accessMode(mode neo4j.AccessMode) AccessMode {
+	if mode == neo4j.AccessModeRead {
+		return Read
+	}
+	return Write
+}
@@ -0,0 +1,51 @@
+package observe
+
+import "context"
+
+// Logger logs messages with alternating keys and values, *slog.Logger implements it
+type Logger interface {
+	Info(msg string, args ...any)
+	Error(msg string, args ...any)
+}
+
+// LogSink logs each query and transaction function, failed ones at the error level
+type LogSink struct {
+	logger Logger
+}
+
+// NewLogSink returns a LogSink logging with the logger
+func NewLogSink(logger Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+func (sink *LogSink) Query(_ context.Context, query Query) {
+	args := []any{
+		"query", query.Text,
+		"parameters", query.ParameterKeys,
+		"database", query.Database,
+		"mode", string(query.AccessMode),
+		"attempt", query.Attempt,
+		"duration", query.Duration,
+		"counters", query.Counters.String(),
+	}
+	if query.Err != nil {
+		sink.logger.Error("neo4j query failed", append(args, "error", query.Err)...)
+		return
+	}
+	sink.logger.Info("neo4j query", args...)
+}
+
+func (sink *LogSink) Transaction(_ context.Context, transaction Transaction) {
+	args := []any{
+		"database", transaction.Database,
+		"mode", string(transaction.AccessMode),
+		"attempts", transaction.Attempts,
+		"retries", transaction.Retries(),
+		"duration", transaction.Duration,
+	}
+	if transaction.Err != nil {
+		sink.logger.Error("neo4j transaction failed", append(args, "error", transaction.Err)...)
+		return
+	}
+	sink.logger.Info("neo4j transaction", args...)
+}
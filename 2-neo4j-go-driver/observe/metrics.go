@@ -0,0 +1,150 @@
+package observe
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultBuckets are the upper bounds of the query duration histogram, in seconds, as in the Prometheus client
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// Metrics counts the queries and transaction functions, and their durations, by database, access mode and outcome
+// It serves them in the Prometheus text format, e.g. with http.Handle("/metrics", metrics)
+type Metrics struct {
+	mutex        sync.Mutex
+	buckets      []float64
+	queries      map[labels]int
+	durations    map[labels]*histogram
+	transactions map[labels]int
+	retries      map[labels]int
+}
+
+type labels struct {
+	database   string
+	accessMode AccessMode
+	outcome    string
+}
+
+type histogram struct {
+	counts []int
+	sum    float64
+	count  int
+}
+
+// NewMetrics returns empty Metrics, the duration histogram uses the buckets, or DefaultBuckets when none is given
+func NewMetrics(buckets ...float64) *Metrics {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+	return &Metrics{
+		buckets:      buckets,
+		queries:      map[labels]int{},
+		durations:    map[labels]*histogram{},
+		transactions: map[labels]int{},
+		retries:      map[labels]int{},
+	}
+}
+
+func (metrics *Metrics) Query(_ context.Context, query Query) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.queries[labels{query.Database, query.AccessMode, outcome(query.Err)}]++
+	key := labels{database: query.Database, accessMode: query.AccessMode}
+	durations, found := metrics.durations[key]
+	if !found {
+		durations = &histogram{counts: make([]int, len(metrics.buckets))}
+		metrics.durations[key] = durations
+	}
+	seconds := query.Duration.Seconds()
+	for i, bound := range metrics.buckets {
+		if seconds <= bound {
+			durations.counts[i]++
+		}
+	}
+	durations.sum += seconds
+	durations.count++
+}
+
+func (metrics *Metrics) Transaction(_ context.Context, transaction Transaction) {
+	metrics.mutex.Lock()
+	defer metrics.mutex.Unlock()
+	metrics.transactions[labels{transaction.Database, transaction.AccessMode, outcome(transaction.Err)}]++
+	metrics.retries[labels{database: transaction.Database, accessMode: transaction.AccessMode}] += transaction.Retries()
+}
+
+// WriteTo writes the metrics in the Prometheus text format
+func (metrics *Metrics) WriteTo(writer io.Writer) (int64, error) {
+	var output bytes.Buffer
+	metrics.mutex.Lock()
+	writeCounter(&output, "neo4j_queries_total", "Queries run, by outcome", metrics.queries)
+	output.WriteString("# HELP neo4j_query_duration_seconds Time from the start of the queries to the end of their result\n")
+	output.WriteString("# TYPE neo4j_query_duration_seconds histogram\n")
+	for _, key := range sortedLabels(metrics.durations) {
+		durations := metrics.durations[key]
+		for i, bound := range metrics.buckets {
+			fmt.Fprintf(&output, "neo4j_query_duration_seconds_bucket{%s,le=\"%s\"} %d\n", key, formatFloat(bound), durations.counts[i])
+		}
+		fmt.Fprintf(&output, "neo4j_query_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", key, durations.count)
+		fmt.Fprintf(&output, "neo4j_query_duration_seconds_sum{%s} %s\n", key, formatFloat(durations.sum))
+		fmt.Fprintf(&output, "neo4j_query_duration_seconds_count{%s} %d\n", key, durations.count)
+	}
+	writeCounter(&output, "neo4j_transactions_total", "Transaction functions run, by outcome", metrics.transactions)
+	writeCounter(&output, "neo4j_transaction_retries_total", "Retries of the transaction functions", metrics.retries)
+	metrics.mutex.Unlock()
+	return output.WriteTo(writer)
+}
+
+// ServeHTTP serves the metrics in the Prometheus text format
+func (metrics *Metrics) ServeHTTP(writer http.ResponseWriter, _ *http.Request) {
+	writer.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = metrics.WriteTo(writer)
+}
+
+func (key labels) String() string {
+	pairs := []string{
+		fmt.Sprintf("database=%q", key.database),
+		fmt.Sprintf("mode=%q", string(key.accessMode)),
+	}
+	if key.outcome != "" {
+		pairs = append(pairs, fmt.Sprintf("outcome=%q", key.outcome))
+	}
+	return strings.Join(pairs, ",")
+}
+
+func writeCounter(output *bytes.Buffer, name, help string, values map[labels]int) {
+	fmt.Fprintf(output, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+	for _, key := range sortedLabels(values) {
+		fmt.Fprintf(output, "%s{%s} %d\n", name, key, values[key])
+	}
+}
+
+func sortedLabels[V any](values map[labels]V) []labels {
+	keys := make([]labels, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].String() < keys[j].String()
+	})
+	return keys
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return "error"
+	}
+	return "success"
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,169 @@
+// Package observe wraps the driver, so that the queries run by its sessions and transactions are logged, traced or measured
+//
+//	driver = observe.WrapDriver(driver, observe.NewLogSink(slog.Default()), metrics)
+//
+// The code using the wrapped driver is left unchanged
+package observe
+
+import (
+	"context"
+	"graphconnect/go-driver/summary"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Query is what the wrappers record about a query, once its result is consumed or fails
+type Query struct {
+	Text string
+	// ParameterKeys are the sorted names of the parameters, their values are not recorded since they may be sensitive
+	ParameterKeys []string
+	// Database is the database of the session, empty for the default database
+	Database   string
+	AccessMode AccessMode
+	// Attempt is the attempt of the transaction function running the query, starting at 1
+	// It is 0 for the queries of auto-commit and explicit transactions, which are not retried
+	Attempt int
+	Start   time.Time
+	// Duration runs from the start of the query to the end of its result
+	Duration time.Duration
+	Counters summary.Counters
+	Err      error
+}
+
+// Transaction is what the wrappers record about a transaction function, e.g. session.ExecuteRead
+type Transaction struct {
+	Database   string
+	AccessMode AccessMode
+	// Attempts counts the calls of the transaction function, the driver retries it on transient errors
+	Attempts int
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// Retries returns the number of times the transaction function was retried
+func (transaction Transaction) Retries() int {
+	if transaction.Attempts < 1 {
+		return 0
+	}
+	return transaction.Attempts - 1
+}
+
+// AccessMode tells whether the query or transaction reads or writes
+type AccessMode string
+
+const (
+	Read  AccessMode = "READ"
+	Write AccessMode = "WRITE"
+)
+
+// Sink receives the records of the wrappers, it may be called concurrently by several sessions
+type Sink interface {
+	Query(ctx context.Context, query Query)
+	Transaction(ctx context.Context, transaction Transaction)
+}
+
+// observer sends the records of the queries and transactions of a session to the sinks
+type observer struct {
+	sinks      []Sink
+	database   string
+	accessMode AccessMode
+}
+
+func (observer observer) transaction(ctx context.Context, accessMode AccessMode, attempts int, start time.Time, err error) {
+	transaction := Transaction{
+		Database:   observer.database,
+		AccessMode: accessMode,
+		Attempts:   attempts,
+		Start:      start,
+		Duration:   time.Since(start),
+		Err:        err,
+	}
+	for _, sink := range observer.sinks {
+		sink.Transaction(ctx, transaction)
+	}
+}
+
+// start returns the execution of a query, whose record is sent once finished
+func (observer observer) start(ctx context.Context, accessMode AccessMode, attempt int, text string, parameters map[string]any) *execution {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return &execution{ctx: ctx, sinks: observer.sinks, query: Query{
+		Text:          text,
+		ParameterKeys: keys,
+		Database:      observer.database,
+		AccessMode:    accessMode,
+		Attempt:       attempt,
+		Start:         time.Now(),
+	}}
+}
+
+// execution is a query whose result is not consumed yet
+type execution struct {
+	ctx      context.Context
+	sinks    []Sink
+	query    Query
+	finished bool
+	// onFinish runs once the record is sent, e.g. to stop tracking the result as pending
+	onFinish func()
+}
+
+// finish sends the record of the query, only once
+func (execution *execution) finish(counters summary.Counters, err error) {
+	if execution.finished {
+		return
+	}
+	execution.finished = true
+	execution.query.Duration = time.Since(execution.query.Start)
+	execution.query.Counters = counters
+	execution.query.Err = err
+	for _, sink := range execution.sinks {
+		sink.Query(execution.ctx, execution.query)
+	}
+	if execution.onFinish != nil {
+		execution.onFinish()
+	}
+}
+
+// pending tracks the results of a session or a transaction that are not consumed yet
+// The driver consumes them before committing or closing, the wrappers do it first so that they are recorded
+type pending struct {
+	mutex   sync.Mutex
+	results []interface{ finish() }
+}
+
+// add tracks the result until the execution of its query finishes
+// note: long-lived sessions run many auto-commit queries, keeping their consumed results would leak them
+func (pending *pending) add(execution *execution, result interface{ finish() }) {
+	pending.mutex.Lock()
+	defer pending.mutex.Unlock()
+	pending.results = append(pending.results, result)
+	execution.onFinish = func() {
+		pending.remove(result)
+	}
+}
+
+func (pending *pending) remove(result interface{ finish() }) {
+	pending.mutex.Lock()
+	defer pending.mutex.Unlock()
+	for i, other := range pending.results {
+		if other == result {
+			pending.results = append(pending.results[:i], pending.results[i+1:]...)
+			return
+		}
+	}
+}
+
+func (pending *pending) finish() {
+	pending.mutex.Lock()
+	results := pending.results
+	pending.results = nil
+	pending.mutex.Unlock()
+	for _, result := range results {
+		result.finish()
+	}
+}
@@ -0,0 +1,192 @@
+package observe_test
+
+import (
+	"context"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/observe"
+	"graphconnect/go-driver/summary"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const (
+	createQuery = "CREATE (p:Person {name: $name, since: $since}) RETURN p.name AS name"
+	countQuery  = "MATCH (p:Person) RETURN count(p) AS count"
+)
+
+var script = fakebolt.Script{
+	createQuery: {
+		Keys:     []string{"name"},
+		Records:  [][]any{{"Eric"}},
+		Counters: map[string]int{"nodes-created": 1, "properties-set": 2, "labels-added": 1},
+	},
+	countQuery: {Keys: []string{"count"}, Records: [][]any{{4}}},
+}
+
+func TestWrapDriver(outer *testing.T) {
+	ctx := context.Background()
+	parameters := map[string]any{"since": 2021, "name": "Eric"}
+
+	outer.Run("records the queries of transaction functions", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		sink := &recordingSink{}
+		session := observe.WrapDriver(driver, sink).NewSession(ctx, neo4j.SessionConfig{DatabaseName: "neo4j"})
+		defer session.Close(ctx)
+
+		_, err := session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			result, err := tx.Run(ctx, createQuery, parameters)
+			if err != nil {
+				return nil, err
+			}
+			return result.Single(ctx)
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		queries, transactions := sink.records()
+		if len(queries) != 1 {
+			t.Fatalf("Expected 1 query, got: %v", queries)
+		}
+		query := queries[0]
+		if query.Text != createQuery || !reflect.DeepEqual(query.ParameterKeys, []string{"name", "since"}) {
+			t.Errorf("Expected the query text and its sorted parameter keys, got: %q %v", query.Text, query.ParameterKeys)
+		}
+		if query.Database != "neo4j" || query.AccessMode != observe.Write || query.Attempt != 1 || query.Err != nil {
+			t.Errorf("Expected a successful first attempt to write to neo4j, got: %+v", query)
+		}
+		if expected := (summary.Counters{NodesCreated: 1, PropertiesSet: 2, LabelsAdded: 1}); query.Counters != expected {
+			t.Errorf("Expected counters %+v, got: %+v", expected, query.Counters)
+		}
+		if len(transactions) != 1 || transactions[0].Attempts != 1 || transactions[0].Retries() != 0 {
+			t.Errorf("Expected a transaction without retry, got: %+v", transactions)
+		}
+	})
+	outer.Run("counts the retries of transaction functions", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		sink := &recordingSink{}
+		session := observe.WrapDriver(driver, sink).NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+
+		_, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			result, err := tx.Run(ctx, countQuery, nil)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := result.Collect(ctx); err != nil {
+				return nil, err
+			}
+			if queries, _ := sink.records(); len(queries) == 1 {
+				return nil, &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected", Msg: "deadlock"}
+			}
+			return nil, nil
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		queries, transactions := sink.records()
+		if len(queries) != 2 || queries[0].Attempt != 1 || queries[1].Attempt != 2 {
+			t.Errorf("Expected the query to be recorded on both attempts, got: %+v", queries)
+		}
+		if len(transactions) != 1 || transactions[0].AccessMode != observe.Read || transactions[0].Retries() != 1 {
+			t.Errorf("Expected a read transaction retried once, got: %+v", transactions)
+		}
+	})
+	outer.Run("records unconsumed results when the session closes", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		sink := &recordingSink{}
+		session := observe.WrapDriver(driver, sink).NewSession(ctx, neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+
+		if _, err := session.Run(ctx, countQuery, nil); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if queries, _ := sink.records(); len(queries) != 0 {
+			t.Errorf("Expected no query before the result is consumed, got: %+v", queries)
+		}
+		if err := session.Close(ctx); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+
+		queries, transactions := sink.records()
+		if len(queries) != 1 || queries[0].AccessMode != observe.Read || queries[0].Attempt != 0 || queries[0].Err != nil {
+			t.Errorf("Expected the auto-commit query, got: %+v", queries)
+		}
+		if len(transactions) != 0 {
+			t.Errorf("Expected no transaction function, got: %+v", transactions)
+		}
+	})
+	outer.Run("records the queries of explicit transactions", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		sink := &recordingSink{}
+		session := observe.WrapDriver(driver, sink).NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+
+		tx, err := session.BeginTransaction(ctx)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if _, err := tx.Run(ctx, createQuery, parameters); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+
+		queries, _ := sink.records()
+		if len(queries) != 1 || queries[0].Counters.NodesCreated != 1 {
+			t.Errorf("Expected the query with its counters, got: %+v", queries)
+		}
+	})
+	outer.Run("records failed queries", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		sink := &recordingSink{}
+		session := observe.WrapDriver(driver, sink).NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+
+		_, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			result, err := tx.Run(ctx, "MATCH (p:Person RETURN p", nil)
+			if err != nil {
+				return nil, err
+			}
+			return result.Collect(ctx)
+		})
+
+		queries, transactions := sink.records()
+		if err == nil || len(queries) != 1 || queries[0].Err == nil {
+			t.Errorf("Expected the query to fail, got: %+v", queries)
+		}
+		if len(transactions) != 1 || transactions[0].Err == nil || transactions[0].Attempts != 1 {
+			t.Errorf("Expected the transaction to fail without retry, got: %+v", transactions)
+		}
+	})
+}
+
+// recordingSink keeps the records it receives
+type recordingSink struct {
+	mutex        sync.Mutex
+	queries      []observe.Query
+	transactions []observe.Transaction
+}
+
+func (sink *recordingSink) Query(_ context.Context, query observe.Query) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	sink.queries = append(sink.queries, query)
+}
+
+func (sink *recordingSink) Transaction(_ context.Context, transaction observe.Transaction) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	sink.transactions = append(sink.transactions, transaction)
+}
+
+func (sink *recordingSink) records() ([]observe.Query, []observe.Transaction) {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+	return append([]observe.Query(nil), sink.queries...), append([]observe.Transaction(nil), sink.transactions...)
+}
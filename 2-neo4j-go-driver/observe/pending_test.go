@@ -0,0 +1,37 @@
+package observe
+
+import (
+	"context"
+	"graphconnect/go-driver/summary"
+	"testing"
+)
+
+// stubResult finishes its execution like the observed results do
+type stubResult struct {
+	execution *execution
+}
+
+func (result *stubResult) finish() {
+	result.execution.finish(summary.Counters{}, nil)
+}
+
+func TestPending(outer *testing.T) {
+	outer.Run("stops tracking the results once their execution finishes", func(t *testing.T) {
+		var pending pending
+		results := make([]*stubResult, 3)
+		for i := range results {
+			results[i] = &stubResult{execution: observer{}.start(context.Background(), Read, 0, "RETURN 1", nil)}
+			pending.add(results[i].execution, results[i])
+		}
+
+		results[1].finish()
+
+		if len(pending.results) != 2 || pending.results[0] != results[0] || pending.results[1] != results[2] {
+			t.Errorf("Expected the first and last results to be pending, got: %v", pending.results)
+		}
+		pending.finish()
+		if len(pending.results) != 0 || !results[0].execution.finished || !results[2].execution.finished {
+			t.Errorf("Expected all the results to be finished, got: %v", pending.results)
+		}
+	})
+}
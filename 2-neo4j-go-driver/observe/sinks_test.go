@@ -0,0 +1,187 @@
+package observe_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"graphconnect/go-driver/observe"
+	"graphconnect/go-driver/summary"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2022, 10, 5, 9, 30, 0, 0, time.UTC)
+
+var query = observe.Query{
+	Text:          "MATCH (p:Person {name: $name}) RETURN p",
+	ParameterKeys: []string{"name"},
+	Database:      "neo4j",
+	AccessMode:    observe.Read,
+	Attempt:       1,
+	Start:         start,
+	Duration:      20 * time.Millisecond,
+}
+
+var transaction = observe.Transaction{
+	Database:   "neo4j",
+	AccessMode: observe.Write,
+	Attempts:   3,
+	Start:      start,
+	Duration:   2 * time.Second,
+	Err:        errors.New("deadlock"),
+}
+
+func TestLogSink(outer *testing.T) {
+	outer.Run("logs queries", func(t *testing.T) {
+		logger := &fakeLogger{}
+
+		observe.NewLogSink(logger).Query(context.Background(), query)
+
+		expected := []string{`INFO neo4j query [query MATCH (p:Person {name: $name}) RETURN p parameters [name] database neo4j mode READ attempt 1 duration 20ms counters no updates]`}
+		if !reflect.DeepEqual(logger.lines, expected) {
+			t.Errorf("Expected %q, got: %q", expected, logger.lines)
+		}
+	})
+	outer.Run("logs failed transactions as errors", func(t *testing.T) {
+		logger := &fakeLogger{}
+
+		observe.NewLogSink(logger).Transaction(context.Background(), transaction)
+
+		expected := []string{`ERROR neo4j transaction failed [database neo4j mode WRITE attempts 3 retries 2 duration 2s error deadlock]`}
+		if !reflect.DeepEqual(logger.lines, expected) {
+			t.Errorf("Expected %q, got: %q", expected, logger.lines)
+		}
+	})
+}
+
+func TestTraceSink(outer *testing.T) {
+	outer.Run("traces queries", func(t *testing.T) {
+		tracer := &fakeTracer{}
+		written := query
+		written.Text = "CREATE (p:Person) RETURN p"
+		written.Counters = summary.Counters{NodesCreated: 1, LabelsAdded: 1}
+
+		observe.NewTraceSink(tracer).Query(context.Background(), written)
+
+		if len(tracer.spans) != 1 {
+			t.Fatalf("Expected 1 span, got: %d", len(tracer.spans))
+		}
+		span := tracer.spans[0]
+		if span.name != "neo4j CREATE" || span.start != start || span.end != start.Add(20*time.Millisecond) {
+			t.Errorf("Expected the CREATE span to last 20ms, got: %+v", span)
+		}
+		for key, value := range map[string]any{
+			"db.system":                "neo4j",
+			"db.statement":             "CREATE (p:Person) RETURN p",
+			"db.neo4j.attempt":         1,
+			"db.neo4j.nodes_created":   1,
+			"db.neo4j.labels_added":    1,
+			"db.neo4j.properties_set":  nil,
+			"db.neo4j.access_mode":     "READ",
+			"db.neo4j.parameters":      []string{"name"},
+			"db.name":                  "neo4j",
+			"db.operation":             "CREATE",
+			"db.neo4j.nodes_deleted":   nil,
+			"db.neo4j.indexes_removed": nil,
+		} {
+			if !reflect.DeepEqual(span.attributes[key], value) {
+				t.Errorf("Expected attribute %s to be %v, got: %v", key, value, span.attributes[key])
+			}
+		}
+	})
+	outer.Run("traces failed transactions", func(t *testing.T) {
+		tracer := &fakeTracer{}
+
+		observe.NewTraceSink(tracer).Transaction(context.Background(), transaction)
+
+		span := tracer.spans[0]
+		if span.name != "neo4j transaction" || span.attributes["db.neo4j.retries"] != 2 || span.err != transaction.Err {
+			t.Errorf("Expected a failed transaction span with 2 retries, got: %+v", span)
+		}
+	})
+}
+
+func TestMetrics(outer *testing.T) {
+	outer.Run("writes the Prometheus text format", func(t *testing.T) {
+		metrics := observe.NewMetrics(0.01, 0.1)
+		failed := query
+		failed.Duration = 50 * time.Millisecond
+		failed.Err = errors.New("syntax error")
+
+		metrics.Query(context.Background(), query)
+		metrics.Query(context.Background(), failed)
+		metrics.Transaction(context.Background(), transaction)
+		var output bytes.Buffer
+		if _, err := metrics.WriteTo(&output); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+
+		expected := strings.Join([]string{
+			"# HELP neo4j_queries_total Queries run, by outcome",
+			"# TYPE neo4j_queries_total counter",
+			`neo4j_queries_total{database="neo4j",mode="READ",outcome="error"} 1`,
+			`neo4j_queries_total{database="neo4j",mode="READ",outcome="success"} 1`,
+			"# HELP neo4j_query_duration_seconds Time from the start of the queries to the end of their result",
+			"# TYPE neo4j_query_duration_seconds histogram",
+			`neo4j_query_duration_seconds_bucket{database="neo4j",mode="READ",le="0.01"} 0`,
+			`neo4j_query_duration_seconds_bucket{database="neo4j",mode="READ",le="0.1"} 2`,
+			`neo4j_query_duration_seconds_bucket{database="neo4j",mode="READ",le="+Inf"} 2`,
+			`neo4j_query_duration_seconds_sum{database="neo4j",mode="READ"} 0.07`,
+			`neo4j_query_duration_seconds_count{database="neo4j",mode="READ"} 2`,
+			"# HELP neo4j_transactions_total Transaction functions run, by outcome",
+			"# TYPE neo4j_transactions_total counter",
+			`neo4j_transactions_total{database="neo4j",mode="WRITE",outcome="error"} 1`,
+			"# HELP neo4j_transaction_retries_total Retries of the transaction functions",
+			"# TYPE neo4j_transaction_retries_total counter",
+			`neo4j_transaction_retries_total{database="neo4j",mode="WRITE"} 2`,
+		}, "\n") + "\n"
+		if output.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, output.String())
+		}
+	})
+}
+
+// fakeLogger formats the messages like "INFO msg [key value ...]"
+type fakeLogger struct {
+	lines []string
+}
+
+func (logger *fakeLogger) Info(msg string, args ...any) {
+	logger.lines = append(logger.lines, "INFO "+msg+" "+fmt.Sprintf("%v", args))
+}
+
+func (logger *fakeLogger) Error(msg string, args ...any) {
+	logger.lines = append(logger.lines, "ERROR "+msg+" "+fmt.Sprintf("%v", args))
+}
+
+type fakeTracer struct {
+	spans []*fakeSpan
+}
+
+type fakeSpan struct {
+	name       string
+	start, end time.Time
+	attributes map[string]any
+	err        error
+}
+
+func (tracer *fakeTracer) Start(_ context.Context, name string, start time.Time) observe.Span {
+	span := &fakeSpan{name: name, start: start, attributes: map[string]any{}}
+	tracer.spans = append(tracer.spans, span)
+	return span
+}
+
+func (span *fakeSpan) SetAttribute(key string, value any) {
+	span.attributes[key] = value
+}
+
+func (span *fakeSpan) RecordError(err error) {
+	span.err = err
+}
+
+func (span *fakeSpan) End(end time.Time) {
+	span.end = end
+}
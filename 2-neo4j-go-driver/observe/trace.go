@@ -0,0 +1,81 @@
+package observe
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+// Tracer starts spans, a few lines adapt an OpenTelemetry trace.Tracer to it
+//
+//	func (tracer otelTracer) Start(ctx context.Context, name string, start time.Time) observe.Span {
+//		_, span := tracer.Tracer.Start(ctx, name, trace.WithTimestamp(start), trace.WithSpanKind(trace.SpanKindClient))
+//		return otelSpan{span}
+//	}
+type Tracer interface {
+	Start(ctx context.Context, name string, start time.Time) Span
+}
+
+// Span is a traced operation, attribute values are strings, ints or string slices
+type Span interface {
+	SetAttribute(key string, value any)
+	RecordError(err error)
+	End(end time.Time)
+}
+
+// TraceSink turns each query and transaction function into a span, named after the OpenTelemetry database conventions
+// The spans are children of the span of the context given to the driver, they are started once the query is over
+type TraceSink struct {
+	tracer Tracer
+}
+
+// NewTraceSink returns a TraceSink starting spans with the tracer
+func NewTraceSink(tracer Tracer) *TraceSink {
+	return &TraceSink{tracer: tracer}
+}
+
+func (sink *TraceSink) Query(ctx context.Context, query Query) {
+	span := sink.tracer.Start(ctx, "neo4j "+operation(query.Text), query.Start)
+	span.SetAttribute("db.system", "neo4j")
+	span.SetAttribute("db.name", query.Database)
+	span.SetAttribute("db.operation", operation(query.Text))
+	span.SetAttribute("db.statement", query.Text)
+	span.SetAttribute("db.neo4j.parameters", query.ParameterKeys)
+	span.SetAttribute("db.neo4j.access_mode", string(query.AccessMode))
+	span.SetAttribute("db.neo4j.attempt", query.Attempt)
+	for _, counter := range query.Counters.List() {
+		if counter.Count > 0 {
+			span.SetAttribute("db.neo4j."+counter.Name, counter.Count)
+		}
+	}
+	if query.Err != nil {
+		span.RecordError(query.Err)
+	}
+	span.End(query.Start.Add(query.Duration))
+}
+
+func (sink *TraceSink) Transaction(ctx context.Context, transaction Transaction) {
+	span := sink.tracer.Start(ctx, "neo4j transaction", transaction.Start)
+	span.SetAttribute("db.system", "neo4j")
+	span.SetAttribute("db.name", transaction.Database)
+	span.SetAttribute("db.neo4j.access_mode", string(transaction.AccessMode))
+	span.SetAttribute("db.neo4j.attempts", transaction.Attempts)
+	span.SetAttribute("db.neo4j.retries", transaction.Retries())
+	if transaction.Err != nil {
+		span.RecordError(transaction.Err)
+	}
+	span.End(transaction.Start.Add(transaction.Duration))
+}
+
+// operation returns the first keyword of the query, e.g. MATCH or CREATE, in upper case
+func operation(query string) string {
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return ""
+	}
+	keyword := fields[0]
+	if end := strings.IndexAny(keyword, "({"); end > 0 {
+		keyword = keyword[:end]
+	}
+	return strings.ToUpper(keyword)
+}
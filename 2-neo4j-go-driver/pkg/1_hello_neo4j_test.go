@@ -3,11 +3,10 @@ package workshop_test
 import (
 	"context"
 	"fmt"
-	workshop "graphconnect/go-driver/pkg"
+	"graphconnect/neo4jtest"
 	"testing"
 
-	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
-	"github.com/testcontainers/testcontainers-go"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 )
 
 const username = "neo4j"
@@ -15,33 +14,16 @@ const password = "s3cr3t"
 
 func TestNeo4jDriverConnectivity(outer *testing.T) {
 
-	ctx := context.Background()
-
-	neo4jContainer, err := workshop.StartNeo4jContainer(ctx, workshop.ContainerConfiguration{
-		Neo4jVersion: "4.4",
-		Username:     username,
-		Password:     password,
-	})
-	if err != nil {
-		outer.Errorf("Could not start container: %v", err)
-	}
-	defer func() {
-		err := neo4jContainer.Terminate(ctx)
-		if err != nil {
-			outer.Fatalf("Could not stop container: %v", err)
-		}
-	}()
-	// Run `go test -v -run TestNeo4jDriverConnectivity/'creates a Neo4j driver and verify connectivity' ./2-neo4j-go-driver/...`
+	neo4jServer := neo4jtest.Isolate(outer)
+	// Run `go test -v -run TestNeo4jDriverConnectivity/'creates a Neo4j driver and verify connectivity' ./2-neo4j-go-driver/pkg/...`
 	outer.Run("creates a Neo4j driver and verify connectivity", func(t *testing.T) {
+		// every I/O operation of the 5.x driver takes a context, to cancel it or to set a deadline
+		ctx := context.Background()
 		// TODO: fix the createDriver function below
-		driver := createDriver(t, ctx, neo4jContainer)
-		defer func() {
-			if err := driver.Close(); err != nil {
-				t.Fatalf("Could not close driver: %v", err)
-			}
-		}()
+		driver := createDriver(t, neo4jServer)
+		defer closeDriver(ctx, t, driver)
 
-		err := driver.VerifyConnectivity()
+		err := driver.VerifyConnectivity(ctx)
 
 		if err != nil {
 			t.Fatalf("Expected driver to connect to the container but did not: %v", err)
@@ -49,13 +31,22 @@ func TestNeo4jDriverConnectivity(outer *testing.T) {
 	})
 }
 
-func createDriver(t *testing.T, ctx context.Context, container testcontainers.Container) neo4j.Driver {
-	port, err := container.MappedPort(ctx, "7687")
-	if err != nil {
-		t.Fatalf("Could not get mapped Bolt port: %v", err)
-	}
-	uri := fmt.Sprintf("neo4j://localhost:%d", port.Int())
+func createDriver(t *testing.T, server neo4jtest.Neo4jServer) neo4j.DriverWithContext {
+	uri := server.BoltURI()
+	username, password := server.Credentials()
 	auth := neo4j.BasicAuth(username, password, "")
-	// TODO: create the driver to connect to the running container
+	// TODO: create the driver to connect to the running server
 	panic(fmt.Errorf("connect driver to %s with %v", uri, auth))
 }
+
+func closeDriver(ctx context.Context, t *testing.T, driver neo4j.DriverWithContext) {
+	if err := driver.Close(ctx); err != nil {
+		t.Fatalf("Could not close driver: %v", err)
+	}
+}
+
+func closeSession(ctx context.Context, t *testing.T, session neo4j.SessionWithContext) {
+	if err := session.Close(ctx); err != nil {
+		t.Fatalf("Could not close session: %v", err)
+	}
+}
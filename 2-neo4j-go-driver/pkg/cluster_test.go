@@ -0,0 +1,79 @@
+package workshop_test
+
+import (
+	"context"
+	"graphconnect/neo4jtest"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestNeo4jDriverRouting(outer *testing.T) {
+
+	cluster := neo4jtest.Cluster(outer, neo4jtest.ClusterTopology{Cores: 3, ReadReplicas: 1})
+	ctx := context.Background()
+	// Run `NEO4J_TEST_CLUSTER=yes go test -v -run TestNeo4jDriverRouting ./2-neo4j-go-driver/pkg/...`
+	outer.Run("routes reads and writes across the cluster members", func(t *testing.T) {
+		driver := createDriver(t, cluster)
+		defer closeDriver(ctx, t, driver)
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer closeSession(ctx, t, session)
+
+		if _, err := session.ExecuteWrite(ctx, createConference(ctx, "GraphConnect")); err != nil {
+			t.Fatalf("Expected write to reach the leader, got: %v", err)
+		}
+		// the session bookmark makes the read wait until the write is replicated
+		name, err := session.ExecuteRead(ctx, findConference(ctx, "GraphConnect"))
+
+		if err != nil {
+			t.Fatalf("Expected read to succeed, got: %v", err)
+		}
+		if name != "GraphConnect" {
+			t.Errorf("Expected to read GraphConnect, got: %v", name)
+		}
+	})
+
+	outer.Run("routes writes to the new leader after a leader switch", func(t *testing.T) {
+		driver := createDriver(t, cluster)
+		defer closeDriver(ctx, t, driver)
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer closeSession(ctx, t, session)
+		leader, err := cluster.Leader()
+		if err != nil {
+			t.Fatalf("Could not find leader: %v", err)
+		}
+
+		if err := leader.Terminate(ctx); err != nil {
+			t.Fatalf("Could not stop leader: %v", err)
+		}
+		_, err = session.ExecuteWrite(ctx, createConference(ctx, "GopherCon"))
+
+		if err != nil {
+			t.Errorf("Expected write to reach the new leader, got: %v", err)
+		}
+	})
+}
+
+func createConference(ctx context.Context, name string) neo4j.ManagedTransactionWork {
+	return func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, "CREATE (:Conference {name: $name})", map[string]any{"name": name})
+		if err != nil {
+			return nil, err
+		}
+		return result.Consume(ctx)
+	}
+}
+
+func findConference(ctx context.Context, name string) neo4j.ManagedTransactionWork {
+	return func(tx neo4j.ManagedTransaction) (any, error) {
+		result, err := tx.Run(ctx, "MATCH (c:Conference {name: $name}) RETURN c.name AS name", map[string]any{"name": name})
+		if err != nil {
+			return nil, err
+		}
+		record, err := result.Single(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return record.Values[0], nil
+	}
+}
@@ -0,0 +1,76 @@
+package workshop_test
+
+import (
+	"context"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/mapping"
+	"graphconnect/neo4jtest"
+	"graphconnect/neo4jtest/fakebolt"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// TestFakeNeo4jServer checks the exercise helpers work against the fake server, without Docker
+func TestFakeNeo4jServer(outer *testing.T) {
+	server, err := fakebolt.Start(workshoptest.Script, fakebolt.WithAuth(username, password))
+	if err != nil {
+		outer.Fatalf("Could not start fake server: %v", err)
+	}
+	defer func() {
+		if err := server.Close(); err != nil {
+			outer.Errorf("Could not stop fake server: %v", err)
+		}
+	}()
+	ctx := context.Background()
+	driver, err := neo4j.NewDriverWithContext(server.BoltURI(), neo4j.BasicAuth(username, password, ""))
+	if err != nil {
+		outer.Fatalf("Could not create driver: %v", err)
+	}
+	defer closeDriver(ctx, outer, driver)
+
+	outer.Run("inserts the small graph", func(t *testing.T) {
+		summaries := insertSmallGraph(t, driver, server)
+
+		if len(summaries) != 1 {
+			t.Fatalf("Expected the summary of the small graph file, got: %v", summaries)
+		}
+		neo4jtest.AssertCounters(t, summaries[0].Counters, neo4jtest.Counters{
+			NodesCreated: 8, LabelsAdded: 8, PropertiesSet: 8, RelationshipsCreated: 6,
+		})
+	})
+	outer.Run("extracts the answer", func(t *testing.T) {
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer closeSession(ctx, t, session)
+
+		answer, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			result, err := tx.Run(ctx,
+				"RETURN REDUCE(sum=0, power IN $powersOfTwo | sum+power) AS answer",
+				map[string]any{"powersOfTwo": []int{2, 8, 32}})
+			if err != nil {
+				return nil, err
+			}
+			return extractAnswer(ctx, t, result), nil
+		})
+
+		if err != nil {
+			t.Errorf("Expected query to successfully execute but did not: %v", err)
+		}
+		if answer != int64(42) {
+			t.Errorf("Expected 42 from read transaction but got: %v", answer)
+		}
+	})
+	outer.Run("extracts the answer with ExecuteQuery", func(t *testing.T) {
+		result, err := neo4j.ExecuteQuery(ctx, driver,
+			"RETURN REDUCE(sum=0, power IN $powersOfTwo | sum+power) AS answer",
+			map[string]any{"powersOfTwo": []int{2, 8, 32}},
+			neo4j.EagerResultTransformer)
+
+		if err != nil {
+			t.Fatalf("Expected query to successfully execute but did not: %v", err)
+		}
+		if answers, err := mapping.DecodeAll[int64](result.Records); err != nil || len(answers) != 1 || answers[0] != 42 {
+			t.Errorf("Expected a single 42 answer, got: %v (%v)", answers, err)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package workshop_test
+
+import (
+	"context"
+	"graphconnect/neo4jtest"
+	"testing"
+)
+
+func TestNeo4jDriverEncryption(outer *testing.T) {
+
+	if neo4jtest.SelectedBackend() != neo4jtest.ContainerBackend {
+		outer.Skipf("Encryption tests only run with the %s backend", neo4jtest.ContainerBackend)
+	}
+	neo4jServer := neo4jtest.Pristine(outer, neo4jtest.WithTLS())
+	ctx := context.Background()
+	// Run `go test -v -run TestNeo4jDriverEncryption ./2-neo4j-go-driver/pkg/...`
+	for _, scheme := range []string{"neo4j+s", "neo4j+ssc", "bolt+s", "bolt+ssc"} {
+		scheme := scheme
+		outer.Run("connects with "+scheme, func(t *testing.T) {
+			driver, err := neo4jtest.NewDriverWithContext(neo4jServer, scheme)
+			if err != nil {
+				t.Fatalf("Could not create driver: %v", err)
+			}
+			defer closeDriver(ctx, t, driver)
+
+			err = driver.VerifyConnectivity(ctx)
+
+			if err != nil {
+				t.Errorf("Expected driver to connect with %s, got: %v", scheme, err)
+			}
+		})
+	}
+	outer.Run("refuses plain text connections", func(t *testing.T) {
+		driver, err := neo4jtest.NewDriverWithContext(neo4jServer, "neo4j")
+		if err != nil {
+			t.Fatalf("Could not create driver: %v", err)
+		}
+		defer closeDriver(ctx, t, driver)
+
+		err = driver.VerifyConnectivity(ctx)
+
+		if err == nil {
+			t.Errorf("Expected plain text connection to fail, got nil")
+		}
+	})
+}
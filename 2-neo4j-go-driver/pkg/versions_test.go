@@ -0,0 +1,66 @@
+package workshop_test
+
+import (
+	"context"
+	"graphconnect/go-driver/mapping"
+	"graphconnect/neo4jtest"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestNeo4jDriverVersions(outer *testing.T) {
+
+	// Run `NEO4J_TEST_VERSIONS=4.3,4.4,5 go test -v -run TestNeo4jDriverVersions ./2-neo4j-go-driver/pkg/...`
+	neo4jtest.Matrix(outer, []string{"4.4"}, func(outer *testing.T, neo4jServer neo4jtest.VersionedServer) {
+
+		outer.Run("filters with exists()", func(t *testing.T) {
+			// note: the exists() function was removed in 5.0, in favor of IS NOT NULL
+			neo4jServer.SkipFrom(t, "5.0")
+
+			count := runCount(t, neo4jServer, "MATCH (p:Person) WHERE exists(p.name) RETURN count(p) AS count")
+
+			if count != 0 {
+				t.Errorf("Expected 0 person, got: %d", count)
+			}
+		})
+		outer.Run("filters with IS NOT NULL", func(t *testing.T) {
+			count := runCount(t, neo4jServer, "MATCH (p:Person) WHERE p.name IS NOT NULL RETURN count(p) AS count")
+
+			if count != 0 {
+				t.Errorf("Expected 0 person, got: %d", count)
+			}
+		})
+		outer.Run("batches with CALL {} IN TRANSACTIONS", func(t *testing.T) {
+			// note: CALL {} IN TRANSACTIONS was introduced in 4.4
+			neo4jServer.SkipUnless(t, "4.4")
+
+			count := runCount(t, neo4jServer, "CALL { RETURN 42 AS answer } IN TRANSACTIONS RETURN answer AS count")
+
+			if count != 42 {
+				t.Errorf("Expected 42, got: %d", count)
+			}
+		})
+	})
+}
+
+func runCount(t *testing.T, server neo4jtest.Neo4jServer, query string) int64 {
+	ctx := context.Background()
+	driver, err := neo4jtest.NewDriverWithContext(server, "")
+	if err != nil {
+		t.Fatalf("Could not create driver: %v", err)
+	}
+	defer closeDriver(ctx, t, driver)
+	session := driver.NewSession(ctx, neo4j.SessionConfig{})
+	defer closeSession(ctx, t, session)
+	// note: session.Run is used since CALL {} IN TRANSACTIONS cannot run in a transaction function
+	result, err := session.Run(ctx, query, nil)
+	if err != nil {
+		t.Fatalf("Could not run query: %v", err)
+	}
+	count, err := mapping.SingleAs[int64](ctx, result)
+	if err != nil {
+		t.Fatalf("Could not read result: %v", err)
+	}
+	return count
+}
@@ -0,0 +1,66 @@
+package plan
+
+import (
+	"fmt"
+
+	neo4j4 "github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// The functions below are the 4.x driver counterparts of Explain, Profile and FromSummary
+// They keep the 4.x lessons working, 4.x transactions do not take a context
+
+// ExplainV4 returns the plan of the query, run under EXPLAIN in a transaction of the 4.x driver, see Explain
+func ExplainV4(tx neo4j4.Transaction, query string, params map[string]any) (*Plan, error) {
+	return runV4(tx, "EXPLAIN "+query, params)
+}
+
+// ProfileV4 returns the profiled plan of the query, run under PROFILE in a transaction of the 4.x driver, see Profile
+func ProfileV4(tx neo4j4.Transaction, query string, params map[string]any) (*Plan, error) {
+	return runV4(tx, "PROFILE "+query, params)
+}
+
+func runV4(tx neo4j4.Transaction, query string, params map[string]any) (*Plan, error) {
+	result, err := tx.Run(query, params)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := result.Consume()
+	if err != nil {
+		return nil, err
+	}
+	plan, ok := FromSummaryV4(summary)
+	if !ok {
+		return nil, fmt.Errorf("the summary of %q has no plan", query)
+	}
+	return plan, nil
+}
+
+// FromSummaryV4 returns the plan of a summary of the 4.x driver, see FromSummary
+func FromSummaryV4(summary neo4j4.ResultSummary) (plan *Plan, ok bool) {
+	if profile := summary.Profile(); profile != nil {
+		return fromProfileV4(profile), true
+	}
+	if plan := summary.Plan(); plan != nil {
+		return fromPlanV4(plan), true
+	}
+	return nil, false
+}
+
+func fromPlanV4(plan neo4j4.Plan) *Plan {
+	result := newPlan(plan.Operator(), plan.Identifiers(), plan.Arguments())
+	for _, child := range plan.Children() {
+		result.Children = append(result.Children, fromPlanV4(child))
+	}
+	return result
+}
+
+func fromProfileV4(profile neo4j4.ProfiledPlan) *Plan {
+	result := newPlan(profile.Operator(), profile.Identifiers(), profile.Arguments())
+	result.Profiled = true
+	result.DbHits = profile.DbHits()
+	result.Rows = profile.Records()
+	for _, child := range profile.Children() {
+		result.Children = append(result.Children, fromProfileV4(child))
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package plan_test
+
+import (
+	"graphconnect/go-driver/plan"
+	"graphconnect/neo4jtest/fakebolt"
+	"testing"
+
+	neo4j4 "github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+func TestPlanV4(outer *testing.T) {
+	server, err := fakebolt.Start(script)
+	if err != nil {
+		outer.Fatalf("Could not start fake server: %v", err)
+	}
+	defer func() {
+		if err := server.Close(); err != nil {
+			outer.Errorf("Could not stop fake server: %v", err)
+		}
+	}()
+	driver, err := neo4j4.NewDriver(server.BoltURI(), neo4j4.NoAuth())
+	if err != nil {
+		outer.Fatalf("Could not create driver: %v", err)
+	}
+	defer func() {
+		if err := driver.Close(); err != nil {
+			outer.Errorf("Could not close driver: %v", err)
+		}
+	}()
+
+	outer.Run("explains queries", func(t *testing.T) {
+		session := driver.NewSession(neo4j4.SessionConfig{})
+		defer session.Close()
+
+		explained, err := session.ReadTransaction(func(tx neo4j4.Transaction) (any, error) {
+			return plan.ExplainV4(tx, maintainersQuery, nil)
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if scans := explained.(*plan.Plan).Find("NodeByLabelScan"); len(scans) != 1 || scans[0].EstimatedRows != 2 {
+			t.Errorf("Expected a label scan of 2 estimated rows, got: %+v", scans)
+		}
+	})
+	outer.Run("profiles queries", func(t *testing.T) {
+		session := driver.NewSession(neo4j4.SessionConfig{})
+		defer session.Close()
+
+		profiled, err := session.ReadTransaction(func(tx neo4j4.Transaction) (any, error) {
+			return plan.ProfileV4(tx, projectQuery, map[string]any{"name": "GoGM"})
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if seeks := profiled.(*plan.Plan).Find("NodeIndexSeek"); len(seeks) != 1 || seeks[0].DbHits != 2 {
+			t.Errorf("Expected a profiled index seek of 2 db hits, got: %+v", seeks)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+// Package plan captures the execution plans of queries, run under EXPLAIN or PROFILE, and prints them as text tables
+//
+//	explained, err := plan.Explain(ctx, tx, "MATCH (p:Project {name: $name}) RETURN p", map[string]any{"name": "GoGM"})
+//	fmt.Println(explained)
+package plan
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Plan is an operator of an execution plan, with the operators it gets its rows from as children
+type Plan struct {
+	// Operator is the operator type, without the runtime suffix of 5.x, e.g. NodeIndexSeek rather than NodeIndexSeek@neo4j
+	Operator string
+	// EstimatedRows is the number of rows the planner expects the operator to produce
+	EstimatedRows float64
+	// Profiled tells whether the query ran under PROFILE, DbHits and Rows are only set then
+	Profiled bool
+	DbHits   int64
+	Rows     int64
+	// Identifiers are the variables the operator produces, named or generated by the planner
+	Identifiers []string
+	// Arguments hold the other details of the operator, e.g. "Details" or "PlannerVersion"
+	Arguments map[string]any
+	Children  []*Plan
+}
+
+// Explain returns the plan of the query, run under EXPLAIN: the query is planned but does not run
+func Explain(ctx context.Context, tx neo4j.ManagedTransaction, query string, params map[string]any) (*Plan, error) {
+	return run(ctx, tx, "EXPLAIN "+query, params)
+}
+
+// Profile returns the profiled plan of the query, run under PROFILE: the query runs and its records are discarded
+// Profiling writes in a read transaction fails, like running them
+func Profile(ctx context.Context, tx neo4j.ManagedTransaction, query string, params map[string]any) (*Plan, error) {
+	return run(ctx, tx, "PROFILE "+query, params)
+}
+
+func run(ctx context.Context, tx neo4j.ManagedTransaction, query string, params map[string]any) (*Plan, error) {
+	result, err := tx.Run(ctx, query, params)
+	if err != nil {
+		return nil, err
+	}
+	summary, err := result.Consume(ctx)
+	if err != nil {
+		return nil, err
+	}
+	plan, ok := FromSummary(summary)
+	if !ok {
+		return nil, fmt.Errorf("the summary of %q has no plan", query)
+	}
+	return plan, nil
+}
+
+// FromSummary returns the plan of the summary of an EXPLAIN or PROFILE query, the profiled one when both are present
+// ok is false when the summary has no plan, i.e. when the query did not start with EXPLAIN or PROFILE
+func FromSummary(summary neo4j.ResultSummary) (plan *Plan, ok bool) {
+	if profile := summary.Profile(); profile != nil {
+		return fromProfile(profile), true
+	}
+	if plan := summary.Plan(); plan != nil {
+		return fromPlan(plan), true
+	}
+	return nil, false
+}
+
+func fromPlan(plan neo4j.Plan) *Plan {
+	result := newPlan(plan.Operator(), plan.Identifiers(), plan.Arguments())
+	for _, child := range plan.Children() {
+		result.Children = append(result.Children, fromPlan(child))
+	}
+	return result
+}
+
+func fromProfile(profile neo4j.ProfiledPlan) *Plan {
+	result := newPlan(profile.Operator(), profile.Identifiers(), profile.Arguments())
+	result.Profiled = true
+	result.DbHits = profile.DbHits()
+	result.Rows = profile.Records()
+	for _, child := range profile.Children() {
+		result.Children = append(result.Children, fromProfile(child))
+	}
+	return result
+}
+
+func newPlan(operator string, identifiers []string, arguments map[string]any) *Plan {
+	if at := strings.IndexByte(operator, '@'); at >= 0 {
+		operator = operator[:at]
+	}
+	plan := &Plan{Operator: operator, Identifiers: identifiers, Arguments: arguments}
+	switch rows := arguments["EstimatedRows"].(type) {
+	case float64:
+		plan.EstimatedRows = rows
+	case int64:
+		plan.EstimatedRows = float64(rows)
+	}
+	return plan
+}
+
+// Find returns the operators of the given type, e.g. NodeIndexSeek, in depth-first order
+func (plan *Plan) Find(operator string) []*Plan {
+	var found []*Plan
+	plan.walk(0, func(current *Plan, _ int) {
+		if current.Operator == operator {
+			found = append(found, current)
+		}
+	})
+	return found
+}
+
+// UsesIndex reports whether an operator seeks or scans a property index, e.g. NodeIndexSeek or NodeUniqueIndexSeek
+// Label scans, which use the token lookup index, do not count
+func (plan *Plan) UsesIndex() bool {
+	uses := false
+	plan.walk(0, func(current *Plan, _ int) {
+		uses = uses || strings.Contains(current.Operator, "IndexSeek") || strings.Contains(current.Operator, "IndexScan") ||
+			strings.Contains(current.Operator, "IndexContainsScan") || strings.Contains(current.Operator, "IndexEndsWithScan")
+	})
+	return uses
+}
+
+// String renders the plan as a text table, with one operator per line, indented under the operator it feeds
+// The rows and db hits columns are only rendered for profiled plans, the details column when an operator has some
+//
+//	Operator        Estimated Rows  Rows  DB Hits  Identifiers
+//	ProduceResults  2               2     0        count, p
+//	  Sort          2               2     0        count, p
+func (plan *Plan) String() string {
+	details := false
+	plan.walk(0, func(current *Plan, _ int) {
+		details = details || current.details() != ""
+	})
+	header := []string{"Operator", "Estimated Rows"}
+	if plan.Profiled {
+		header = append(header, "Rows", "DB Hits")
+	}
+	header = append(header, "Identifiers")
+	if details {
+		header = append(header, "Details")
+	}
+	var builder strings.Builder
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, strings.Join(header, "\t"))
+	plan.walk(0, func(current *Plan, depth int) {
+		row := []string{strings.Repeat("  ", depth) + current.Operator, strconv.FormatFloat(current.EstimatedRows, 'f', 0, 64)}
+		if plan.Profiled {
+			row = append(row, strconv.FormatInt(current.Rows, 10), strconv.FormatInt(current.DbHits, 10))
+		}
+		identifiers := append([]string(nil), current.Identifiers...)
+		sort.Strings(identifiers)
+		row = append(row, strings.Join(identifiers, ", "))
+		if details {
+			row = append(row, current.details())
+		}
+		fmt.Fprintln(writer, strings.TrimRight(strings.Join(row, "\t"), "\t"))
+	})
+	_ = writer.Flush()
+	return builder.String()
+}
+
+func (plan *Plan) details() string {
+	details, _ := plan.Arguments["Details"].(string)
+	return details
+}
+
+func (plan *Plan) walk(depth int, visit func(*Plan, int)) {
+	visit(plan, depth)
+	for _, child := range plan.Children {
+		child.walk(depth+1, visit)
+	}
+}
@@ -0,0 +1,135 @@
+package plan_test
+
+import (
+	"context"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/plan"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const maintainersQuery = "MATCH (p:Project)<-[:WORKS_ON]-(pe:Person) WITH p, size(collect(pe)) AS count RETURN p ORDER BY count DESC"
+
+const projectQuery = "MATCH (p:Project {name: $name}) RETURN p"
+
+// maintainersPlan is the plan of maintainersQuery, as reported by Neo4j 5
+var maintainersPlan = &fakebolt.Plan{
+	Operator: "ProduceResults@neo4j", Identifiers: []string{"p", "count"},
+	Arguments: map[string]any{"EstimatedRows": 2.0, "Details": "p"},
+	Children: []fakebolt.Plan{{
+		Operator: "Sort@neo4j", Identifiers: []string{"p", "count"},
+		Arguments: map[string]any{"EstimatedRows": 2.0, "Details": "count DESC"},
+		Children: []fakebolt.Plan{{
+			Operator: "EagerAggregation@neo4j", Identifiers: []string{"p", "count"},
+			Arguments: map[string]any{"EstimatedRows": 2.0, "Details": "p, size(collect(pe)) AS count"},
+			Children: []fakebolt.Plan{{
+				Operator: "Expand(All)@neo4j", Identifiers: []string{"p", "pe", "anon_0"},
+				Arguments: map[string]any{"EstimatedRows": 4.0, "Details": "(p)<-[anon_0:WORKS_ON]-(pe)"},
+				Children: []fakebolt.Plan{{
+					Operator: "NodeByLabelScan@neo4j", Identifiers: []string{"p"},
+					Arguments: map[string]any{"EstimatedRows": 2.0, "Details": "p:Project"},
+				}},
+			}},
+		}},
+	}},
+}
+
+var projectPlan = &fakebolt.Plan{
+	Operator: "ProduceResults@neo4j", Identifiers: []string{"p"}, Rows: 1,
+	Arguments: map[string]any{"EstimatedRows": 1.0},
+	Children: []fakebolt.Plan{{
+		Operator: "NodeIndexSeek@neo4j", Identifiers: []string{"p"}, Rows: 1, DbHits: 2,
+		Arguments: map[string]any{"EstimatedRows": 1.0},
+	}},
+}
+
+var script = fakebolt.Script{
+	"EXPLAIN " + maintainersQuery: {Keys: []string{"p"}, Plan: maintainersPlan},
+	"PROFILE " + projectQuery:     {Keys: []string{"p"}, Plan: projectPlan, Records: [][]any{{fakebolt.Node{ID: 1, Labels: []string{"Project"}, Props: map[string]any{"name": "GoGM"}}}}},
+	"PROFILE " + maintainersQuery: {Keys: []string{"p"}},
+}
+
+func TestPlan(outer *testing.T) {
+	ctx := context.Background()
+	server, driver := workshoptest.StartFakeServer(outer, script)
+	read := func(run func(neo4j.ManagedTransaction) (*plan.Plan, error)) (*plan.Plan, error) {
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+		return neo4j.ExecuteRead(ctx, session, run)
+	}
+
+	outer.Run("explains queries", func(t *testing.T) {
+		explained, err := read(func(tx neo4j.ManagedTransaction) (*plan.Plan, error) {
+			return plan.Explain(ctx, tx, maintainersQuery, nil)
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if explained.Operator != "ProduceResults" || explained.Profiled || len(explained.Children) != 1 {
+			t.Errorf("Expected the ProduceResults operator of an explained plan, got: %+v", explained)
+		}
+		scans := explained.Find("NodeByLabelScan")
+		if len(scans) != 1 || scans[0].EstimatedRows != 2 || !reflect.DeepEqual(scans[0].Identifiers, []string{"p"}) {
+			t.Errorf("Expected a label scan of 2 estimated rows, got: %+v", scans)
+		}
+		if explained.UsesIndex() {
+			t.Errorf("Expected a plan without index")
+		}
+		queries := workshoptest.ReceivedQueries(server)
+		if last := queries[len(queries)-1]; last != "EXPLAIN "+maintainersQuery {
+			t.Errorf("Expected the query to run under EXPLAIN, got: %s", last)
+		}
+	})
+	outer.Run("renders explained plans as text tables", func(t *testing.T) {
+		explained, err := read(func(tx neo4j.ManagedTransaction) (*plan.Plan, error) {
+			return plan.Explain(ctx, tx, maintainersQuery, nil)
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := "" +
+			"Operator                 Estimated Rows  Identifiers    Details\n" +
+			"ProduceResults           2               count, p       p\n" +
+			"  Sort                   2               count, p       count DESC\n" +
+			"    EagerAggregation     2               count, p       p, size(collect(pe)) AS count\n" +
+			"      Expand(All)        4               anon_0, p, pe  (p)<-[anon_0:WORKS_ON]-(pe)\n" +
+			"        NodeByLabelScan  2               p              p:Project\n"
+		if rendered := explained.String(); rendered != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, rendered)
+		}
+	})
+	outer.Run("profiles queries", func(t *testing.T) {
+		profiled, err := read(func(tx neo4j.ManagedTransaction) (*plan.Plan, error) {
+			return plan.Profile(ctx, tx, projectQuery, map[string]any{"name": "GoGM"})
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		seeks := profiled.Find("NodeIndexSeek")
+		if !profiled.Profiled || !profiled.UsesIndex() || len(seeks) != 1 || seeks[0].DbHits != 2 || seeks[0].Rows != 1 {
+			t.Errorf("Expected a profiled index seek of 2 db hits, got: %+v", seeks)
+		}
+		expected := "" +
+			"Operator         Estimated Rows  Rows  DB Hits  Identifiers\n" +
+			"ProduceResults   1               1     0        p\n" +
+			"  NodeIndexSeek  1               1     2        p\n"
+		if rendered := profiled.String(); rendered != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, rendered)
+		}
+	})
+	outer.Run("fails when the summary has no plan", func(t *testing.T) {
+		_, err := read(func(tx neo4j.ManagedTransaction) (*plan.Plan, error) {
+			return plan.Profile(ctx, tx, maintainersQuery, nil)
+		})
+
+		if err == nil {
+			t.Errorf("Expected an error, got nil")
+		}
+	})
+}
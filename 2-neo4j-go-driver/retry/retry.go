@@ -0,0 +1,151 @@
+// Package retry runs transaction functions with a retry policy the caller controls and observes
+package retry
+
+import (
+	"context"
+	"fmt"
+	"graphconnect/go-driver/neo4jerrors"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Policy tells how many times and how often a transaction function is retried
+// Start from DefaultPolicy and change the fields you need
+type Policy struct {
+	// MaxAttempts bounds the number of attempts, including the first one
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry, it is multiplied by Multiplier after each retry, up to MaxBackoff
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	// Jitter randomizes the delays by up to the given fraction, e.g. 0.2 for ±20%, so that concurrent transactions retry at different times
+	Jitter float64
+	// Deadline bounds the time spent on all the attempts, no retry starts after it, 0 for no deadline
+	Deadline time.Duration
+	// Retryable tells which errors are worth retrying, IsRetryable when nil
+	Retryable func(error) bool
+	// OnAttempt is called after each attempt, when set
+	OnAttempt func(Attempt)
+}
+
+// DefaultPolicy retries transient and connectivity errors 5 times at most, for 30 seconds at most
+var DefaultPolicy = Policy{
+	MaxAttempts:    5,
+	InitialBackoff: 200 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Multiplier:     2,
+	Jitter:         0.2,
+	Deadline:       30 * time.Second,
+}
+
+// Attempt describes a finished attempt
+type Attempt struct {
+	// Number is the number of the attempt, starting at 1
+	Number int
+	// Err is the error of the attempt, nil when it succeeded
+	Err error
+	// Elapsed is the time spent since the start of the first attempt
+	Elapsed time.Duration
+	// Retry tells whether another attempt follows, after Backoff
+	Retry   bool
+	Backoff time.Duration
+}
+
+// ExecuteWithRetry runs the work in a transaction of the session, and commits it, until it succeeds or the policy gives up
+// Each attempt runs in a new transaction, the ones of failed attempts are rolled back
+// The work must be idempotent: a transaction whose commit failed because of the network may have been committed
+// Unlike session.ExecuteRead and session.ExecuteWrite, the access mode is the one of the session
+// When the context is done between attempts, the returned error wraps the context error, e.g. context.Canceled
+func ExecuteWithRetry[T any](ctx context.Context, session neo4j.SessionWithContext, work func(neo4j.ManagedTransaction) (T, error), policy Policy, configurers ...func(*neo4j.TransactionConfig)) (T, error) {
+	retryable := policy.Retryable
+	if retryable == nil {
+		retryable = IsRetryable
+	}
+	start := time.Now()
+	for number := 1; ; number++ {
+		value, err := attempt(ctx, session, work, configurers)
+		current := Attempt{Number: number, Err: err, Elapsed: time.Since(start)}
+		if err == nil {
+			policy.notify(current)
+			return value, nil
+		}
+		current.Backoff = policy.backoff(number)
+		current.Retry = retryable(err) && number < policy.MaxAttempts &&
+			(policy.Deadline == 0 || current.Elapsed+current.Backoff < policy.Deadline)
+		policy.notify(current)
+		if !current.Retry {
+			var zero T
+			if number == 1 {
+				return zero, err
+			}
+			return zero, fmt.Errorf("gave up after %d attempts: %w", number, err)
+		}
+		if err := sleep(ctx, current.Backoff); err != nil {
+			// note: the context error is the one wrapped, so that callers can tell a cancellation from a failure
+			var zero T
+			return zero, fmt.Errorf("gave up after %d attempts: %w (last error: %v)", number, err, current.Err)
+		}
+	}
+}
+
+// attempt runs the work in an explicit transaction, so that the driver does not retry it on its own
+func attempt[T any](ctx context.Context, session neo4j.SessionWithContext, work func(neo4j.ManagedTransaction) (T, error), configurers []func(*neo4j.TransactionConfig)) (T, error) {
+	var zero T
+	tx, err := session.BeginTransaction(ctx, configurers...)
+	if err != nil {
+		return zero, err
+	}
+	defer tx.Close(ctx)
+	// note: the work gets the transaction as a managed one, so that it cannot commit or roll it back
+	value, err := work(tx)
+	if err != nil {
+		_ = tx.Rollback(ctx)
+		return zero, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return zero, err
+	}
+	return value, nil
+}
+
+// backoff returns the delay after the given attempt
+func (policy Policy) backoff(number int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	delay := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(number-1))
+	if policy.MaxBackoff > 0 && delay > float64(policy.MaxBackoff) {
+		delay = float64(policy.MaxBackoff)
+	}
+	if policy.Jitter > 0 {
+		delay *= 1 + policy.Jitter*(2*rand.Float64()-1)
+	}
+	return time.Duration(delay)
+}
+
+func (policy Policy) notify(attempt Attempt) {
+	if policy.OnAttempt != nil {
+		policy.OnAttempt(attempt)
+	}
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsRetryable reports whether the error is transient, including cluster leader switches, or a connectivity error
+// The transient errors caused by the client, such as terminated transactions, are not retryable, see neo4jerrors.Classify
+func IsRetryable(err error) bool {
+	return neo4jerrors.IsTransient(err) || neo4jerrors.IsConnectivityError(err)
+}
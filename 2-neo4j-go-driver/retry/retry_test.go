@@ -0,0 +1,210 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/retry"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+const mergeQuery = "MERGE (p:Person {name: $name}) RETURN p.name AS name"
+
+var deadlock = &neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.DeadlockDetected", Msg: "deadlock"}
+
+var script = fakebolt.Script{
+	mergeQuery: {Keys: []string{"name"}, Records: [][]any{{"Eric"}}, Counters: map[string]int{"nodes-created": 1}},
+	"MATCH (p:Person) SET p.locked = true": {Failure: &fakebolt.Failure{
+		Code:    "Neo.TransientError.Transaction.LockClientStopped",
+		Message: "The transaction has been terminated",
+	}},
+	"MATCH (p:Person) SET p.visits = p.visits + 1": {Failure: &fakebolt.Failure{
+		Code:    "Neo.TransientError.Transaction.DeadlockDetected",
+		Message: "ForsetiClient can't acquire ExclusiveLock",
+	}},
+}
+
+// policy retries without jitter, so that the delays are predictable
+var policy = retry.Policy{MaxAttempts: 4, InitialBackoff: time.Millisecond, MaxBackoff: 3 * time.Millisecond, Multiplier: 2}
+
+func TestExecuteWithRetry(outer *testing.T) {
+	ctx := context.Background()
+
+	outer.Run("retries idempotent work until it succeeds", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, script)
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+		var attempts []retry.Attempt
+		retried := policy
+		retried.OnAttempt = func(attempt retry.Attempt) {
+			attempts = append(attempts, attempt)
+		}
+		var transactions []neo4j.ManagedTransaction
+
+		name, err := retry.ExecuteWithRetry(ctx, session, func(tx neo4j.ManagedTransaction) (string, error) {
+			transactions = append(transactions, tx)
+			result, err := tx.Run(ctx, mergeQuery, map[string]any{"name": "Eric"})
+			if err != nil {
+				return "", err
+			}
+			record, err := result.Single(ctx)
+			if err != nil {
+				return "", err
+			}
+			if len(transactions) < 3 {
+				return "", deadlock
+			}
+			return record.Values[0].(string), nil
+		}, retried)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if name != "Eric" {
+			t.Errorf("Expected Eric, got: %s", name)
+		}
+		if len(attempts) != 3 || !attempts[0].Retry || !attempts[1].Retry || attempts[2].Retry || attempts[2].Err != nil {
+			t.Errorf("Expected 2 failed attempts and a successful one, got: %+v", attempts)
+		}
+		if attempts[0].Backoff != time.Millisecond || attempts[1].Backoff != 2*time.Millisecond {
+			t.Errorf("Expected backoffs of 1ms and 2ms, got: %v and %v", attempts[0].Backoff, attempts[1].Backoff)
+		}
+		// the same MERGE ran in each attempt, with the same parameters
+		for _, query := range server.Received() {
+			if query.Text != mergeQuery || !reflect.DeepEqual(query.Params, map[string]any{"name": "Eric"}) {
+				t.Errorf("Expected only the MERGE query for Eric, got: %v", query)
+			}
+		}
+		if received := len(server.Received()); received != 3 {
+			t.Errorf("Expected 3 MERGE queries, got: %d", received)
+		}
+		// the transactions of the failed attempts were rolled back
+		for _, tx := range transactions[:2] {
+			if _, err := tx.Run(ctx, mergeQuery, map[string]any{"name": "Eric"}); err == nil {
+				t.Errorf("Expected the transaction of a failed attempt to be closed")
+			}
+		}
+	})
+	outer.Run("does not retry other errors", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+		calls := 0
+
+		_, err := retry.ExecuteWithRetry(ctx, session, func(tx neo4j.ManagedTransaction) (any, error) {
+			calls++
+			return tx.Run(ctx, "MATCH (p:Person) SET p.locked = true", nil)
+		}, policy)
+
+		var neo4jError *neo4j.Neo4jError
+		if !errors.As(err, &neo4jError) || calls != 1 {
+			t.Errorf("Expected the Neo4j error of a single attempt, got: %v after %d attempts", err, calls)
+		}
+	})
+	outer.Run("gives up after the maximum number of attempts", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+		var backoffs []time.Duration
+		retried := policy
+		retried.OnAttempt = func(attempt retry.Attempt) {
+			backoffs = append(backoffs, attempt.Backoff)
+		}
+
+		_, err := retry.ExecuteWithRetry(ctx, session, func(tx neo4j.ManagedTransaction) (any, error) {
+			return tx.Run(ctx, "MATCH (p:Person) SET p.visits = p.visits + 1", nil)
+		}, retried)
+
+		if err == nil || !retry.IsRetryable(err) {
+			t.Errorf("Expected the transient error, got: %v", err)
+		}
+		expected := []time.Duration{time.Millisecond, 2 * time.Millisecond, 3 * time.Millisecond, 3 * time.Millisecond}
+		if !reflect.DeepEqual(backoffs, expected) {
+			t.Errorf("Expected 4 attempts with backoffs %v, got: %v", expected, backoffs)
+		}
+	})
+	outer.Run("stops retrying at the deadline", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+		calls := 0
+		retried := policy
+		retried.MaxAttempts = 100
+		retried.InitialBackoff = 30 * time.Millisecond
+		retried.MaxBackoff = 30 * time.Millisecond
+		retried.Deadline = 50 * time.Millisecond
+
+		_, err := retry.ExecuteWithRetry(ctx, session, func(neo4j.ManagedTransaction) (any, error) {
+			calls++
+			return nil, deadlock
+		}, retried)
+
+		// the second retry would start after 60ms
+		if !errors.Is(err, deadlock) || calls != 2 {
+			t.Errorf("Expected the deadlock after 2 attempts, got: %v after %d attempts", err, calls)
+		}
+	})
+	outer.Run("stops retrying when the context is done", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+		cancelled, cancel := context.WithCancel(ctx)
+		retried := policy
+		retried.InitialBackoff = time.Hour
+		retried.OnAttempt = func(retry.Attempt) {
+			cancel()
+		}
+
+		_, err := retry.ExecuteWithRetry(cancelled, session, func(neo4j.ManagedTransaction) (any, error) {
+			return nil, deadlock
+		}, retried)
+
+		if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), deadlock.Error()) {
+			t.Errorf("Expected the cancellation and the deadlock, got: %v", err)
+		}
+	})
+	outer.Run("stops retrying when the context deadline is exceeded", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, script)
+		session := driver.NewSession(ctx, neo4j.SessionConfig{})
+		defer session.Close(ctx)
+		expiring, cancel := context.WithTimeout(ctx, 100*time.Millisecond)
+		defer cancel()
+		retried := policy
+		retried.InitialBackoff, retried.MaxBackoff = time.Hour, time.Hour
+
+		_, err := retry.ExecuteWithRetry(expiring, session, func(neo4j.ManagedTransaction) (any, error) {
+			return nil, deadlock
+		}, retried)
+
+		if !errors.Is(err, context.DeadlineExceeded) || errors.Is(err, deadlock) {
+			t.Errorf("Expected the exceeded deadline only, got: %v", err)
+		}
+	})
+}
+
+func TestIsRetryable(t *testing.T) {
+	for _, test := range []struct {
+		err       error
+		retryable bool
+	}{
+		{deadlock, true},
+		{fmt.Errorf("could not save: %w", deadlock), true},
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.Cluster.NotALeader"}, true},
+		{&neo4j.Neo4jError{Code: "Neo.TransientError.Transaction.Terminated"}, false},
+		{&neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError"}, false},
+		{&neo4j.ConnectivityError{Inner: errors.New("connection reset")}, true},
+		{errors.New("boom"), false},
+		{nil, false},
+	} {
+		if retryable := retry.IsRetryable(test.err); retryable != test.retryable {
+			t.Errorf("Expected IsRetryable(%v) to be %t", test.err, test.retryable)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package schema
+
+import (
+	"strings"
+)
+
+// Diff lists the differences between a declared schema and the schema of a database
+type Diff struct {
+	// MissingIndexes and MissingConstraints are declared but do not exist
+	MissingIndexes     []Index
+	MissingConstraints []Constraint
+	// ExtraIndexes and ExtraConstraints exist but are not declared
+	// Token lookup indexes and indexes backing constraints are never extra
+	ExtraIndexes     []Index
+	ExtraConstraints []Constraint
+}
+
+// Compare compares the declared schema to the actual one
+// Indexes and constraints match when they apply to the same labels or types and properties the same way, whatever their name
+func Compare(declared, actual Schema) Diff {
+	var diff Diff
+	for _, index := range declared.Indexes {
+		if !containsIndex(actual.Indexes, index) {
+			diff.MissingIndexes = append(diff.MissingIndexes, index)
+		}
+	}
+	for _, index := range actual.Indexes {
+		if index.kind() == LookupIndex || index.OwningConstraint != "" {
+			continue
+		}
+		if !containsIndex(declared.Indexes, index) {
+			diff.ExtraIndexes = append(diff.ExtraIndexes, index)
+		}
+	}
+	for _, constraint := range declared.Constraints {
+		if !containsConstraint(actual.Constraints, constraint) {
+			diff.MissingConstraints = append(diff.MissingConstraints, constraint)
+		}
+	}
+	for _, constraint := range actual.Constraints {
+		if !containsConstraint(declared.Constraints, constraint) {
+			diff.ExtraConstraints = append(diff.ExtraConstraints, constraint)
+		}
+	}
+	return diff
+}
+
+// Empty reports whether the schemas match
+func (diff Diff) Empty() bool {
+	return len(diff.MissingIndexes) == 0 && len(diff.MissingConstraints) == 0 &&
+		len(diff.ExtraIndexes) == 0 && len(diff.ExtraConstraints) == 0
+}
+
+// Statements returns the statements creating the missing constraints and indexes
+func (diff Diff) Statements() []string {
+	var statements []string
+	for _, constraint := range diff.MissingConstraints {
+		statements = append(statements, constraint.CreateStatement())
+	}
+	for _, index := range diff.MissingIndexes {
+		statements = append(statements, index.CreateStatement())
+	}
+	return statements
+}
+
+// String lists the differences, one per line, e.g. "+ RANGE index person_name on :Person(name)"
+// Missing indexes and constraints are prefixed with +, extra ones with -
+func (diff Diff) String() string {
+	var lines []string
+	for _, constraint := range diff.MissingConstraints {
+		lines = append(lines, "+ "+constraint.String())
+	}
+	for _, index := range diff.MissingIndexes {
+		lines = append(lines, "+ "+index.String())
+	}
+	for _, constraint := range diff.ExtraConstraints {
+		lines = append(lines, "- "+constraint.String())
+	}
+	for _, index := range diff.ExtraIndexes {
+		lines = append(lines, "- "+index.String())
+	}
+	if len(lines) == 0 {
+		return "no differences"
+	}
+	return strings.Join(lines, "\n")
+}
+
+func containsIndex(indexes []Index, index Index) bool {
+	for _, candidate := range indexes {
+		if candidate.matches(index) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsConstraint(constraints []Constraint, constraint Constraint) bool {
+	for _, candidate := range constraints {
+		if candidate.matches(constraint) {
+			return true
+		}
+	}
+	return false
+}
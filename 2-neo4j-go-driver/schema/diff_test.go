@@ -0,0 +1,67 @@
+package schema_test
+
+import (
+	"graphconnect/go-driver/schema"
+	"reflect"
+	"testing"
+)
+
+func TestCompare(outer *testing.T) {
+	declared := schema.Schema{
+		Indexes: []schema.Index{
+			schema.NodeIndex("person_name", "Person", "name"),
+			schema.NodeIndex("project_name", "Project", "name"),
+		},
+		Constraints: []schema.Constraint{
+			schema.UniqueConstraint("topic_name", "Topic", "name"),
+		},
+	}
+
+	outer.Run("matches indexes and constraints regardless of their name", func(t *testing.T) {
+		actual := schema.Schema{
+			Indexes: []schema.Index{
+				{Name: "index_1", Type: "BTREE", EntityType: schema.Node, LabelsOrTypes: []string{"Person"}, Properties: []string{"name"}},
+				{Name: "index_2", Type: schema.RangeIndex, EntityType: schema.Node, LabelsOrTypes: []string{"Project"}, Properties: []string{"name"}},
+				{Name: "constraint_1", Type: schema.RangeIndex, EntityType: schema.Node, LabelsOrTypes: []string{"Topic"}, Properties: []string{"name"}, OwningConstraint: "constraint_1"},
+				{Name: "labels", Type: schema.LookupIndex, EntityType: schema.Node},
+			},
+			Constraints: []schema.Constraint{
+				{Name: "constraint_1", Type: schema.Uniqueness, EntityType: schema.Node, LabelsOrTypes: []string{"Topic"}, Properties: []string{"name"}},
+			},
+		}
+
+		diff := schema.Compare(declared, actual)
+
+		if !diff.Empty() {
+			t.Errorf("Expected no differences, got:\n%s", diff)
+		}
+	})
+	outer.Run("reports missing and extra indexes and constraints", func(t *testing.T) {
+		actual := schema.Schema{
+			Indexes: []schema.Index{
+				{Name: "person_name", Type: schema.TextIndex, EntityType: schema.Node, LabelsOrTypes: []string{"Person"}, Properties: []string{"name"}},
+				{Name: "project_name", Type: schema.RangeIndex, EntityType: schema.Node, LabelsOrTypes: []string{"Project"}, Properties: []string{"name"}},
+			},
+			Constraints: []schema.Constraint{
+				{Name: "legacy", Type: schema.NodeKey, EntityType: schema.Node, LabelsOrTypes: []string{"Topic"}, Properties: []string{"name"}},
+			},
+		}
+
+		diff := schema.Compare(declared, actual)
+
+		expected := "+ UNIQUENESS constraint topic_name on :Topic(name)\n" +
+			"+ RANGE index person_name on :Person(name)\n" +
+			"- NODE_KEY constraint legacy on :Topic(name)\n" +
+			"- TEXT index person_name on :Person(name)"
+		if diff.String() != expected {
+			t.Errorf("Expected:\n%s\ngot:\n%s", expected, diff)
+		}
+		expectedStatements := []string{
+			"CREATE CONSTRAINT topic_name IF NOT EXISTS FOR (n:Topic) REQUIRE n.name IS UNIQUE",
+			"CREATE INDEX person_name IF NOT EXISTS FOR (n:Person) ON (n.name)",
+		}
+		if statements := diff.Statements(); !reflect.DeepEqual(statements, expectedStatements) {
+			t.Errorf("Expected %q, got: %q", expectedStatements, statements)
+		}
+	})
+}
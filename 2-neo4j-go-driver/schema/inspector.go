@@ -0,0 +1,221 @@
+package schema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// Inspector reads the schema of a database, compares it to a declared one and creates what is missing
+type Inspector struct {
+	driver neo4j.DriverWithContext
+	config neo4j.SessionConfig
+}
+
+// Option customizes the Inspector
+type Option func(*Inspector)
+
+// WithDatabase inspects the given database, instead of the default one
+func WithDatabase(name string) Option {
+	return func(inspector *Inspector) {
+		inspector.config.DatabaseName = name
+	}
+}
+
+// NewInspector returns an Inspector running its queries with the driver
+func NewInspector(driver neo4j.DriverWithContext, options ...Option) *Inspector {
+	inspector := &Inspector{driver: driver}
+	for _, option := range options {
+		option(inspector)
+	}
+	return inspector
+}
+
+// Read returns the indexes and constraints of the database
+// It runs SHOW INDEXES and SHOW CONSTRAINTS, and falls back to the db.indexes() and db.constraints() procedures before 4.2
+func (inspector *Inspector) Read(ctx context.Context) (schema Schema, err error) {
+	session := inspector.driver.NewSession(ctx, inspector.config)
+	defer func() {
+		if closeErr := session.Close(ctx); err == nil {
+			err = closeErr
+		}
+	}()
+	indexRows, err := readRows(ctx, session, "SHOW INDEXES", "CALL db.indexes()")
+	if err != nil {
+		return Schema{}, fmt.Errorf("could not read indexes: %w", err)
+	}
+	constraintRows, err := readRows(ctx, session, "SHOW CONSTRAINTS", "CALL db.constraints()")
+	if err != nil {
+		return Schema{}, fmt.Errorf("could not read constraints: %w", err)
+	}
+	for _, row := range indexRows {
+		schema.Indexes = append(schema.Indexes, indexOf(row))
+	}
+	for _, row := range constraintRows {
+		constraint, err := constraintOf(row)
+		if err != nil {
+			return Schema{}, err
+		}
+		schema.Constraints = append(schema.Constraints, constraint)
+	}
+	return schema, nil
+}
+
+// Diff reads the schema of the database and compares it to the declared one, see Compare
+func (inspector *Inspector) Diff(ctx context.Context, declared Schema) (Diff, error) {
+	actual, err := inspector.Read(ctx)
+	if err != nil {
+		return Diff{}, err
+	}
+	return Compare(declared, actual), nil
+}
+
+// Apply creates the missing indexes and constraints of the diff, in a single schema transaction
+// Extra indexes and constraints are left untouched
+func (inspector *Inspector) Apply(ctx context.Context, diff Diff) (err error) {
+	statements := diff.Statements()
+	if len(statements) == 0 {
+		return nil
+	}
+	session := inspector.driver.NewSession(ctx, inspector.config)
+	defer func() {
+		if closeErr := session.Close(ctx); err == nil {
+			err = closeErr
+		}
+	}()
+	_, err = session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		for _, statement := range statements {
+			result, err := tx.Run(ctx, statement, nil)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := result.Consume(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// readRows runs the query, or the fallback query when the server does not understand the first one
+func readRows(ctx context.Context, session neo4j.SessionWithContext, query, fallback string) ([]map[string]any, error) {
+	rows, err := session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		return collectRows(ctx, tx, query)
+	})
+	var neo4jErr *neo4j.Neo4jError
+	if errors.As(err, &neo4jErr) && neo4jErr.Code == "Neo.ClientError.Statement.SyntaxError" {
+		rows, err = session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+			return collectRows(ctx, tx, fallback)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rows.([]map[string]any), nil
+}
+
+// collectRows returns the records as maps, since the columns differ between versions
+func collectRows(ctx context.Context, tx neo4j.ManagedTransaction, query string) ([]map[string]any, error) {
+	result, err := tx.Run(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	records, err := result.Collect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]map[string]any, len(records))
+	for i, record := range records {
+		rows[i] = record.AsMap()
+	}
+	return rows, nil
+}
+
+func indexOf(row map[string]any) Index {
+	index := Index{
+		Name:             stringOf(row["name"]),
+		Type:             IndexType(stringOf(row["type"])),
+		EntityType:       EntityType(stringOf(row["entityType"])),
+		LabelsOrTypes:    stringsOf(row["labelsOrTypes"]),
+		Properties:       stringsOf(row["properties"]),
+		State:            stringOf(row["state"]),
+		OwningConstraint: stringOf(row["owningConstraint"]),
+	}
+	// note: before 5.0, the indexes backing constraints are unique and share the name of their constraint
+	if stringOf(row["uniqueness"]) == "UNIQUE" && index.OwningConstraint == "" {
+		index.OwningConstraint = index.Name
+	}
+	return index
+}
+
+// constraintOf converts a row of SHOW CONSTRAINTS, or a row of db.constraints() which only describes the constraint
+func constraintOf(row map[string]any) (Constraint, error) {
+	if _, found := row["type"]; found {
+		return Constraint{
+			Name:          stringOf(row["name"]),
+			Type:          ConstraintType(stringOf(row["type"])),
+			EntityType:    EntityType(stringOf(row["entityType"])),
+			LabelsOrTypes: stringsOf(row["labelsOrTypes"]),
+			Properties:    stringsOf(row["properties"]),
+		}, nil
+	}
+	return parseConstraintDescription(stringOf(row["name"]), stringOf(row["description"]))
+}
+
+var nodeConstraintDescription = regexp.MustCompile(`^CONSTRAINT ON \( ?\w+:(\S+?) ?\) ASSERT (.+)$`)
+var relationshipConstraintDescription = regexp.MustCompile(`^CONSTRAINT ON \(\)-\[ ?\w+:(\S+?) ?\]-\(\) ASSERT (.+)$`)
+var assertion = regexp.MustCompile(`^(?:exists\((.+)\)|\((.+)\) IS (UNIQUE|NODE KEY))$`)
+
+// parseConstraintDescription parses the description of db.constraints(), e.g. "CONSTRAINT ON ( p:Person ) ASSERT (p.name) IS UNIQUE"
+func parseConstraintDescription(name, description string) (Constraint, error) {
+	constraint := Constraint{Name: name, EntityType: Node}
+	matches := nodeConstraintDescription.FindStringSubmatch(description)
+	if matches == nil {
+		constraint.EntityType = Relationship
+		matches = relationshipConstraintDescription.FindStringSubmatch(description)
+	}
+	if matches == nil {
+		return Constraint{}, fmt.Errorf("cannot parse constraint %s: %q", name, description)
+	}
+	constraint.LabelsOrTypes = []string{strings.Trim(matches[1], "`")}
+	parts := assertion.FindStringSubmatch(matches[2])
+	if parts == nil {
+		return Constraint{}, fmt.Errorf("cannot parse constraint %s: %q", name, description)
+	}
+	properties := parts[2]
+	switch {
+	case parts[1] != "" && constraint.EntityType == Relationship:
+		constraint.Type, properties = RelationshipPropertyExistence, parts[1]
+	case parts[1] != "":
+		constraint.Type, properties = NodePropertyExistence, parts[1]
+	case parts[3] == "NODE KEY":
+		constraint.Type = NodeKey
+	default:
+		constraint.Type = Uniqueness
+	}
+	for _, property := range strings.Split(properties, ",") {
+		// properties are prefixed with the variable, e.g. p.name
+		_, name, _ := strings.Cut(strings.TrimSpace(property), ".")
+		constraint.Properties = append(constraint.Properties, strings.Trim(name, "`"))
+	}
+	return constraint, nil
+}
+
+func stringOf(value any) string {
+	text, _ := value.(string)
+	return text
+}
+
+func stringsOf(value any) []string {
+	values, _ := value.([]any)
+	texts := make([]string, 0, len(values))
+	for _, value := range values {
+		texts = append(texts, stringOf(value))
+	}
+	return texts
+}
@@ -0,0 +1,106 @@
+package schema_test
+
+import (
+	"context"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/schema"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+)
+
+func TestInspector(outer *testing.T) {
+	ctx := context.Background()
+
+	outer.Run("reads the schema with SHOW commands", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"SHOW INDEXES": {
+				Keys: []string{"id", "name", "state", "populationPercent", "type", "entityType", "labelsOrTypes", "properties", "indexProvider", "owningConstraint"},
+				Records: [][]any{
+					{1, "person_name", "ONLINE", 100.0, "RANGE", "NODE", []any{"Person"}, []any{"name"}, "range-1.0", nil},
+					{2, "topic_name", "ONLINE", 100.0, "RANGE", "NODE", []any{"Topic"}, []any{"name"}, "range-1.0", "topic_name"},
+				},
+			},
+			"SHOW CONSTRAINTS": {
+				Keys:    []string{"id", "name", "type", "entityType", "labelsOrTypes", "properties", "ownedIndex"},
+				Records: [][]any{{3, "topic_name", "UNIQUENESS", "NODE", []any{"Topic"}, []any{"name"}, "topic_name"}},
+			},
+		})
+
+		actual, err := schema.NewInspector(driver).Read(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := schema.Schema{
+			Indexes: []schema.Index{
+				{Name: "person_name", Type: schema.RangeIndex, EntityType: schema.Node, LabelsOrTypes: []string{"Person"}, Properties: []string{"name"}, State: "ONLINE"},
+				{Name: "topic_name", Type: schema.RangeIndex, EntityType: schema.Node, LabelsOrTypes: []string{"Topic"}, Properties: []string{"name"}, State: "ONLINE", OwningConstraint: "topic_name"},
+			},
+			Constraints: []schema.Constraint{schema.UniqueConstraint("topic_name", "Topic", "name")},
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("Expected %+v, got: %+v", expected, actual)
+		}
+	})
+	outer.Run("falls back to procedures before 4.2", func(t *testing.T) {
+		// the fake server rejects the SHOW commands with a syntax error, since they are not scripted
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"CALL db.indexes()": {
+				Keys: []string{"id", "name", "state", "populationPercent", "uniqueness", "type", "entityType", "labelsOrTypes", "properties", "provider"},
+				Records: [][]any{
+					{1, "index_1", "ONLINE", 100.0, "NONUNIQUE", "BTREE", "NODE", []any{"Person"}, []any{"name"}, map[string]any{}},
+					{2, "constraint_1", "ONLINE", 100.0, "UNIQUE", "BTREE", "NODE", []any{"Topic"}, []any{"name"}, map[string]any{}},
+				},
+			},
+			"CALL db.constraints()": {
+				Keys: []string{"name", "description"},
+				Records: [][]any{
+					{"constraint_1", "CONSTRAINT ON ( topic:Topic ) ASSERT (topic.name) IS UNIQUE"},
+					{"constraint_2", "CONSTRAINT ON ( project:Project ) ASSERT (project.name, project.version) IS NODE KEY"},
+					{"constraint_3", "CONSTRAINT ON ()-[ works_on:WORKS_ON ]-() ASSERT exists(works_on.since)"},
+				},
+			},
+		})
+
+		actual, err := schema.NewInspector(driver).Read(ctx)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if owner := actual.Indexes[1].OwningConstraint; owner != "constraint_1" {
+			t.Errorf("Expected unique index to be owned by constraint_1, got: %q", owner)
+		}
+		expected := []schema.Constraint{
+			schema.UniqueConstraint("constraint_1", "Topic", "name"),
+			schema.NodeKeyConstraint("constraint_2", "Project", "name", "version"),
+			{Name: "constraint_3", Type: schema.RelationshipPropertyExistence, EntityType: schema.Relationship,
+				LabelsOrTypes: []string{"WORKS_ON"}, Properties: []string{"since"}},
+		}
+		if !reflect.DeepEqual(actual.Constraints, expected) {
+			t.Errorf("Expected %+v, got: %+v", expected, actual.Constraints)
+		}
+	})
+	outer.Run("creates the missing indexes and constraints", func(t *testing.T) {
+		server, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"SHOW INDEXES":     {Keys: []string{"name", "type", "entityType", "labelsOrTypes", "properties", "owningConstraint"}},
+			"SHOW CONSTRAINTS": {Keys: []string{"name", "type", "entityType", "labelsOrTypes", "properties"}},
+			"CREATE INDEX person_name IF NOT EXISTS FOR (n:Person) ON (n.name)": {Counters: map[string]int{"indexes-added": 1}},
+		})
+		inspector := schema.NewInspector(driver)
+
+		diff, err := inspector.Diff(ctx, schema.Schema{Indexes: []schema.Index{schema.NodeIndex("person_name", "Person", "name")}})
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		err = inspector.Apply(ctx, diff)
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		received := server.Received()
+		if last := received[len(received)-1].Text; last != "CREATE INDEX person_name IF NOT EXISTS FOR (n:Person) ON (n.name)" {
+			t.Errorf("Expected the missing index to be created, got: %q", last)
+		}
+	})
+}
@@ -0,0 +1,247 @@
+// Package schema reads the indexes and constraints of a database and compares them to the ones the code expects
+package schema
+
+import (
+	"fmt"
+	"graphconnect/go-driver/cypher"
+	"sort"
+	"strings"
+)
+
+// IndexType is the kind of index, as reported by SHOW INDEXES
+type IndexType string
+
+const (
+	// RangeIndex is the default index type of 5.x, it matches the BTREE indexes of 4.x
+	RangeIndex    IndexType = "RANGE"
+	TextIndex     IndexType = "TEXT"
+	PointIndex    IndexType = "POINT"
+	FulltextIndex IndexType = "FULLTEXT"
+	// LookupIndex is the kind of the token lookup indexes, created by default since 4.3
+	LookupIndex IndexType = "LOOKUP"
+	btreeIndex  IndexType = "BTREE"
+)
+
+// ConstraintType is the kind of constraint, as reported by SHOW CONSTRAINTS
+type ConstraintType string
+
+const (
+	Uniqueness                    ConstraintType = "UNIQUENESS"
+	NodeKey                       ConstraintType = "NODE_KEY"
+	NodePropertyExistence         ConstraintType = "NODE_PROPERTY_EXISTENCE"
+	RelationshipPropertyExistence ConstraintType = "RELATIONSHIP_PROPERTY_EXISTENCE"
+)
+
+// EntityType tells whether an index or a constraint applies to nodes or relationships
+type EntityType string
+
+const (
+	Node         EntityType = "NODE"
+	Relationship EntityType = "RELATIONSHIP"
+)
+
+// Schema is the set of indexes and constraints of a database
+type Schema struct {
+	Indexes     []Index
+	Constraints []Constraint
+}
+
+// Index is a database index
+type Index struct {
+	Name       string
+	Type       IndexType
+	EntityType EntityType
+	// LabelsOrTypes holds the labels of node indexes or the types of relationship indexes
+	LabelsOrTypes []string
+	Properties    []string
+	// State is only set on indexes read from the database, e.g. ONLINE or POPULATING
+	State string
+	// OwningConstraint is the name of the constraint the index backs, if any
+	OwningConstraint string
+}
+
+// Constraint is a database constraint
+type Constraint struct {
+	Name       string
+	Type       ConstraintType
+	EntityType EntityType
+	// LabelsOrTypes holds the label of node constraints or the type of relationship constraints
+	LabelsOrTypes []string
+	Properties    []string
+}
+
+// NodeIndex declares a range index on the properties of the nodes with the label
+func NodeIndex(name, label string, properties ...string) Index {
+	return Index{Name: name, Type: RangeIndex, EntityType: Node, LabelsOrTypes: []string{label}, Properties: properties}
+}
+
+// RelationshipIndex declares a range index on the properties of the relationships with the type
+func RelationshipIndex(name, relationshipType string, properties ...string) Index {
+	return Index{Name: name, Type: RangeIndex, EntityType: Relationship, LabelsOrTypes: []string{relationshipType}, Properties: properties}
+}
+
+// UniqueConstraint declares that no two nodes with the label share the same values of the properties
+func UniqueConstraint(name, label string, properties ...string) Constraint {
+	return Constraint{Name: name, Type: Uniqueness, EntityType: Node, LabelsOrTypes: []string{label}, Properties: properties}
+}
+
+// NodeKeyConstraint declares that the nodes with the label have the properties, with unique values (Enterprise Edition only)
+func NodeKeyConstraint(name, label string, properties ...string) Constraint {
+	return Constraint{Name: name, Type: NodeKey, EntityType: Node, LabelsOrTypes: []string{label}, Properties: properties}
+}
+
+// ExistenceConstraint declares that the nodes with the label have the property (Enterprise Edition only)
+func ExistenceConstraint(name, label, property string) Constraint {
+	return Constraint{Name: name, Type: NodePropertyExistence, EntityType: Node, LabelsOrTypes: []string{label}, Properties: []string{property}}
+}
+
+func (index Index) String() string {
+	return fmt.Sprintf("%s index %s on %s", index.kind(), displayName(index.Name), describe(index.EntityType, index.LabelsOrTypes, index.Properties))
+}
+
+func (constraint Constraint) String() string {
+	return fmt.Sprintf("%s constraint %s on %s", constraint.Type, displayName(constraint.Name), describe(constraint.EntityType, constraint.LabelsOrTypes, constraint.Properties))
+}
+
+// CreateStatement returns the statement creating the index unless an equivalent one exists, it requires Neo4j 4.4 or later
+func (index Index) CreateStatement() string {
+	variable := "n"
+	if index.EntityType == Relationship {
+		variable = "r"
+	}
+	var statement strings.Builder
+	statement.WriteString("CREATE ")
+	if kind := index.kind(); kind != RangeIndex {
+		statement.WriteString(string(kind) + " ")
+	}
+	statement.WriteString("INDEX ")
+	if index.Name != "" {
+		statement.WriteString(cypher.Quote(index.Name) + " ")
+	}
+	statement.WriteString("IF NOT EXISTS FOR " + pattern(index.EntityType, variable, index.LabelsOrTypes))
+	switch index.kind() {
+	case LookupIndex:
+		if index.EntityType == Relationship {
+			return statement.String() + " ON EACH type(r)"
+		}
+		return statement.String() + " ON EACH labels(n)"
+	case FulltextIndex:
+		return statement.String() + " ON EACH [" + properties(variable, index.Properties) + "]"
+	}
+	return statement.String() + " ON (" + properties(variable, index.Properties) + ")"
+}
+
+// CreateStatement returns the statement creating the constraint unless an equivalent one exists, it requires Neo4j 4.4 or later
+func (constraint Constraint) CreateStatement() string {
+	variable := "n"
+	if constraint.EntityType == Relationship {
+		variable = "r"
+	}
+	var statement strings.Builder
+	statement.WriteString("CREATE CONSTRAINT ")
+	if constraint.Name != "" {
+		statement.WriteString(cypher.Quote(constraint.Name) + " ")
+	}
+	statement.WriteString("IF NOT EXISTS FOR " + pattern(constraint.EntityType, variable, constraint.LabelsOrTypes) + " REQUIRE ")
+	required := properties(variable, constraint.Properties)
+	if len(constraint.Properties) != 1 {
+		required = "(" + required + ")"
+	}
+	switch constraint.Type {
+	case NodeKey:
+		return statement.String() + required + " IS NODE KEY"
+	case NodePropertyExistence, RelationshipPropertyExistence:
+		return statement.String() + required + " IS NOT NULL"
+	}
+	return statement.String() + required + " IS UNIQUE"
+}
+
+// kind returns the type of the index, the indexes of 4.x and the ones declared without a type are range indexes
+func (index Index) kind() IndexType {
+	if index.Type == "" || index.Type == btreeIndex {
+		return RangeIndex
+	}
+	return index.Type
+}
+
+// matches reports whether both indexes index the same properties the same way, regardless of their name
+func (index Index) matches(other Index) bool {
+	return index.kind() == other.kind() && sameEntities(index.EntityType, other.EntityType) &&
+		sameElements(index.LabelsOrTypes, other.LabelsOrTypes) && equal(index.Properties, other.Properties)
+}
+
+// matches reports whether both constraints constrain the same properties the same way, regardless of their name
+func (constraint Constraint) matches(other Constraint) bool {
+	return constraint.Type == other.Type && sameEntities(constraint.EntityType, other.EntityType) &&
+		sameElements(constraint.LabelsOrTypes, other.LabelsOrTypes) && sameElements(constraint.Properties, other.Properties)
+}
+
+// sameEntities compares the entity types, declared indexes and constraints apply to nodes by default
+func sameEntities(entityType, other EntityType) bool {
+	if entityType == "" {
+		entityType = Node
+	}
+	if other == "" {
+		other = Node
+	}
+	return entityType == other
+}
+
+func describe(entityType EntityType, labelsOrTypes []string, properties []string) string {
+	if entityType == Relationship {
+		return fmt.Sprintf("()-[:%s]-()(%s)", strings.Join(labelsOrTypes, "|"), strings.Join(properties, ", "))
+	}
+	return fmt.Sprintf(":%s(%s)", strings.Join(labelsOrTypes, "|"), strings.Join(properties, ", "))
+}
+
+func displayName(name string) string {
+	if name == "" {
+		return "(unnamed)"
+	}
+	return name
+}
+
+func pattern(entityType EntityType, variable string, labelsOrTypes []string) string {
+	quoted := make([]string, len(labelsOrTypes))
+	for i, labelOrType := range labelsOrTypes {
+		quoted[i] = cypher.Quote(labelOrType)
+	}
+	if len(quoted) == 0 {
+		if entityType == Relationship {
+			return "()-[" + variable + "]-()"
+		}
+		return "(" + variable + ")"
+	}
+	if entityType == Relationship {
+		return "()-[" + variable + ":" + strings.Join(quoted, "|") + "]-()"
+	}
+	return "(" + variable + ":" + strings.Join(quoted, "|") + ")"
+}
+
+func properties(variable string, names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = variable + "." + cypher.Quote(name)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func equal(values, others []string) bool {
+	if len(values) != len(others) {
+		return false
+	}
+	for i := range values {
+		if values[i] != others[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sameElements(values, others []string) bool {
+	sortedValues := append([]string(nil), values...)
+	sortedOthers := append([]string(nil), others...)
+	sort.Strings(sortedValues)
+	sort.Strings(sortedOthers)
+	return equal(sortedValues, sortedOthers)
+}
@@ -0,0 +1,50 @@
+package schema_test
+
+import (
+	"graphconnect/go-driver/schema"
+	"testing"
+)
+
+func TestSchema(outer *testing.T) {
+	outer.Run("creates indexes", func(t *testing.T) {
+		for _, testCase := range []struct {
+			index    schema.Index
+			expected string
+		}{
+			{schema.NodeIndex("person_name", "Person", "name"),
+				"CREATE INDEX person_name IF NOT EXISTS FOR (n:Person) ON (n.name)"},
+			{schema.RelationshipIndex("", "WORKS_ON", "since", "role"),
+				"CREATE INDEX IF NOT EXISTS FOR ()-[r:WORKS_ON]-() ON (r.since, r.role)"},
+			{schema.Index{Name: "bio", Type: schema.TextIndex, LabelsOrTypes: []string{"Person"}, Properties: []string{"bio"}},
+				"CREATE TEXT INDEX bio IF NOT EXISTS FOR (n:Person) ON (n.bio)"},
+			{schema.Index{Name: "search", Type: schema.FulltextIndex, LabelsOrTypes: []string{"Project", "Topic"}, Properties: []string{"name", "description"}},
+				"CREATE FULLTEXT INDEX search IF NOT EXISTS FOR (n:Project|Topic) ON EACH [n.name, n.description]"},
+			{schema.NodeIndex("odd name", "Music Project", "full name"),
+				"CREATE INDEX `odd name` IF NOT EXISTS FOR (n:`Music Project`) ON (n.`full name`)"},
+		} {
+			if statement := testCase.index.CreateStatement(); statement != testCase.expected {
+				t.Errorf("Expected %q, got: %q", testCase.expected, statement)
+			}
+		}
+	})
+	outer.Run("creates constraints", func(t *testing.T) {
+		for _, testCase := range []struct {
+			constraint schema.Constraint
+			expected   string
+		}{
+			{schema.UniqueConstraint("person_name", "Person", "name"),
+				"CREATE CONSTRAINT person_name IF NOT EXISTS FOR (n:Person) REQUIRE n.name IS UNIQUE"},
+			{schema.NodeKeyConstraint("project_key", "Project", "name", "version"),
+				"CREATE CONSTRAINT project_key IF NOT EXISTS FOR (n:Project) REQUIRE (n.name, n.version) IS NODE KEY"},
+			{schema.ExistenceConstraint("", "Topic", "name"),
+				"CREATE CONSTRAINT IF NOT EXISTS FOR (n:Topic) REQUIRE n.name IS NOT NULL"},
+			{schema.Constraint{Name: "since", Type: schema.RelationshipPropertyExistence, EntityType: schema.Relationship,
+				LabelsOrTypes: []string{"WORKS_ON"}, Properties: []string{"since"}},
+				"CREATE CONSTRAINT since IF NOT EXISTS FOR ()-[r:WORKS_ON]-() REQUIRE r.since IS NOT NULL"},
+		} {
+			if statement := testCase.constraint.CreateStatement(); statement != testCase.expected {
+				t.Errorf("Expected %q, got: %q", testCase.expected, statement)
+			}
+		}
+	})
+}
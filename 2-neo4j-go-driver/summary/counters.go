@@ -0,0 +1,108 @@
+// Package summary reads the summary counters of the queries of the 5.x and 4.x drivers, so that they can be summed,
+// compared and reported
+package summary
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Counters are the summary counters of one or several queries
+type Counters struct {
+	NodesCreated         int
+	NodesDeleted         int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	PropertiesSet        int
+	LabelsAdded          int
+	LabelsRemoved        int
+	IndexesAdded         int
+	IndexesRemoved       int
+	ConstraintsAdded     int
+	ConstraintsRemoved   int
+}
+
+// DriverCounters are the counters of a query summary, of the 5.x or the 4.x driver, e.g. summary.Counters()
+type DriverCounters interface {
+	NodesCreated() int
+	NodesDeleted() int
+	RelationshipsCreated() int
+	RelationshipsDeleted() int
+	PropertiesSet() int
+	LabelsAdded() int
+	LabelsRemoved() int
+	IndexesAdded() int
+	IndexesRemoved() int
+	ConstraintsAdded() int
+	ConstraintsRemoved() int
+}
+
+// CountersOf copies the counters of a query summary
+func CountersOf(counters DriverCounters) Counters {
+	return Counters{
+		NodesCreated:         counters.NodesCreated(),
+		NodesDeleted:         counters.NodesDeleted(),
+		RelationshipsCreated: counters.RelationshipsCreated(),
+		RelationshipsDeleted: counters.RelationshipsDeleted(),
+		PropertiesSet:        counters.PropertiesSet(),
+		LabelsAdded:          counters.LabelsAdded(),
+		LabelsRemoved:        counters.LabelsRemoved(),
+		IndexesAdded:         counters.IndexesAdded(),
+		IndexesRemoved:       counters.IndexesRemoved(),
+		ConstraintsAdded:     counters.ConstraintsAdded(),
+		ConstraintsRemoved:   counters.ConstraintsRemoved(),
+	}
+}
+
+// Add adds the other counters
+func (counters *Counters) Add(other Counters) {
+	counters.NodesCreated += other.NodesCreated
+	counters.NodesDeleted += other.NodesDeleted
+	counters.RelationshipsCreated += other.RelationshipsCreated
+	counters.RelationshipsDeleted += other.RelationshipsDeleted
+	counters.PropertiesSet += other.PropertiesSet
+	counters.LabelsAdded += other.LabelsAdded
+	counters.LabelsRemoved += other.LabelsRemoved
+	counters.IndexesAdded += other.IndexesAdded
+	counters.IndexesRemoved += other.IndexesRemoved
+	counters.ConstraintsAdded += other.ConstraintsAdded
+	counters.ConstraintsRemoved += other.ConstraintsRemoved
+}
+
+// String lists the non-zero counters, e.g. "1 nodes created, 2 properties set"
+func (counters Counters) String() string {
+	var parts []string
+	for _, counter := range counters.List() {
+		if counter.Count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counter.Count, strings.ReplaceAll(counter.Name, "_", " ")))
+		}
+	}
+	if len(parts) == 0 {
+		return "no updates"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Counter is a named counter, see Counters.List
+type Counter struct {
+	// Name is the snake case name of the counter, e.g. "nodes_created"
+	Name  string
+	Count int
+}
+
+// List returns all the counters, in the order of the fields of Counters
+func (counters Counters) List() []Counter {
+	return []Counter{
+		{"nodes_created", counters.NodesCreated},
+		{"nodes_deleted", counters.NodesDeleted},
+		{"relationships_created", counters.RelationshipsCreated},
+		{"relationships_deleted", counters.RelationshipsDeleted},
+		{"properties_set", counters.PropertiesSet},
+		{"labels_added", counters.LabelsAdded},
+		{"labels_removed", counters.LabelsRemoved},
+		{"indexes_added", counters.IndexesAdded},
+		{"indexes_removed", counters.IndexesRemoved},
+		{"constraints_added", counters.ConstraintsAdded},
+		{"constraints_removed", counters.ConstraintsRemoved},
+	}
+}
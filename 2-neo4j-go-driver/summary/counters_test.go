@@ -0,0 +1,43 @@
+package summary_test
+
+import (
+	"context"
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/go-driver/summary"
+	"graphconnect/neo4jtest/fakebolt"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestCounters(outer *testing.T) {
+	ctx := context.Background()
+
+	outer.Run("sums the counters of query summaries", func(t *testing.T) {
+		_, driver := workshoptest.StartFakeServer(t, fakebolt.Script{
+			"CREATE (:Person {name: 'Eric', since: 2019})": {Counters: map[string]int{"nodes-created": 1, "labels-added": 1, "properties-set": 2}},
+		})
+		result, err := neo4j.ExecuteQuery(ctx, driver, "CREATE (:Person {name: 'Eric', since: 2019})", nil, neo4j.EagerResultTransformer)
+		if err != nil {
+			t.Fatalf("Could not run query: %v", err)
+		}
+		var counters summary.Counters
+
+		counters.Add(summary.CountersOf(result.Summary.Counters()))
+		counters.Add(summary.Counters{RelationshipsCreated: 1})
+
+		if expected := (summary.Counters{NodesCreated: 1, RelationshipsCreated: 1, PropertiesSet: 2, LabelsAdded: 1}); counters != expected {
+			t.Errorf("Expected %+v, got: %+v", expected, counters)
+		}
+	})
+	outer.Run("lists the non-zero counters", func(t *testing.T) {
+		counters := summary.Counters{NodesCreated: 1, PropertiesSet: 2}
+
+		if expected := "1 nodes created, 2 properties set"; counters.String() != expected {
+			t.Errorf("Expected %q, got: %q", expected, counters.String())
+		}
+		if expected := "no updates"; (summary.Counters{}).String() != expected {
+			t.Errorf("Expected %q, got: %q", expected, summary.Counters{}.String())
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package workshop_test
+
+import (
+	"fmt"
+	"graphconnect/neo4jtest"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+const username = "neo4j"
+const password = "s3cr3t"
+
+func TestNeo4jDriverConnectivity(outer *testing.T) {
+
+	neo4jServer := neo4jtest.Isolate(outer)
+	// Run `go test -v -run TestNeo4jDriverConnectivity/'creates a Neo4j driver and verify connectivity' ./2-neo4j-go-driver/v4/...`
+	outer.Run("creates a Neo4j driver and verify connectivity", func(t *testing.T) {
+		// TODO: fix the createDriver function below
+		driver := createDriver(t, neo4jServer)
+		defer func() {
+			if err := driver.Close(); err != nil {
+				t.Fatalf("Could not close driver: %v", err)
+			}
+		}()
+
+		err := driver.VerifyConnectivity()
+
+		if err != nil {
+			t.Fatalf("Expected driver to connect to the container but did not: %v", err)
+		}
+	})
+}
+
+func createDriver(t *testing.T, server neo4jtest.Neo4jServer) neo4j.Driver {
+	uri := server.BoltURI()
+	auth := server.AuthToken()
+	// TODO: create the driver to connect to the running server
+	panic(fmt.Errorf("connect driver to %s with %v", uri, auth))
+}
@@ -0,0 +1,117 @@
+package workshop_test
+
+import (
+	"fmt"
+	"graphconnect/go-driver/graph"
+	"graphconnect/neo4jtest"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+func TestNeo4jDriverResultMapping(outer *testing.T) {
+
+	neo4jServer := neo4jtest.Isolate(outer)
+
+	driver := createDriver(outer, neo4jServer)
+	defer func() {
+		if err := driver.Close(); err != nil {
+			outer.Errorf("Could not close driver: %v", err)
+		}
+	}()
+	summaries := insertSmallGraph(outer, driver)
+	// the small graph holds 4 persons, 3 projects and 1 topic, and 6 relationships between them
+	neo4jtest.AssertCounters(outer, summaries[1].Counters, neo4jtest.Counters{
+		NodesCreated: 8, LabelsAdded: 8, PropertiesSet: 8, RelationshipsCreated: 6,
+	})
+	// Run `go test -v -run TestNeo4jDriverResultMapping/'extracts persons working on projects' ./2-neo4j-go-driver/v4/...`
+	outer.Run("extracts persons working on projects", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer func() {
+			if err := session.Close(); err != nil {
+				t.Errorf("Session could not close: %v", err)
+			}
+		}()
+
+		names, err := session.ReadTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+			var records []*neo4j.Record
+			query := "MATCH (p:Person)-[:WORKS_ON]->(:Project) RETURN p ORDER BY p.name ASC"
+			// TODO: remove next line and run query + collect records
+			fmt.Println(query)
+
+			names := make([]string, len(records))
+			for i, record := range records {
+				var name string
+				// TODO: remove next line and extract the name property from the returned nodes
+				fmt.Println(record)
+				names[i] = name
+			}
+			return names, nil
+		})
+
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+		}
+		expected := []string{"Eric", "Florent", "Nikita"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v, got: %v", expected, names)
+		}
+	})
+	// Run `go test -v -run TestNeo4jDriverResultMapping/'extracts projects, sorted by maintainer count' ./2-neo4j-go-driver/v4/...`
+	outer.Run("extracts projects, sorted by maintainer count", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer func() {
+			if err := session.Close(); err != nil {
+				t.Errorf("Session could not close: %v", err)
+			}
+		}()
+
+		names, err := session.ReadTransaction(func(tx neo4j.Transaction) (interface{}, error) {
+			var result neo4j.Result
+			query := "MATCH (p:Project)<-[:WORKS_ON]-(pe:Person) WITH p, size(collect(pe)) AS count RETURN p ORDER BY count DESC"
+			// note: print plan.ExplainV4(tx, query, nil) to see how the query runs, e.g. whether it scans all the projects
+			// TODO: remove next line and run query + iterate over results
+			fmt.Println(query)
+
+			var names []string
+			for result.Next() {
+				record := result.Record()
+				// TODO: remove next line and extract project name + append it to names slice
+				fmt.Println(record)
+			}
+			err := result.Err()
+			if err != nil {
+				return nil, err
+			}
+			return names, nil
+		})
+
+		if err != nil {
+			t.Errorf("Expected nil error, got %v", err)
+		}
+		expected := []string{"GoGM", "Go Driver"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v, got: %v", expected, names)
+		}
+	})
+}
+
+func insertSmallGraph(t *testing.T, driver neo4j.Driver) []neo4jtest.SeedSummary {
+	// the indexes come from the migrations of the graph package
+	// ... the migrate package needs the 5.x driver, hence the migration files are loaded as seeds, without being recorded
+	summaries, err := neo4jtest.LoadSeed(driver, "", graph.Migrations, "*.cypher")
+	if err != nil {
+		t.Fatalf("Could not migrate database: %v", err)
+	}
+	// the small graph is shared with the GoGM module, see neo4jtest/fixtures
+	graphSummaries, err := neo4jtest.LoadSeed(driver, "", neo4jtest.Fixtures, "small_graph.cypher")
+	if err != nil {
+		t.Fatalf("Could not insert small graph: %v", err)
+	}
+	summaries = append(summaries, graphSummaries...)
+	for _, summary := range summaries {
+		t.Logf("Inserted %s", summary)
+	}
+	return summaries
+}
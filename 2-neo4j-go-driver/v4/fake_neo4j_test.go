@@ -0,0 +1,69 @@
+package workshop_test
+
+import (
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/neo4jtest"
+	"graphconnect/neo4jtest/fakebolt"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// TestFakeNeo4jServer checks the exercise helpers work against the fake server, without Docker
+func TestFakeNeo4jServer(outer *testing.T) {
+	server, err := fakebolt.Start(workshoptest.Script, fakebolt.WithAuth(username, password))
+	if err != nil {
+		outer.Fatalf("Could not start fake server: %v", err)
+	}
+	defer func() {
+		if err := server.Close(); err != nil {
+			outer.Errorf("Could not stop fake server: %v", err)
+		}
+	}()
+	driver, err := neo4j.NewDriver(server.BoltURI(), server.AuthToken())
+	if err != nil {
+		outer.Fatalf("Could not create driver: %v", err)
+	}
+	defer func() {
+		if err := driver.Close(); err != nil {
+			outer.Errorf("Could not close driver: %v", err)
+		}
+	}()
+
+	outer.Run("inserts the small graph", func(t *testing.T) {
+		summaries := insertSmallGraph(t, driver)
+
+		if len(summaries) != 2 {
+			t.Fatalf("Expected the summaries of the migration and small graph files, got: %v", summaries)
+		}
+		neo4jtest.AssertCounters(t, summaries[0].Counters, neo4jtest.Counters{IndexesAdded: 3})
+		neo4jtest.AssertCounters(t, summaries[1].Counters, neo4jtest.Counters{
+			NodesCreated: 8, LabelsAdded: 8, PropertiesSet: 8, RelationshipsCreated: 6,
+		})
+	})
+	outer.Run("extracts the answer", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer func() {
+			if err := session.Close(); err != nil {
+				t.Errorf("Could not close session: %v", err)
+			}
+		}()
+
+		answer, err := session.ReadTransaction(func(tx neo4j.Transaction) (any, error) {
+			result, err := tx.Run(
+				"RETURN REDUCE(sum=0, power IN $powersOfTwo | sum+power) AS answer",
+				map[string]any{"powersOfTwo": []int{2, 8, 32}})
+			if err != nil {
+				return nil, err
+			}
+			return extractAnswer(t, result), nil
+		})
+
+		if err != nil {
+			t.Errorf("Expected query to successfully execute but did not: %v", err)
+		}
+		if answer != int64(42) {
+			t.Errorf("Expected 42 from read transaction but got: %v", answer)
+		}
+	})
+}
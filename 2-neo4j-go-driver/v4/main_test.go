@@ -0,0 +1,18 @@
+package workshop_test
+
+import (
+	"graphconnect/go-driver/internal/workshoptest"
+	"graphconnect/neo4jtest"
+	"os"
+	"testing"
+)
+
+// TestMain starts a single Neo4j server, shared by all the tests of the package
+// This package keeps the lessons written against the 4.x driver, see ../pkg for the 5.x driver ones
+func TestMain(m *testing.M) {
+	os.Exit(neo4jtest.Main(m,
+		neo4jtest.WithVersion("4.4"),
+		neo4jtest.WithAuth(username, password),
+		neo4jtest.WithScript(workshoptest.Script),
+	))
+}
@@ -1,30 +1,24 @@
 package workshop_gogm
 
 import (
-	"context"
 	"fmt"
+	"graphconnect/neo4jtest"
+	"net/url"
 	"reflect"
+	"strconv"
 	"testing"
 
 	"github.com/mindstand/gogm/v2"
-	"github.com/testcontainers/testcontainers-go"
 )
 
+const username = "neo4j"
+const password = "s3cr3t"
+
 func TestInitializeGogm(outer *testing.T) {
-	ctx := context.Background()
-	neo4jContainer, err := StartNeo4jContainer(ctx, ContainerConfiguration{
-		Neo4jVersion: "4.4",
-		Username:     username,
-		Password:     password,
-	})
-	if err != nil {
-		outer.Errorf("Could not start container: %v", err)
+	if neo4jtest.SelectedBackend() == neo4jtest.FakeBackend {
+		outer.Skip("GoGM queries cannot be scripted, run this test against a real Neo4j server")
 	}
-	defer func() {
-		if err := neo4jContainer.Terminate(ctx); err != nil {
-			outer.Errorf("Could not stop container: %v", err)
-		}
-	}()
+	neo4jServer := neo4jtest.Wipe(outer)
 	// Run `go test -v -run TestInitializeGogm/'create a gogm instance' ./3-gogm/...`
 	outer.Run("create a gogm instance", func(t *testing.T) {
 		// TODO: below this test, the Person, Topic, and Project have problems, fix them so that GoGM initializes
@@ -40,7 +34,7 @@ func TestInitializeGogm(outer *testing.T) {
 		}
 
 		// Initialize GoGM, which will validate the structs defined below
-		if err := initGogm(ctx, neo4jContainer); err != nil {
+		if err := initGogm(neo4jServer); err != nil {
 			t.Errorf("gogm init failed, did you fix the broken schema? Error: %v", err)
 		}
 	})
@@ -127,16 +121,22 @@ func (w *WorksOnEdge) SetEndNode(v interface{}) error {
 	return nil
 }
 
-// a little helper function that initializes gogm with the neo4j container
-func initGogm(ctx context.Context, neo4jContainer testcontainers.Container) error {
-	containerIP, err := neo4jContainer.ContainerIP(ctx)
+// a little helper function that initializes gogm with the neo4j server
+func initGogm(neo4jServer neo4jtest.Neo4jServer) error {
+	boltURI, err := url.Parse(neo4jServer.BoltURI())
+	if err != nil {
+		return fmt.Errorf("failed to parse Bolt URI: %w", err)
+	}
+	port, err := strconv.Atoi(boltURI.Port())
 	if err != nil {
-		return fmt.Errorf("failed to get container IP: %w", err)
+		return fmt.Errorf("failed to parse Bolt port: %w", err)
 	}
+	// the credentials may differ from the workshop ones when an external server is configured
+	username, password := neo4jServer.Credentials()
 	config := gogm.Config{
-		Host:     containerIP,
-		Port:     7687,
-		Protocol: "bolt",
+		Host:     boltURI.Hostname(),
+		Port:     port,
+		Protocol: gogmProtocol(boltURI.Scheme),
 		// use a better method to determine this
 		// 10 is arbitrary
 		PoolSize: 10,
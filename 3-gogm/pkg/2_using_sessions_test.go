@@ -2,6 +2,7 @@ package workshop_gogm
 
 import (
 	"context"
+	"graphconnect/neo4jtest"
 	"testing"
 
 	"github.com/mindstand/gogm/v2"
@@ -9,22 +10,13 @@ import (
 )
 
 func TestUseSessions(outer *testing.T) {
-	ctx := context.Background()
-	neo4jContainer, err := StartNeo4jContainer(ctx, ContainerConfiguration{
-		Neo4jVersion: "4.4",
-		Username:     username,
-		Password:     password,
-	})
-	if err != nil {
-		outer.Errorf("Could not start container: %v", err)
+	if neo4jtest.SelectedBackend() == neo4jtest.FakeBackend {
+		outer.Skip("GoGM queries cannot be scripted, run this test against a real Neo4j server")
 	}
-	defer func() {
-		if err := neo4jContainer.Terminate(ctx); err != nil {
-			outer.Errorf("Could not stop container: %v", err)
-		}
-	}()
+	ctx := context.Background()
+	neo4jServer := neo4jtest.Wipe(outer)
 
-	if err = initGogm(ctx, neo4jContainer); err != nil {
+	if err := initGogm(neo4jServer); err != nil {
 		outer.Fatal("error initializing gogm, did you finish 1_defining_a_schema?", err.Error())
 	}
 
@@ -187,16 +179,18 @@ func TestUseSessions(outer *testing.T) {
 		if err = clearDb(ctx, sess); err != nil {
 			t.Fatal("Failed to clear database, error:", err)
 		}
-		// insert our test data
-		if err = insertData(ctx, sess); err != nil {
-			t.Fatal("Failed to insert data, error:", err)
+		// insert the small graph the driver module uses too, GoGM is not needed to read names
+		neo4jtest.Seed(t, neo4jServer, neo4jtest.Fixtures, "small_graph.cypher")
+
+		// APOC is installed by TestMain, no need for hacky string concatenation anymore ;)
+		joinedNames := "apoc.text.join(names, ' and ')"
+		if !apocInstalled(t, neo4jServer) {
+			// e.g. on an external server without APOC
+			joinedNames = "REDUCE(joined = head(names), name IN tail(names) | joined + ' and ' + name)"
 		}
-
-		// a very hacky query that avoids using APOC ;)
 		query := `MATCH (p:Person)
 		WITH COLLECT(p.name) as names
-		WITH REDUCE(merged= "" , name IN names | merged + name + ' and ') as joined
-		RETURN LEFT(joined, SIZE(joined) - 5) + " thank you for joining the " + $conference + " Go Workshop!"`
+		RETURN ` + joinedNames + ` + " thank you for joining the " + $conference + " Go Workshop!"`
 
 		res, _, err := sess.QueryRaw(ctx, query, map[string]interface{}{
 			// TODO: Oops, I forgot to se the conference name :)
@@ -214,6 +208,22 @@ func TestUseSessions(outer *testing.T) {
 	})
 }
 
+// Helper that tells whether the APOC procedures and functions are available on the server
+func apocInstalled(t *testing.T, server neo4jtest.Neo4jServer) bool {
+	driver, err := neo4jtest.NewDriver(server, "")
+	if err != nil {
+		t.Fatalf("Could not create driver: %v", err)
+	}
+	defer driver.Close()
+	session := driver.NewSession(neo4j.SessionConfig{})
+	defer session.Close()
+	result, err := session.Run("RETURN apoc.version() AS version", nil)
+	if err == nil {
+		_, err = result.Single()
+	}
+	return err == nil
+}
+
 // Helper that delete everything in the database
 func clearDb(ctx context.Context, sess gogm.SessionV2) error {
 	_, _, err := sess.QueryRaw(ctx, "match(n) detach delete n", map[string]interface{}{})
@@ -0,0 +1,26 @@
+package workshop_gogm
+
+import (
+	"strings"
+	"testing"
+)
+
+// gogmProtocol connects GoGM directly to the test server, even when its URI uses routing (neo4j://)
+// The encryption of the URI is kept, e.g. neo4j+s:// becomes bolt+s://
+func gogmProtocol(scheme string) string {
+	return "bolt" + strings.TrimPrefix(strings.TrimPrefix(scheme, "neo4j"), "bolt")
+}
+
+func TestGogmProtocol(t *testing.T) {
+	for scheme, expected := range map[string]string{
+		"bolt":      "bolt",
+		"neo4j":     "bolt",
+		"neo4j+s":   "bolt+s",
+		"neo4j+ssc": "bolt+ssc",
+		"bolt+s":    "bolt+s",
+	} {
+		if protocol := gogmProtocol(scheme); protocol != expected {
+			t.Errorf("Expected %s for %s, got: %s", expected, scheme, protocol)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package neo4jtest
+
+import (
+	"context"
+	"fmt"
+	"graphconnect/neo4jtest/fakebolt"
+	"os"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// BackendEnvVar is the environment variable selecting the BackendKind tests run against
+const BackendEnvVar = "NEO4J_TEST_BACKEND"
+
+// Environment variables configuring the external backend
+const (
+	URIEnvVar      = "NEO4J_TEST_URI"
+	UsernameEnvVar = "NEO4J_TEST_USERNAME"
+	PasswordEnvVar = "NEO4J_TEST_PASSWORD"
+	// HTTPURIEnvVar is optional, the HTTP URI of the external server is empty without it
+	HTTPURIEnvVar = "NEO4J_TEST_HTTP_URI"
+)
+
+// BackendKind identifies a Backend implementation
+type BackendKind string
+
+const (
+	// ContainerBackend starts a Neo4j container, this is the default
+	ContainerBackend BackendKind = "testcontainers"
+	// ExternalBackend connects to an already running server, configured with URIEnvVar, UsernameEnvVar and PasswordEnvVar
+	ExternalBackend BackendKind = "external"
+	// FakeBackend starts an in-process fakebolt.Server answering the queries scripted with WithScript
+	FakeBackend BackendKind = "fake"
+)
+
+// Backend provides the Neo4j server tests run against
+type Backend interface {
+	Start(ctx context.Context, config ContainerConfiguration) (Neo4jServer, error)
+}
+
+// SelectedBackend returns the kind of backend configured with BackendEnvVar
+func SelectedBackend() BackendKind {
+	if kind := os.Getenv(BackendEnvVar); kind != "" {
+		return BackendKind(kind)
+	}
+	return ContainerBackend
+}
+
+// NewBackend returns the Backend implementation of the given kind
+func NewBackend(kind BackendKind) (Backend, error) {
+	switch kind {
+	case ContainerBackend:
+		return containerBackend{}, nil
+	case ExternalBackend:
+		return externalBackend{
+			uri:      os.Getenv(URIEnvVar),
+			httpURI:  os.Getenv(HTTPURIEnvVar),
+			username: os.Getenv(UsernameEnvVar),
+			password: os.Getenv(PasswordEnvVar),
+		}, nil
+	case FakeBackend:
+		return fakeBackend{}, nil
+	}
+	return nil, fmt.Errorf("unknown backend %q set in %s, expected one of: %s, %s, %s",
+		kind, BackendEnvVar, ContainerBackend, ExternalBackend, FakeBackend)
+}
+
+// Start starts the Neo4j server of the backend selected with BackendEnvVar
+func Start(ctx context.Context, options ...Option) (Neo4jServer, error) {
+	backend, err := NewBackend(SelectedBackend())
+	if err != nil {
+		return nil, err
+	}
+	return backend.Start(ctx, newContainerConfiguration(options))
+}
+
+type containerBackend struct{}
+
+func (containerBackend) Start(ctx context.Context, config ContainerConfiguration) (Neo4jServer, error) {
+	// note: returning the *Neo4jContainer as-is would turn a nil container into a non-nil Neo4jServer
+	container, err := startNeo4jContainer(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return container, nil
+}
+
+type externalBackend struct {
+	uri      string
+	httpURI  string
+	username string
+	password string
+}
+
+// Start checks the external server is reachable, the configured credentials are used when none are set in the environment
+func (backend externalBackend) Start(ctx context.Context, config ContainerConfiguration) (Neo4jServer, error) {
+	if backend.uri == "" {
+		return nil, fmt.Errorf("%s must be set when %s is %s", URIEnvVar, BackendEnvVar, ExternalBackend)
+	}
+	if backend.username == "" {
+		backend.username, backend.password = config.Username, config.Password
+	}
+	server := &externalServer{uri: backend.uri, httpURI: backend.httpURI, username: backend.username, password: backend.password}
+	driver, err := neo4j.NewDriver(server.uri, server.AuthToken())
+	if err != nil {
+		return nil, err
+	}
+	defer driver.Close()
+	if err := driver.VerifyConnectivity(); err != nil {
+		return nil, fmt.Errorf("could not connect to external server %s: %w", server.uri, err)
+	}
+	return server, nil
+}
+
+// externalServer is managed outside the tests, hence never terminated by them
+type externalServer struct {
+	uri      string
+	httpURI  string
+	username string
+	password string
+}
+
+func (server *externalServer) BoltURI() string {
+	return server.uri
+}
+
+func (server *externalServer) HTTPURI() string {
+	return server.httpURI
+}
+
+func (server *externalServer) AuthToken() neo4j.AuthToken {
+	return neo4j.BasicAuth(server.username, server.password, "")
+}
+
+func (server *externalServer) Credentials() (string, string) {
+	return server.username, server.password
+}
+
+func (server *externalServer) Terminate(context.Context) error {
+	return nil
+}
+
+type fakeBackend struct{}
+
+func (fakeBackend) Start(_ context.Context, config ContainerConfiguration) (Neo4jServer, error) {
+	if config.Script == nil {
+		return nil, fmt.Errorf("the %s backend requires a script, see WithScript", FakeBackend)
+	}
+	server, err := fakebolt.Start(config.Script, fakebolt.WithAuth(config.Username, config.Password))
+	if err != nil {
+		return nil, err
+	}
+	return server, nil
+}
@@ -0,0 +1,121 @@
+package neo4jtest_test
+
+import (
+	"context"
+	"graphconnect/neo4jtest"
+	"graphconnect/neo4jtest/fakebolt"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+func TestBackendSelection(outer *testing.T) {
+	ctx := context.Background()
+
+	outer.Run("defaults to testcontainers", func(t *testing.T) {
+		t.Setenv(neo4jtest.BackendEnvVar, "")
+
+		if kind := neo4jtest.SelectedBackend(); kind != neo4jtest.ContainerBackend {
+			t.Errorf("Expected %s backend, got: %s", neo4jtest.ContainerBackend, kind)
+		}
+	})
+	outer.Run("rejects unknown backends", func(t *testing.T) {
+		t.Setenv(neo4jtest.BackendEnvVar, "in-memory")
+
+		_, err := neo4jtest.Start(ctx)
+
+		if err == nil || !strings.Contains(err.Error(), `unknown backend "in-memory"`) {
+			t.Errorf("Expected unknown backend error, got: %v", err)
+		}
+	})
+	outer.Run("starts the fake backend with the given script", func(t *testing.T) {
+		t.Setenv(neo4jtest.BackendEnvVar, string(neo4jtest.FakeBackend))
+
+		server, err := neo4jtest.Start(ctx,
+			neo4jtest.WithAuth("neo4j", "s3cr3t"),
+			neo4jtest.WithScript(fakebolt.Script{}))
+		if err != nil {
+			t.Fatalf("Could not start fake backend: %v", err)
+		}
+		defer terminate(t, server)
+
+		verifyConnectivity(t, server.BoltURI(), server.AuthToken())
+	})
+	outer.Run("requires a script for the fake backend", func(t *testing.T) {
+		t.Setenv(neo4jtest.BackendEnvVar, string(neo4jtest.FakeBackend))
+
+		_, err := neo4jtest.Start(ctx)
+
+		if err == nil || !strings.Contains(err.Error(), "requires a script") {
+			t.Errorf("Expected missing script error, got: %v", err)
+		}
+	})
+	outer.Run("requires a URI for the external backend", func(t *testing.T) {
+		t.Setenv(neo4jtest.BackendEnvVar, string(neo4jtest.ExternalBackend))
+		t.Setenv(neo4jtest.URIEnvVar, "")
+
+		_, err := neo4jtest.Start(ctx)
+
+		if err == nil || !strings.Contains(err.Error(), neo4jtest.URIEnvVar) {
+			t.Errorf("Expected missing URI error, got: %v", err)
+		}
+	})
+	outer.Run("connects to the external backend with the environment credentials", func(t *testing.T) {
+		external, err := fakebolt.Start(fakebolt.Script{}, fakebolt.WithAuth("dev", "shared-secret"))
+		if err != nil {
+			t.Fatalf("Could not start fake server: %v", err)
+		}
+		defer terminate(t, external)
+		t.Setenv(neo4jtest.BackendEnvVar, string(neo4jtest.ExternalBackend))
+		t.Setenv(neo4jtest.URIEnvVar, external.BoltURI())
+		t.Setenv(neo4jtest.UsernameEnvVar, "dev")
+		t.Setenv(neo4jtest.PasswordEnvVar, "shared-secret")
+		t.Setenv(neo4jtest.HTTPURIEnvVar, "http://localhost:7474")
+
+		server, err := neo4jtest.Start(ctx, neo4jtest.WithAuth("neo4j", "s3cr3t"))
+		if err != nil {
+			t.Fatalf("Could not start external backend: %v", err)
+		}
+		defer terminate(t, server)
+
+		if server.BoltURI() != external.BoltURI() {
+			t.Errorf("Expected URI %s, got: %s", external.BoltURI(), server.BoltURI())
+		}
+		if server.HTTPURI() != "http://localhost:7474" {
+			t.Errorf("Expected HTTP URI http://localhost:7474, got: %s", server.HTTPURI())
+		}
+		verifyConnectivity(t, server.BoltURI(), server.AuthToken())
+	})
+	outer.Run("returns no server when the container cannot start", func(t *testing.T) {
+		t.Setenv(neo4jtest.BackendEnvVar, string(neo4jtest.ContainerBackend))
+
+		// the missing jar fails the start before Docker is reached
+		server, err := neo4jtest.Start(ctx, neo4jtest.WithPlugins(neo4jtest.Plugin{Name: "missing", JarPath: "testdata/missing.jar"}))
+
+		if err == nil || server != nil {
+			t.Errorf("Expected an error and no server, got: %v and %#v", err, server)
+		}
+	})
+}
+
+func verifyConnectivity(t *testing.T, uri string, auth neo4j.AuthToken) {
+	driver, err := neo4j.NewDriver(uri, auth)
+	if err != nil {
+		t.Fatalf("Could not create driver: %v", err)
+	}
+	defer func() {
+		if err := driver.Close(); err != nil {
+			t.Errorf("Could not close driver: %v", err)
+		}
+	}()
+	if err := driver.VerifyConnectivity(); err != nil {
+		t.Errorf("Expected driver to connect, but did not: %v", err)
+	}
+}
+
+func terminate(t *testing.T, server neo4jtest.Neo4jServer) {
+	if err := server.Terminate(context.Background()); err != nil {
+		t.Errorf("Could not terminate server: %v", err)
+	}
+}
@@ -0,0 +1,358 @@
+package neo4jtest
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// ClusterEnvVar must be set to "yes" to run the tests calling Cluster, which start several Enterprise Edition containers
+const ClusterEnvVar = "NEO4J_TEST_CLUSTER"
+
+const clusterFormationTimeout = 5 * time.Minute
+const discoveryPort = 5000
+
+// ClusterTopology is the number of members of each kind in a Neo4j cluster
+type ClusterTopology struct {
+	// Cores take part in leader elections, 3 cores tolerate the failure of one of them
+	Cores int
+	// ReadReplicas only serve reads, they are called secondaries since Neo4j 5
+	ReadReplicas int
+}
+
+// Neo4jCluster is a running Neo4j Enterprise Edition cluster, whose members share a Docker network
+// Each member advertises a localhost address whose port is the same inside and outside the container
+// so that the routing tables the driver receives are usable from the tests
+type Neo4jCluster struct {
+	network      testcontainers.Network
+	cores        []*Neo4jContainer
+	readReplicas []*Neo4jContainer
+	username     string
+	password     string
+	majorVersion int
+}
+
+// clusterMember is the configuration of a single cluster member
+type clusterMember struct {
+	alias    string
+	readOnly bool
+	boltPort int
+}
+
+// StartNeo4jCluster starts the cluster members concurrently and waits until the default database has a leader
+// and is online on every member
+// The Enterprise Edition image of the configured version is used
+func StartNeo4jCluster(ctx context.Context, topology ClusterTopology, options ...Option) (*Neo4jCluster, error) {
+	if topology.Cores < 2 {
+		return nil, fmt.Errorf("a cluster needs at least 2 cores, got: %d", topology.Cores)
+	}
+	config := newContainerConfiguration(append([]Option{WithEnterpriseEdition()}, options...))
+	if config.TLS {
+		return nil, errors.New("clusters do not support TLS yet")
+	}
+	members, err := newClusterMembers(topology)
+	if err != nil {
+		return nil, err
+	}
+	networkName, err := randomNetworkName()
+	if err != nil {
+		return nil, err
+	}
+	network, err := testcontainers.GenericNetwork(ctx, testcontainers.GenericNetworkRequest{
+		NetworkRequest: testcontainers.NetworkRequest{Name: networkName, CheckDuplicate: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not create cluster network: %w", err)
+	}
+	cluster := &Neo4jCluster{
+		network:      network,
+		username:     config.Username,
+		password:     config.Password,
+		majorVersion: config.majorVersion(),
+	}
+	// cores wait for each other before enabling Bolt, so they must start concurrently
+	containers := make([]*Neo4jContainer, len(members))
+	errs := make([]error, len(members))
+	var group sync.WaitGroup
+	for i, member := range members {
+		group.Add(1)
+		go func(i int, member clusterMember) {
+			defer group.Done()
+			containers[i], errs[i] = startClusterMember(ctx, networkName, member, config.clusterMemberConfiguration(topology, members, member))
+		}(i, member)
+	}
+	group.Wait()
+	cluster.cores = containers[:topology.Cores]
+	cluster.readReplicas = containers[topology.Cores:]
+	for i, err := range errs {
+		if err != nil {
+			err = fmt.Errorf("could not start cluster member %s: %w", members[i].alias, err)
+			return nil, cluster.terminateAfter(ctx, err)
+		}
+	}
+	if err := cluster.waitForFormation(ctx); err != nil {
+		return nil, cluster.terminateAfter(ctx, err)
+	}
+	return cluster, nil
+}
+
+// Cluster starts a cluster dedicated to the calling test, which is skipped unless ClusterEnvVar is set to "yes"
+// The options are applied after the ones given to Main and the cluster is terminated when the test completes
+func Cluster(t testing.TB, topology ClusterTopology, options ...Option) *Neo4jCluster {
+	t.Helper()
+	if os.Getenv(ClusterEnvVar) != "yes" || SelectedBackend() != ContainerBackend {
+		t.Skipf("Cluster tests only run with the %s backend and %s=yes", ContainerBackend, ClusterEnvVar)
+	}
+	shared.Lock()
+	allOptions := append(append([]Option(nil), shared.options...), options...)
+	shared.Unlock()
+	cluster, err := StartNeo4jCluster(context.Background(), topology, allOptions...)
+	if err != nil {
+		t.Fatalf("Could not start Neo4j cluster: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := cluster.Terminate(context.Background()); err != nil {
+			t.Errorf("Could not stop Neo4j cluster: %v", err)
+		}
+	})
+	LogOnFailure(t, cluster)
+	return cluster
+}
+
+func newClusterMembers(topology ClusterTopology) ([]clusterMember, error) {
+	var members []clusterMember
+	for i := 1; i <= topology.Cores+topology.ReadReplicas; i++ {
+		member := clusterMember{alias: fmt.Sprintf("core%d", i)}
+		if i > topology.Cores {
+			member = clusterMember{alias: fmt.Sprintf("replica%d", i-topology.Cores), readOnly: true}
+		}
+		port, err := freePort()
+		if err != nil {
+			return nil, fmt.Errorf("could not find a free port for %s: %w", member.alias, err)
+		}
+		member.boltPort = port
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func startClusterMember(ctx context.Context, networkName string, member clusterMember, config ContainerConfiguration) (*Neo4jContainer, error) {
+	request, err := newContainerRequest(config)
+	if err != nil {
+		return nil, err
+	}
+	request.ExposedPorts = []string{fmt.Sprintf("%d:%d/tcp", member.boltPort, member.boltPort), "7474/tcp"}
+	request.Networks = []string{networkName}
+	request.NetworkAliases = map[string][]string{networkName: {member.alias}}
+	request.WaitingFor = wait.ForLog("Bolt enabled").WithStartupTimeout(clusterFormationTimeout)
+	return startContainer(ctx, request, config, nat.Port(strconv.Itoa(member.boltPort)), nil)
+}
+
+// clusterMemberConfiguration adds the clustering settings of the member to a copy of the configuration
+func (config ContainerConfiguration) clusterMemberConfiguration(topology ClusterTopology, members []clusterMember, member clusterMember) ContainerConfiguration {
+	var discoveryMembers []string
+	for _, other := range members[:topology.Cores] {
+		discoveryMembers = append(discoveryMembers, fmt.Sprintf("%s:%d", other.alias, discoveryPort))
+	}
+	settings := map[string]string{}
+	for key, value := range config.Settings {
+		settings[key] = value
+	}
+	if config.majorVersion() >= 5 {
+		mode := "PRIMARY"
+		if member.readOnly {
+			mode = "SECONDARY"
+		}
+		settings["initial.server.mode_constraint"] = mode
+		settings["dbms.cluster.discovery.endpoints"] = strings.Join(discoveryMembers, ",")
+		settings["initial.dbms.default_primaries_count"] = strconv.Itoa(topology.Cores)
+		settings["initial.dbms.default_secondaries_count"] = strconv.Itoa(topology.ReadReplicas)
+		settings["server.default_advertised_address"] = member.alias
+		settings["server.bolt.listen_address"] = fmt.Sprintf("0.0.0.0:%d", member.boltPort)
+		settings["server.bolt.advertised_address"] = fmt.Sprintf("localhost:%d", member.boltPort)
+	} else {
+		mode := "CORE"
+		if member.readOnly {
+			mode = "READ_REPLICA"
+		}
+		settings["dbms.mode"] = mode
+		settings["causal_clustering.initial_discovery_members"] = strings.Join(discoveryMembers, ",")
+		settings["causal_clustering.minimum_core_cluster_size_at_formation"] = strconv.Itoa(topology.Cores)
+		settings["causal_clustering.minimum_core_cluster_size_at_runtime"] = strconv.Itoa(topology.Cores)
+		settings["dbms.default_advertised_address"] = member.alias
+		settings["dbms.connector.bolt.listen_address"] = fmt.Sprintf("0.0.0.0:%d", member.boltPort)
+		settings["dbms.connector.bolt.advertised_address"] = fmt.Sprintf("localhost:%d", member.boltPort)
+	}
+	config.Settings = settings
+	return config
+}
+
+// BoltURI returns the routing URI (neo4j://) of the first core, the driver discovers the other members from it
+func (cluster *Neo4jCluster) BoltURI() string {
+	return cluster.cores[0].BoltURI()
+}
+
+// HTTPURI returns the HTTP URI of the first core
+func (cluster *Neo4jCluster) HTTPURI() string {
+	return cluster.cores[0].HTTPURI()
+}
+
+// BoltURIs returns the routing URIs of all the cores
+func (cluster *Neo4jCluster) BoltURIs() []string {
+	uris := make([]string, len(cluster.cores))
+	for i, core := range cluster.cores {
+		uris[i] = core.BoltURI()
+	}
+	return uris
+}
+
+// Cores returns the core members, in the order of their aliases: core1, core2...
+func (cluster *Neo4jCluster) Cores() []*Neo4jContainer {
+	return cluster.cores
+}
+
+// ReadReplicas returns the read replica members, in the order of their aliases: replica1, replica2...
+func (cluster *Neo4jCluster) ReadReplicas() []*Neo4jContainer {
+	return cluster.readReplicas
+}
+
+// AuthToken returns the basic authentication token matching the configured credentials
+func (cluster *Neo4jCluster) AuthToken() neo4j.AuthToken {
+	return neo4j.BasicAuth(cluster.username, cluster.password, "")
+}
+
+// Credentials returns the username and password of the initial user
+func (cluster *Neo4jCluster) Credentials() (string, string) {
+	return cluster.username, cluster.password
+}
+
+// Leader returns the core that currently accepts the writes of the default database
+// Terminate it to make the remaining cores elect a new leader
+// The query is routed to the leader of the system database, whichever core it is
+func (cluster *Neo4jCluster) Leader() (*Neo4jContainer, error) {
+	query := "SHOW DATABASE neo4j YIELD address, role WHERE role = 'leader' RETURN address"
+	if cluster.majorVersion >= 5 {
+		query = "SHOW DATABASE neo4j YIELD address, writer WHERE writer RETURN address"
+	}
+	addresses, err := collectNames(cluster, "system", query)
+	if err != nil {
+		return nil, fmt.Errorf("could not find leader: %w", err)
+	}
+	if len(addresses) != 1 {
+		return nil, fmt.Errorf("expected a single leader, got: %v", addresses)
+	}
+	for _, core := range cluster.cores {
+		if samePort(core.BoltURI(), addresses[0]) {
+			return core, nil
+		}
+	}
+	return nil, fmt.Errorf("leader %s is not a core of the cluster", addresses[0])
+}
+
+// Diagnostics concatenates the diagnostics of the members that are still running
+func (cluster *Neo4jCluster) Diagnostics(ctx context.Context) string {
+	var report strings.Builder
+	for _, member := range cluster.members() {
+		if !member.terminated {
+			report.WriteString(member.Diagnostics(ctx))
+		}
+	}
+	return report.String()
+}
+
+// Terminate stops and removes all the members, then the network they share
+func (cluster *Neo4jCluster) Terminate(ctx context.Context) error {
+	var errs []string
+	for _, member := range cluster.members() {
+		if err := member.Terminate(ctx); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if err := cluster.network.Remove(ctx); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// members returns the cores then the read replicas that could start
+func (cluster *Neo4jCluster) members() []*Neo4jContainer {
+	var members []*Neo4jContainer
+	for _, member := range append(append([]*Neo4jContainer(nil), cluster.cores...), cluster.readReplicas...) {
+		if member != nil {
+			members = append(members, member)
+		}
+	}
+	return members
+}
+
+// waitForFormation polls the cluster until the default database has a leader and is online on every member
+func (cluster *Neo4jCluster) waitForFormation(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, clusterFormationTimeout)
+	defer cancel()
+	members := len(cluster.cores) + len(cluster.readReplicas)
+	for {
+		online, err := collectNames(cluster, "system",
+			"SHOW DATABASE neo4j YIELD address, currentStatus WHERE currentStatus = 'online' RETURN address")
+		if err == nil && len(online) < members {
+			err = fmt.Errorf("database is online on %d out of %d members", len(online), members)
+		}
+		if err == nil {
+			if _, err = cluster.Leader(); err == nil {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cluster did not form (last error: %v): %w", err, ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+func (cluster *Neo4jCluster) terminateAfter(ctx context.Context, cause error) error {
+	if err := cluster.Terminate(ctx); err != nil {
+		return fmt.Errorf("%v (and could not terminate cluster: %w)", cause, err)
+	}
+	return cause
+}
+
+func samePort(uri string, address string) bool {
+	uriPort := uri[strings.LastIndex(uri, ":")+1:]
+	_, addressPort, err := net.SplitHostPort(address)
+	return err == nil && uriPort == addressPort
+}
+
+// freePort asks the system for a port that is free right now, it can be taken by the time the container starts
+func freePort() (int, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer listener.Close()
+	return listener.Addr().(*net.TCPAddr).Port, nil
+}
+
+func randomNetworkName() (string, error) {
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", err
+	}
+	return "neo4jtest-" + hex.EncodeToString(suffix), nil
+}
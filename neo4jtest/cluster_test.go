@@ -0,0 +1,65 @@
+package neo4jtest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCluster(outer *testing.T) {
+	members := []clusterMember{
+		{alias: "core1", boltPort: 20001},
+		{alias: "core2", boltPort: 20002},
+		{alias: "core3", boltPort: 20003},
+		{alias: "replica1", boltPort: 20004, readOnly: true},
+	}
+	topology := ClusterTopology{Cores: 3, ReadReplicas: 1}
+
+	outer.Run("configures causal clustering on Neo4j 4", func(t *testing.T) {
+		config := newContainerConfiguration([]Option{WithSetting("dbms.transaction.timeout", "5s")})
+
+		env := config.clusterMemberConfiguration(topology, members, members[3]).settingsEnv()
+
+		expected := map[string]string{
+			"NEO4J_dbms_transaction_timeout":                                       "5s",
+			"NEO4J_dbms_mode":                                                      "READ_REPLICA",
+			"NEO4J_causal__clustering_initial__discovery__members":                 "core1:5000,core2:5000,core3:5000",
+			"NEO4J_causal__clustering_minimum__core__cluster__size__at__formation": "3",
+			"NEO4J_causal__clustering_minimum__core__cluster__size__at__runtime":   "3",
+			"NEO4J_dbms_default__advertised__address":                              "replica1",
+			"NEO4J_dbms_connector_bolt_listen__address":                            "0.0.0.0:20004",
+			"NEO4J_dbms_connector_bolt_advertised__address":                        "localhost:20004",
+		}
+		if !reflect.DeepEqual(env, expected) {
+			t.Errorf("Expected %v, got: %v", expected, env)
+		}
+		if len(config.Settings) != 1 {
+			t.Errorf("Expected the shared settings to be left untouched, got: %v", config.Settings)
+		}
+	})
+	outer.Run("configures primaries and secondaries on Neo4j 5", func(t *testing.T) {
+		config := newContainerConfiguration([]Option{WithVersion("5.3")})
+
+		env := config.clusterMemberConfiguration(topology, members, members[1]).settingsEnv()
+
+		expected := map[string]string{
+			"NEO4J_initial_server_mode__constraint":          "PRIMARY",
+			"NEO4J_dbms_cluster_discovery_endpoints":         "core1:5000,core2:5000,core3:5000",
+			"NEO4J_initial_dbms_default__primaries__count":   "3",
+			"NEO4J_initial_dbms_default__secondaries__count": "1",
+			"NEO4J_server_default__advertised__address":      "core2",
+			"NEO4J_server_bolt_listen__address":              "0.0.0.0:20002",
+			"NEO4J_server_bolt_advertised__address":          "localhost:20002",
+		}
+		if !reflect.DeepEqual(env, expected) {
+			t.Errorf("Expected %v, got: %v", expected, env)
+		}
+	})
+	outer.Run("matches advertised addresses to members", func(t *testing.T) {
+		if !samePort("neo4j://localhost:20002", "localhost:20002") {
+			t.Errorf("Expected ports to match")
+		}
+		if samePort("neo4j://localhost:20002", "core2:7687") {
+			t.Errorf("Expected ports not to match")
+		}
+	})
+}
@@ -0,0 +1,199 @@
+// Package neo4jtest provides the Neo4j test support shared by the workshop modules
+package neo4jtest
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/docker/go-connections/nat"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+const defaultNeo4jVersion = "4.4"
+const defaultUsername = "neo4j"
+const defaultPassword = "s3cr3t"
+
+// Neo4jServer is a running Neo4j server tests can connect to, such as Neo4jContainer or fakebolt.Server
+type Neo4jServer interface {
+	BoltURI() string
+	// HTTPURI returns the URI of the Neo4j Browser and HTTP API, empty when the server has none, e.g. a fakebolt.Server
+	HTTPURI() string
+	AuthToken() neo4j.AuthToken
+	Credentials() (username, password string)
+	Terminate(ctx context.Context) error
+}
+
+// Neo4jContainer is a running Neo4j test container
+type Neo4jContainer struct {
+	container testcontainers.Container
+	boltURI   string
+	httpURI   string
+	username  string
+	password  string
+	tls       *containerTLS
+	// majorVersion selects the diagnostics queries
+	majorVersion int
+	output       *outputBuffer
+	// terminated is set once the container is removed, e.g. when a cluster member is stopped by a test
+	terminated bool
+}
+
+// StartNeo4jContainer starts a single Neo4j server and waits until Bolt is enabled
+// Without options, a Neo4j 4.4 server is started with the neo4j/s3cr3t credentials
+func StartNeo4jContainer(ctx context.Context, options ...Option) (*Neo4jContainer, error) {
+	return startNeo4jContainer(ctx, newContainerConfiguration(options))
+}
+
+func startNeo4jContainer(ctx context.Context, config ContainerConfiguration) (*Neo4jContainer, error) {
+	if !config.TLS {
+		request, err := newContainerRequest(config)
+		if err != nil {
+			return nil, err
+		}
+		return startContainer(ctx, request, config, "7687", nil)
+	}
+	tls, err := newContainerTLS()
+	if err != nil {
+		return nil, fmt.Errorf("could not generate certificates: %w", err)
+	}
+	config.Settings = config.tlsSettings()
+	request, err := newContainerRequest(config)
+	if err != nil {
+		return nil, tls.removeAfter(err)
+	}
+	request.Mounts = append(request.Mounts, testcontainers.BindMount(tls.directory, certificatesDirectory))
+	result, err := startContainer(ctx, request, config, "7687", tls)
+	if err != nil {
+		return nil, tls.removeAfter(err)
+	}
+	return result, nil
+}
+
+func newContainerRequest(config ContainerConfiguration) (testcontainers.ContainerRequest, error) {
+	request := testcontainers.ContainerRequest{
+		Image:        fmt.Sprintf("neo4j:%s", config.imageTag()),
+		ExposedPorts: []string{"7687/tcp", "7474/tcp"},
+		Env: map[string]string{
+			"NEO4J_AUTH":                     config.neo4jAuthEnvVar(),
+			"NEO4J_ACCEPT_LICENSE_AGREEMENT": "yes",
+		},
+		WaitingFor: wait.ForLog("Bolt enabled"),
+	}
+	for name, value := range config.settingsEnv() {
+		request.Env[name] = value
+	}
+	return request, configurePlugins(&request, config)
+}
+
+// startContainer starts the container, tls is nil unless the container requires encrypted Bolt connections
+func startContainer(ctx context.Context, request testcontainers.ContainerRequest, config ContainerConfiguration, boltPort nat.Port, tls *containerTLS) (*Neo4jContainer, error) {
+	container, err := testcontainers.GenericContainer(ctx,
+		testcontainers.GenericContainerRequest{
+			ContainerRequest: request,
+			Started:          true,
+		})
+	if err != nil {
+		if container != nil {
+			return nil, terminateAfter(ctx, container, withOutput(ctx, container, err))
+		}
+		return nil, err
+	}
+	result := &Neo4jContainer{
+		container:    container,
+		username:     config.Username,
+		password:     config.Password,
+		majorVersion: config.majorVersion(),
+		tls:          tls,
+		output:       followOutput(container),
+	}
+	boltScheme := "neo4j"
+	if tls != nil {
+		boltScheme = "neo4j+s"
+	}
+	if result.boltURI, err = endpoint(ctx, container, boltScheme, boltPort); err != nil {
+		return nil, terminateAfter(ctx, container, err)
+	}
+	if result.httpURI, err = endpoint(ctx, container, "http", "7474"); err != nil {
+		return nil, terminateAfter(ctx, container, err)
+	}
+	if len(config.Plugins) > 0 {
+		if err := waitForPlugins(ctx, result, config.Plugins); err != nil {
+			return nil, terminateAfter(ctx, container, err)
+		}
+		cacheDownloadedPlugins(ctx, container, config)
+	}
+	return result, nil
+}
+
+// BoltURI returns the routing URI (neo4j://, or neo4j+s:// WithTLS) the driver should connect to
+func (c *Neo4jContainer) BoltURI() string {
+	return c.boltURI
+}
+
+// CACertificate returns the PEM-encoded certificate authority of a container started WithTLS, nil otherwise
+func (c *Neo4jContainer) CACertificate() []byte {
+	if c.tls == nil {
+		return nil
+	}
+	return c.tls.caCertificate
+}
+
+// RootCAs returns the pool of the CACertificate, nil unless the container was started WithTLS
+func (c *Neo4jContainer) RootCAs() *x509.CertPool {
+	if c.tls == nil {
+		return nil
+	}
+	return c.tls.rootCAs()
+}
+
+// HTTPURI returns the URI of the Neo4j Browser and HTTP API
+func (c *Neo4jContainer) HTTPURI() string {
+	return c.httpURI
+}
+
+// AuthToken returns the basic authentication token matching the configured credentials
+func (c *Neo4jContainer) AuthToken() neo4j.AuthToken {
+	return neo4j.BasicAuth(c.username, c.password, "")
+}
+
+// Credentials returns the username and password of the initial user
+func (c *Neo4jContainer) Credentials() (string, string) {
+	return c.username, c.password
+}
+
+// Terminate stops and removes the container, terminating it again is a no-op
+func (c *Neo4jContainer) Terminate(ctx context.Context) error {
+	if c.terminated {
+		return nil
+	}
+	if err := c.container.Terminate(ctx); err != nil {
+		return err
+	}
+	c.terminated = true
+	if c.tls != nil {
+		return c.tls.remove()
+	}
+	return nil
+}
+
+func endpoint(ctx context.Context, container testcontainers.Container, scheme string, port nat.Port) (string, error) {
+	host, err := container.Host(ctx)
+	if err != nil {
+		return "", fmt.Errorf("could not get container host: %w", err)
+	}
+	mappedPort, err := container.MappedPort(ctx, port)
+	if err != nil {
+		return "", fmt.Errorf("could not get mapped port %s: %w", port, err)
+	}
+	return fmt.Sprintf("%s://%s:%d", scheme, host, mappedPort.Int()), nil
+}
+
+func terminateAfter(ctx context.Context, container testcontainers.Container, cause error) error {
+	if err := container.Terminate(ctx); err != nil {
+		return fmt.Errorf("%v (and could not terminate container: %w)", cause, err)
+	}
+	return cause
+}
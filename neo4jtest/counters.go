@@ -0,0 +1,125 @@
+package neo4jtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Counters sums the summary counters of several queries
+// It mirrors summary.Counters of the driver module, which cannot be imported: that module depends on this one, and
+// the GoGM module uses this one without it
+type Counters struct {
+	NodesCreated         int
+	NodesDeleted         int
+	RelationshipsCreated int
+	RelationshipsDeleted int
+	PropertiesSet        int
+	LabelsAdded          int
+	LabelsRemoved        int
+	IndexesAdded         int
+	IndexesRemoved       int
+	ConstraintsAdded     int
+	ConstraintsRemoved   int
+}
+
+// SummaryCounters are the counters of a query summary, of the 4.x or the 5.x driver, e.g. summary.Counters()
+type SummaryCounters interface {
+	NodesCreated() int
+	NodesDeleted() int
+	RelationshipsCreated() int
+	RelationshipsDeleted() int
+	PropertiesSet() int
+	LabelsAdded() int
+	LabelsRemoved() int
+	IndexesAdded() int
+	IndexesRemoved() int
+	ConstraintsAdded() int
+	ConstraintsRemoved() int
+}
+
+// CountersOf copies the counters of a query summary
+func CountersOf(other SummaryCounters) Counters {
+	var counters Counters
+	counters.Add(other)
+	return counters
+}
+
+// AssertCounters fails the test when the counters differ from the expected ones, listing the differences, and reports
+// whether they are equal. The counters left out are expected to be 0, e.g. for a query creating 2 projects and 4 WORKS_ON
+// relationships:
+//
+//	neo4jtest.AssertCounters(t, neo4jtest.CountersOf(summary.Counters()), neo4jtest.Counters{NodesCreated: 2, LabelsAdded: 2, PropertiesSet: 2, RelationshipsCreated: 4})
+//
+// The counters of a SeedSummary are asserted as they are, e.g. neo4jtest.AssertCounters(t, summaries[0].Counters, ...)
+func AssertCounters(t testing.TB, actual Counters, expected Counters) bool {
+	t.Helper()
+	diff := actual.Diff(expected)
+	if len(diff) > 0 {
+		t.Errorf("Expected %s, got: %s\n\t%s", expected, actual, strings.Join(diff, "\n\t"))
+	}
+	return len(diff) == 0
+}
+
+// Add adds the counters of a query summary
+func (counters *Counters) Add(other SummaryCounters) {
+	counters.NodesCreated += other.NodesCreated()
+	counters.NodesDeleted += other.NodesDeleted()
+	counters.RelationshipsCreated += other.RelationshipsCreated()
+	counters.RelationshipsDeleted += other.RelationshipsDeleted()
+	counters.PropertiesSet += other.PropertiesSet()
+	counters.LabelsAdded += other.LabelsAdded()
+	counters.LabelsRemoved += other.LabelsRemoved()
+	counters.IndexesAdded += other.IndexesAdded()
+	counters.IndexesRemoved += other.IndexesRemoved()
+	counters.ConstraintsAdded += other.ConstraintsAdded()
+	counters.ConstraintsRemoved += other.ConstraintsRemoved()
+}
+
+// String lists the non-zero counters, e.g. "8 nodes created, 6 relationships created"
+func (counters Counters) String() string {
+	var parts []string
+	for _, counter := range counters.list() {
+		if counter.count > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counter.count, counter.name))
+		}
+	}
+	if len(parts) == 0 {
+		return "no updates"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Diff lists the counters that differ from the expected ones, e.g. "relationships created: expected 4, got 3"
+// It is empty when all the counters are equal
+func (counters Counters) Diff(expected Counters) []string {
+	var diff []string
+	actualCounts, expectedCounts := counters.list(), expected.list()
+	for i, counter := range actualCounts {
+		if counter.count != expectedCounts[i].count {
+			diff = append(diff, fmt.Sprintf("%s: expected %d, got %d", counter.name, expectedCounts[i].count, counter.count))
+		}
+	}
+	return diff
+}
+
+type counter struct {
+	count int
+	name  string
+}
+
+func (counters Counters) list() []counter {
+	return []counter{
+		{counters.NodesCreated, "nodes created"},
+		{counters.NodesDeleted, "nodes deleted"},
+		{counters.RelationshipsCreated, "relationships created"},
+		{counters.RelationshipsDeleted, "relationships deleted"},
+		{counters.PropertiesSet, "properties set"},
+		{counters.LabelsAdded, "labels added"},
+		{counters.LabelsRemoved, "labels removed"},
+		{counters.IndexesAdded, "indexes added"},
+		{counters.IndexesRemoved, "indexes removed"},
+		{counters.ConstraintsAdded, "constraints added"},
+		{counters.ConstraintsRemoved, "constraints removed"},
+	}
+}
@@ -0,0 +1,81 @@
+package neo4jtest
+
+import (
+	"context"
+	"fmt"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+
+	neo4j5 "github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestAssertCounters(outer *testing.T) {
+	const query = `MATCH (eric:Person {name: "Eric"}), (nikita:Person {name: "Nikita"})
+CREATE (eric)-[:WORKS_ON]->(:Project {name: "GoGM"})<-[:WORKS_ON]-(nikita)
+CREATE (eric)-[:WORKS_ON]->(:Project {name: "Go Driver"})<-[:WORKS_ON]-(nikita)`
+	ctx := context.Background()
+	server := startFakeServer(outer, fakebolt.Script{
+		query: {Counters: map[string]int{"nodes-created": 2, "labels-added": 2, "properties-set": 2, "relationships-created": 4}},
+	})
+	driver, err := NewDriverWithContext(server, "")
+	if err != nil {
+		outer.Fatalf("Could not create driver: %v", err)
+	}
+	defer driver.Close(ctx)
+	result, err := neo4j5.ExecuteQuery(ctx, driver, query, nil, neo4j5.EagerResultTransformer)
+	if err != nil {
+		outer.Fatalf("Could not run query: %v", err)
+	}
+	counters := result.Summary.Counters()
+
+	outer.Run("passes when the counters are the expected ones", func(t *testing.T) {
+		recorder := &errorRecorder{TB: t}
+
+		equal := AssertCounters(recorder, CountersOf(counters), Counters{NodesCreated: 2, LabelsAdded: 2, PropertiesSet: 2, RelationshipsCreated: 4})
+
+		if !equal || len(recorder.errors) > 0 {
+			t.Errorf("Expected equal counters, got: %v", recorder.errors)
+		}
+	})
+	outer.Run("lists the counters that differ", func(t *testing.T) {
+		recorder := &errorRecorder{TB: t}
+
+		equal := AssertCounters(recorder, CountersOf(counters), Counters{NodesCreated: 2, LabelsAdded: 2, RelationshipsCreated: 3, IndexesAdded: 1})
+
+		expected := "Expected 2 nodes created, 3 relationships created, 2 labels added, 1 indexes added, " +
+			"got: 2 nodes created, 4 relationships created, 2 properties set, 2 labels added\n" +
+			"\trelationships created: expected 3, got 4\n" +
+			"\tproperties set: expected 0, got 2\n" +
+			"\tindexes added: expected 1, got 0"
+		if equal || !reflect.DeepEqual(recorder.errors, []string{expected}) {
+			t.Errorf("Expected the differences, got: %q", recorder.errors)
+		}
+	})
+	outer.Run("asserts the counters of a seed", func(t *testing.T) {
+		recorder := &errorRecorder{TB: t}
+		summary := SeedSummary{File: "small_graph.cypher", DataStatements: 1,
+			Counters: Counters{NodesCreated: 8, LabelsAdded: 8, PropertiesSet: 8, RelationshipsCreated: 6}}
+
+		equal := AssertCounters(recorder, summary.Counters, Counters{NodesCreated: 8, LabelsAdded: 8, PropertiesSet: 8, RelationshipsCreated: 6})
+
+		if !equal || len(recorder.errors) > 0 {
+			t.Errorf("Expected equal counters, got: %v", recorder.errors)
+		}
+	})
+	outer.Run("copies the counters of a summary", func(t *testing.T) {
+		if copied := CountersOf(counters); copied != (Counters{NodesCreated: 2, LabelsAdded: 2, PropertiesSet: 2, RelationshipsCreated: 4}) {
+			t.Errorf("Expected the counters of the summary, got: %v", copied)
+		}
+	})
+}
+
+// errorRecorder records the errors of a test instead of failing it
+type errorRecorder struct {
+	testing.TB
+	errors []string
+}
+
+func (recorder *errorRecorder) Errorf(format string, args ...any) {
+	recorder.errors = append(recorder.errors, fmt.Sprintf(format, args...))
+}
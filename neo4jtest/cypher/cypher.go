@@ -0,0 +1,79 @@
+// Package cypher splits Cypher scripts into statements
+package cypher
+
+import (
+	"regexp"
+	"strings"
+)
+
+var schemaStatement = regexp.MustCompile(`(?i)^(CREATE|DROP)\s+(OR\s+REPLACE\s+)?(\w+\s+)?(INDEX|CONSTRAINT)\b`)
+
+// Split splits a Cypher script on semicolons, ignoring the ones in strings, quoted names and comments
+// Comments are removed and blank statements are skipped
+func Split(script string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		switch char := runes[i]; {
+		case char == '\'' || char == '"' || char == '`':
+			end := closingQuote(runes, i)
+			current.WriteString(string(runes[i:end]))
+			i = end - 1
+		case char == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current.WriteRune('\n')
+		case char == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			// note: the closing */ cannot share the * of the opening /*, e.g. in /*/
+			for i += 3; i < len(runes) && !(runes[i-1] == '*' && runes[i] == '/'); i++ {
+			}
+			current.WriteRune(' ')
+		case char == ';':
+			flush()
+		default:
+			current.WriteRune(char)
+		}
+	}
+	flush()
+	return statements
+}
+
+// closingQuote returns the index following the quote closing the one at start, backslashes escape quotes in strings
+func closingQuote(runes []rune, start int) int {
+	quote := runes[start]
+	for i := start + 1; i < len(runes); i++ {
+		switch {
+		case runes[i] == '\\' && quote != '`':
+			i++
+		case runes[i] == quote:
+			return i + 1
+		}
+	}
+	return len(runes)
+}
+
+// IsSchema reports whether the statement creates or drops an index or a constraint
+func IsSchema(statement string) bool {
+	return schemaStatement.MatchString(statement)
+}
+
+// Partition separates the schema statements, creating or dropping indexes and constraints, from the data statements
+// Neo4j cannot run both kinds of statements in the same transaction
+func Partition(statements []string) (schema []string, data []string) {
+	for _, statement := range statements {
+		if IsSchema(statement) {
+			schema = append(schema, statement)
+		} else {
+			data = append(data, statement)
+		}
+	}
+	return schema, data
+}
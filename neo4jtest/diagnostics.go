@@ -0,0 +1,265 @@
+package neo4jtest
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	"github.com/testcontainers/testcontainers-go"
+)
+
+const maxCapturedOutput = 1 << 20
+const outputTailLines = 100
+const debugLogTailLines = 200
+const debugLogPath = "/logs/debug.log"
+
+// debugLogPrefix marks the lines of debug.log in the captured output
+const debugLogPrefix = "debug.log: "
+
+// Diagnoser is implemented by servers that can describe their state when a test fails
+type Diagnoser interface {
+	Diagnostics(ctx context.Context) string
+}
+
+// LogOnFailure logs the diagnostics of the server when the test fails, if the server is a Diagnoser
+// Isolate, Wipe, Pristine and Cluster already call it
+func LogOnFailure(t testing.TB, server Neo4jServer) {
+	diagnoser, ok := server.(Diagnoser)
+	if !ok {
+		return
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Log(diagnoser.Diagnostics(context.Background()))
+		}
+	})
+}
+
+// Output returns the output of the container captured so far, up to its last megabyte
+// The lines of debug.log are followed along with stdout and stderr, they start with "debug.log: "
+func (c *Neo4jContainer) Output() string {
+	return c.output.String()
+}
+
+// DebugLog returns the last lines of the debug.log file of the container, as captured so far
+// They are captured while the container runs, so they are still available once the container is gone or the file rotated
+func (c *Neo4jContainer) DebugLog() string {
+	_, debugLog := splitDebugLog(c.Output())
+	return lastLines(debugLog, debugLogTailLines)
+}
+
+// Diagnostics describes the server version, edition and configuration, followed by its output and debug.log
+// Each part is best-effort, failures are reported inline
+func (c *Neo4jContainer) Diagnostics(ctx context.Context) string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "=== Neo4j server %s ===\n", c.boltURI)
+	components, err := c.queryRows("CALL dbms.components() YIELD name, versions, edition RETURN name, versions[0], edition")
+	writeSection(&report, "server", components, err)
+	configQuery := "CALL dbms.listConfig() YIELD name, value RETURN name, value ORDER BY name"
+	if c.majorVersion >= 5 {
+		configQuery = "SHOW SETTINGS YIELD name, value RETURN name, value ORDER BY name"
+	}
+	configuration, err := c.queryRows(configQuery)
+	writeSection(&report, "configuration", configuration, err)
+	output, debugLog := splitDebugLog(c.Output())
+	writeSection(&report, fmt.Sprintf("output (last %d lines)", outputTailLines), lastLines(output, outputTailLines), nil)
+	writeSection(&report, fmt.Sprintf("debug.log (last %d lines)", debugLogTailLines), lastLines(debugLog, debugLogTailLines), nil)
+	return report.String()
+}
+
+// queryRows runs the query on this container only, even when it is a cluster member, and formats the records
+func (c *Neo4jContainer) queryRows(query string) (string, error) {
+	uri, err := url.Parse(c.boltURI)
+	if err != nil {
+		return "", err
+	}
+	driver, err := NewDriver(c, strings.Replace(uri.Scheme, "neo4j", "bolt", 1))
+	if err != nil {
+		return "", err
+	}
+	// diagnostics are best-effort, closing errors are not worth reporting
+	defer driver.Close()
+	session := driver.NewSession(neo4j.SessionConfig{AccessMode: neo4j.AccessModeRead})
+	defer session.Close()
+	result, err := session.Run(query, nil)
+	if err != nil {
+		return "", err
+	}
+	records, err := result.Collect()
+	if err != nil {
+		return "", err
+	}
+	var rows strings.Builder
+	for _, record := range records {
+		values := make([]string, len(record.Values))
+		for i, value := range record.Values {
+			values[i] = fmt.Sprint(value)
+		}
+		rows.WriteString(strings.Join(values, " "))
+		rows.WriteString("\n")
+	}
+	return rows.String(), nil
+}
+
+func writeSection(report *strings.Builder, title string, content string, err error) {
+	fmt.Fprintf(report, "--- %s ---\n", title)
+	if err != nil {
+		fmt.Fprintf(report, "could not collect %s: %v\n", title, err)
+		return
+	}
+	report.WriteString(content)
+	if !strings.HasSuffix(content, "\n") {
+		report.WriteString("\n")
+	}
+}
+
+// outputBuffer keeps the last bytes written to it
+type outputBuffer struct {
+	mutex sync.Mutex
+	data  []byte
+}
+
+func (buffer *outputBuffer) Write(p []byte) (int, error) {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+	buffer.data = append(buffer.data, p...)
+	// trimming only when twice the limit is reached avoids copying the buffer on every write
+	if len(buffer.data) > 2*maxCapturedOutput {
+		buffer.data = append([]byte(nil), buffer.data[len(buffer.data)-maxCapturedOutput:]...)
+	}
+	return len(p), nil
+}
+
+func (buffer *outputBuffer) String() string {
+	buffer.mutex.Lock()
+	defer buffer.mutex.Unlock()
+	if len(buffer.data) > maxCapturedOutput {
+		return string(buffer.data[len(buffer.data)-maxCapturedOutput:])
+	}
+	return string(buffer.data)
+}
+
+// followOutput captures the output and the debug.log of the container from its start, until the container is removed
+func followOutput(container testcontainers.Container) *outputBuffer {
+	buffer := &outputBuffer{}
+	go func() {
+		if err := copyOutput(context.Background(), container.GetContainerID(), true, buffer); err != nil {
+			fmt.Fprintf(buffer, "neo4jtest: stopped capturing output: %v\n", err)
+		}
+	}()
+	go func() {
+		debugLog := &prefixedLines{target: buffer, prefix: debugLogPrefix}
+		if err := followDebugLog(context.Background(), container.GetContainerID(), debugLog); err != nil {
+			fmt.Fprintf(buffer, "neo4jtest: stopped capturing %s: %v\n", debugLogPath, err)
+		}
+	}()
+	return buffer
+}
+
+// followDebugLog copies debug.log from its first line, and keeps following it when it is created or rotated
+func followDebugLog(ctx context.Context, containerID string, target io.Writer) error {
+	docker, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+	exec, err := docker.ContainerExecCreate(ctx, containerID, types.ExecConfig{
+		Cmd:          []string{"tail", "-n", "+1", "-F", debugLogPath},
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return err
+	}
+	attached, err := docker.ContainerExecAttach(ctx, exec.ID, types.ExecStartCheck{})
+	if err != nil {
+		return err
+	}
+	defer attached.Close()
+	_, err = stdcopy.StdCopy(target, target, attached.Reader)
+	return err
+}
+
+// prefixedLines writes whole lines only, each one prefixed, so that they are not mixed with the other output
+type prefixedLines struct {
+	target  io.Writer
+	prefix  string
+	pending []byte
+}
+
+func (lines *prefixedLines) Write(p []byte) (int, error) {
+	lines.pending = append(lines.pending, p...)
+	end := bytes.LastIndexByte(lines.pending, '\n')
+	if end < 0 {
+		return len(p), nil
+	}
+	var prefixed []byte
+	for _, line := range bytes.SplitAfter(lines.pending[:end+1], []byte("\n")) {
+		if len(line) > 0 {
+			prefixed = append(append(prefixed, lines.prefix...), line...)
+		}
+	}
+	lines.pending = append([]byte(nil), lines.pending[end+1:]...)
+	if _, err := lines.target.Write(prefixed); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// splitDebugLog separates the lines of debug.log from the other lines of the captured output
+func splitDebugLog(captured string) (output string, debugLog string) {
+	var outputLines, debugLogLines strings.Builder
+	for _, line := range strings.SplitAfter(captured, "\n") {
+		if strings.HasPrefix(line, debugLogPrefix) {
+			debugLogLines.WriteString(strings.TrimPrefix(line, debugLogPrefix))
+		} else {
+			outputLines.WriteString(line)
+		}
+	}
+	return outputLines.String(), debugLogLines.String()
+}
+
+// withOutput adds the last lines of the container output to the error of a container that could not start
+func withOutput(ctx context.Context, container testcontainers.Container, cause error) error {
+	buffer := &outputBuffer{}
+	if err := copyOutput(ctx, container.GetContainerID(), false, buffer); err != nil {
+		return fmt.Errorf("%w (and could not read container output: %v)", cause, err)
+	}
+	return fmt.Errorf("%w\ncontainer output (last %d lines):\n%s", cause, outputTailLines, lastLines(buffer.String(), outputTailLines))
+}
+
+// copyOutput copies the stdout and stderr of the container, the Docker client is configured like Testcontainers'
+func copyOutput(ctx context.Context, containerID string, follow bool, target io.Writer) error {
+	docker, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		return err
+	}
+	defer docker.Close()
+	reader, err := docker.ContainerLogs(ctx, containerID, types.ContainerLogsOptions{ShowStdout: true, ShowStderr: true, Follow: follow})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	_, err = stdcopy.StdCopy(target, target, reader)
+	return err
+}
+
+func lastLines(text string, count int) string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) > count {
+		lines = lines[len(lines)-count:]
+	}
+	return strings.Join(lines, "")
+}
@@ -0,0 +1,105 @@
+package neo4jtest
+
+import (
+	"fmt"
+	"graphconnect/neo4jtest/fakebolt"
+	"strings"
+	"testing"
+)
+
+func TestDiagnostics(outer *testing.T) {
+	outer.Run("logs the diagnostics of failed tests only", func(t *testing.T) {
+		server := startFakeServer(t, fakebolt.Script{"RETURN 42 AS answer": {Keys: []string{"answer"}, Records: [][]any{{42}}}})
+		if _, err := runSingle(server, "RETURN 42 AS answer", nil); err != nil {
+			t.Fatalf("Could not run query: %v", err)
+		}
+		passed, failed := &recordingTB{TB: t}, &recordingTB{TB: t, failed: true}
+
+		LogOnFailure(passed, server)
+		LogOnFailure(failed, server)
+		passed.cleanup()
+		failed.cleanup()
+
+		if len(passed.logs) > 0 {
+			t.Errorf("Expected no log for passed test, got: %v", passed.logs)
+		}
+		if len(failed.logs) != 1 || !strings.Contains(failed.logs[0], "RETURN 42 AS answer") {
+			t.Errorf("Expected received queries to be logged, got: %v", failed.logs)
+		}
+	})
+	outer.Run("keeps the end of the output", func(t *testing.T) {
+		buffer := &outputBuffer{}
+		for i := 0; i < maxCapturedOutput/10+1; i++ {
+			fmt.Fprintf(buffer, "line %05d\n", i)
+		}
+
+		output := buffer.String()
+
+		if len(output) != maxCapturedOutput {
+			t.Errorf("Expected %d bytes, got: %d", maxCapturedOutput, len(output))
+		}
+		if !strings.HasSuffix(output, fmt.Sprintf("line %05d\n", maxCapturedOutput/10)) {
+			t.Errorf("Expected output to end with the last line, got: %q", lastLines(output, 1))
+		}
+	})
+	outer.Run("captures the lines of debug.log along with the output", func(t *testing.T) {
+		buffer := &outputBuffer{}
+		debugLog := &prefixedLines{target: buffer, prefix: debugLogPrefix}
+		fmt.Fprint(buffer, "Starting...\n")
+		fmt.Fprint(debugLog, "2024-01-01 INFO  [o.n.k.i.DatabaseHealth] Database ")
+		fmt.Fprint(buffer, "Bolt enabled on 0.0.0.0:7687.\n")
+		fmt.Fprint(debugLog, "healthy\n2024-01-01 WARN  [o.n.k.i.p.Procedures] Slow\n")
+
+		output, debugLines := splitDebugLog(buffer.String())
+
+		if expected := "Starting...\nBolt enabled on 0.0.0.0:7687.\n"; output != expected {
+			t.Errorf("Expected output %q, got: %q", expected, output)
+		}
+		if expected := "2024-01-01 INFO  [o.n.k.i.DatabaseHealth] Database healthy\n2024-01-01 WARN  [o.n.k.i.p.Procedures] Slow\n"; debugLines != expected {
+			t.Errorf("Expected debug.log %q, got: %q", expected, debugLines)
+		}
+	})
+	outer.Run("tails lines", func(t *testing.T) {
+		for text, expected := range map[string]string{
+			"a\nb\nc\n": "b\nc\n",
+			"a\nb\nc":   "b\nc",
+			"c\n":       "c\n",
+			"":          "",
+		} {
+			if tail := lastLines(text, 2); tail != expected {
+				t.Errorf("Expected %q, got: %q", expected, tail)
+			}
+		}
+	})
+}
+
+// recordingTB records logs and cleanups instead of passing them to the actual test
+type recordingTB struct {
+	testing.TB
+	failed   bool
+	skipped  bool
+	cleanups []func()
+	logs     []string
+}
+
+func (tb *recordingTB) Cleanup(cleanup func()) {
+	tb.cleanups = append(tb.cleanups, cleanup)
+}
+
+func (tb *recordingTB) Failed() bool {
+	return tb.failed
+}
+
+func (tb *recordingTB) Log(args ...any) {
+	tb.logs = append(tb.logs, fmt.Sprint(args...))
+}
+
+func (tb *recordingTB) Skipf(string, ...any) {
+	tb.skipped = true
+}
+
+func (tb *recordingTB) cleanup() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}
@@ -0,0 +1,31 @@
+package neo4jtest
+
+import (
+	"crypto/tls"
+
+	neo4j5 "github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// NewDriverWithContext creates a 5.x driver connected to the server, it is the counterpart of NewDriver
+// The scheme and the certificate authority are handled like NewDriver does
+func NewDriverWithContext(server Neo4jServer, scheme string, configurers ...func(*neo4j5.Config)) (neo4j5.DriverWithContext, error) {
+	uri, rootCAs, err := driverTarget(server, scheme)
+	if err != nil {
+		return nil, err
+	}
+	if rootCAs != nil {
+		configurers = append([]func(*neo4j5.Config){func(config *neo4j5.Config) {
+			config.TlsConfig = &tls.Config{RootCAs: rootCAs}
+		}}, configurers...)
+	}
+	username, password := server.Credentials()
+	return neo4j5.NewDriverWithContext(uri, neo4j5.BasicAuth(username, password, ""), configurers...)
+}
+
+// TrustRootCAsWithContext is the 5.x driver counterpart of TrustRootCAs, for NewDriverWithContext
+func TrustRootCAsWithContext(pemCertificates ...[]byte) func(*neo4j5.Config) {
+	rootCAs := parseRootCAs(pemCertificates)
+	return func(config *neo4j5.Config) {
+		config.TlsConfig = &tls.Config{RootCAs: rootCAs}
+	}
+}
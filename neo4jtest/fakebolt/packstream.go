@@ -0,0 +1,374 @@
+package fakebolt
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+)
+
+// Structure is a PackStream structure the server does not know how to interpret, e.g. temporal parameters
+type Structure struct {
+	Tag    byte
+	Fields []any
+}
+
+type packer struct {
+	buf []byte
+	err error
+}
+
+func (p *packer) pack(value any) {
+	switch v := value.(type) {
+	case nil:
+		p.buf = append(p.buf, 0xC0)
+	case bool:
+		if v {
+			p.buf = append(p.buf, 0xC3)
+		} else {
+			p.buf = append(p.buf, 0xC2)
+		}
+	case int:
+		p.packInt(int64(v))
+	case int8:
+		p.packInt(int64(v))
+	case int16:
+		p.packInt(int64(v))
+	case int32:
+		p.packInt(int64(v))
+	case int64:
+		p.packInt(v)
+	case uint8:
+		p.packInt(int64(v))
+	case uint16:
+		p.packInt(int64(v))
+	case uint32:
+		p.packInt(int64(v))
+	case float32:
+		p.packFloat(float64(v))
+	case float64:
+		p.packFloat(v)
+	case string:
+		p.packHeader(len(v), 0x80, 0xD0, 0xD1, 0xD2)
+		p.buf = append(p.buf, v...)
+	case []byte:
+		p.packHeader(len(v), 0, 0xCC, 0xCD, 0xCE)
+		p.buf = append(p.buf, v...)
+	case []any:
+		p.packHeader(len(v), 0x90, 0xD4, 0xD5, 0xD6)
+		for _, item := range v {
+			p.pack(item)
+		}
+	case map[string]any:
+		p.packMap(v)
+	case Node:
+		p.packStruct(0x4E, v.ID, toAnySlice(v.Labels), v.Props)
+	case *Node:
+		p.pack(*v)
+	case Relationship:
+		p.packStruct(0x52, v.ID, v.StartID, v.EndID, v.Type, v.Props)
+	case *Relationship:
+		p.pack(*v)
+	case Structure:
+		p.packStruct(v.Tag, v.Fields...)
+	default:
+		p.packReflect(value)
+	}
+}
+
+// packReflect handles typed slices and maps, e.g. []string or map[string]int
+func (p *packer) packReflect(value any) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		items := make([]any, rv.Len())
+		for i := range items {
+			items[i] = rv.Index(i).Interface()
+		}
+		p.pack(items)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			p.setErr(fmt.Errorf("cannot pack map with %s keys", rv.Type().Key()))
+			return
+		}
+		entries := make(map[string]any, rv.Len())
+		for _, key := range rv.MapKeys() {
+			entries[key.String()] = rv.MapIndex(key).Interface()
+		}
+		p.pack(entries)
+	default:
+		p.setErr(fmt.Errorf("cannot pack value of type %T", value))
+	}
+}
+
+func (p *packer) packInt(v int64) {
+	switch {
+	case v >= -16 && v <= 127:
+		p.buf = append(p.buf, byte(int8(v)))
+	case v >= math.MinInt8 && v <= math.MaxInt8:
+		p.buf = append(p.buf, 0xC8, byte(int8(v)))
+	case v >= math.MinInt16 && v <= math.MaxInt16:
+		p.buf = append(p.buf, 0xC9)
+		p.buf = appendUint16(p.buf, uint16(int16(v)))
+	case v >= math.MinInt32 && v <= math.MaxInt32:
+		p.buf = append(p.buf, 0xCA)
+		p.buf = appendUint32(p.buf, uint32(int32(v)))
+	default:
+		p.buf = append(p.buf, 0xCB)
+		p.buf = appendUint64(p.buf, uint64(v))
+	}
+}
+
+func (p *packer) packFloat(v float64) {
+	p.buf = append(p.buf, 0xC1)
+	p.buf = appendUint64(p.buf, math.Float64bits(v))
+}
+
+func (p *packer) packMap(entries map[string]any) {
+	p.packHeader(len(entries), 0xA0, 0xD8, 0xD9, 0xDA)
+	// sorted keys keep the wire format deterministic
+	keys := make([]string, 0, len(entries))
+	for key := range entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		p.pack(key)
+		p.pack(entries[key])
+	}
+}
+
+func (p *packer) packStruct(tag byte, fields ...any) {
+	if len(fields) > 0x0F {
+		p.setErr(fmt.Errorf("structure 0x%X has too many fields: %d", tag, len(fields)))
+		return
+	}
+	p.buf = append(p.buf, 0xB0+byte(len(fields)), tag)
+	for _, field := range fields {
+		p.pack(field)
+	}
+}
+
+// packHeader writes the marker of a sized value, tiny is 0 when the type has no tiny representation
+func (p *packer) packHeader(size int, tiny, marker8, marker16, marker32 byte) {
+	switch {
+	case tiny != 0 && size < 0x10:
+		p.buf = append(p.buf, tiny+byte(size))
+	case size <= math.MaxUint8:
+		p.buf = append(p.buf, marker8, byte(size))
+	case size <= math.MaxUint16:
+		p.buf = append(p.buf, marker16)
+		p.buf = appendUint16(p.buf, uint16(size))
+	case size <= math.MaxUint32:
+		p.buf = append(p.buf, marker32)
+		p.buf = appendUint32(p.buf, uint32(size))
+	default:
+		p.setErr(fmt.Errorf("value is too large to pack: %d", size))
+	}
+}
+
+func (p *packer) setErr(err error) {
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+var errTruncated = errors.New("truncated PackStream value")
+
+type unpacker struct {
+	buf []byte
+	pos int
+}
+
+func (u *unpacker) unpack() (any, error) {
+	marker, err := u.read(1)
+	if err != nil {
+		return nil, err
+	}
+	m := marker[0]
+	switch {
+	case m <= 0x7F:
+		return int64(m), nil
+	case m >= 0xF0:
+		return int64(int8(m)), nil
+	case m >= 0x80 && m <= 0x8F:
+		return u.unpackString(int(m & 0x0F))
+	case m >= 0x90 && m <= 0x9F:
+		return u.unpackList(int(m & 0x0F))
+	case m >= 0xA0 && m <= 0xAF:
+		return u.unpackMap(int(m & 0x0F))
+	case m >= 0xB0 && m <= 0xBF:
+		return u.unpackStruct(int(m & 0x0F))
+	}
+	switch m {
+	case 0xC0:
+		return nil, nil
+	case 0xC1:
+		raw, err := u.read(8)
+		if err != nil {
+			return nil, err
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(raw)), nil
+	case 0xC2:
+		return false, nil
+	case 0xC3:
+		return true, nil
+	case 0xC8, 0xC9, 0xCA, 0xCB:
+		return u.unpackInt(m)
+	case 0xCC, 0xCD, 0xCE:
+		size, err := u.unpackSize(m - 0xCC)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := u.read(size)
+		if err != nil {
+			return nil, err
+		}
+		return append([]byte(nil), raw...), nil
+	case 0xD0, 0xD1, 0xD2:
+		size, err := u.unpackSize(m - 0xD0)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackString(size)
+	case 0xD4, 0xD5, 0xD6:
+		size, err := u.unpackSize(m - 0xD4)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackList(size)
+	case 0xD8, 0xD9, 0xDA:
+		size, err := u.unpackSize(m - 0xD8)
+		if err != nil {
+			return nil, err
+		}
+		return u.unpackMap(size)
+	}
+	return nil, fmt.Errorf("unknown PackStream marker 0x%X", m)
+}
+
+func (u *unpacker) unpackInt(marker byte) (int64, error) {
+	switch marker {
+	case 0xC8:
+		raw, err := u.read(1)
+		if err != nil {
+			return 0, err
+		}
+		return int64(int8(raw[0])), nil
+	case 0xC9:
+		raw, err := u.read(2)
+		if err != nil {
+			return 0, err
+		}
+		return int64(int16(binary.BigEndian.Uint16(raw))), nil
+	case 0xCA:
+		raw, err := u.read(4)
+		if err != nil {
+			return 0, err
+		}
+		return int64(int32(binary.BigEndian.Uint32(raw))), nil
+	default:
+		raw, err := u.read(8)
+		if err != nil {
+			return 0, err
+		}
+		return int64(binary.BigEndian.Uint64(raw)), nil
+	}
+}
+
+// unpackSize reads a 8, 16 or 32-bit unsigned size, depending on the width index (0, 1 or 2)
+func (u *unpacker) unpackSize(width byte) (int, error) {
+	raw, err := u.read(1 << width)
+	if err != nil {
+		return 0, err
+	}
+	switch width {
+	case 0:
+		return int(raw[0]), nil
+	case 1:
+		return int(binary.BigEndian.Uint16(raw)), nil
+	default:
+		return int(binary.BigEndian.Uint32(raw)), nil
+	}
+}
+
+func (u *unpacker) unpackString(size int) (string, error) {
+	raw, err := u.read(size)
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+func (u *unpacker) unpackList(size int) ([]any, error) {
+	items := make([]any, size)
+	for i := range items {
+		item, err := u.unpack()
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+func (u *unpacker) unpackMap(size int) (map[string]any, error) {
+	entries := make(map[string]any, size)
+	for i := 0; i < size; i++ {
+		rawKey, err := u.unpack()
+		if err != nil {
+			return nil, err
+		}
+		key, ok := rawKey.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected string map key, got %T", rawKey)
+		}
+		if entries[key], err = u.unpack(); err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
+func (u *unpacker) unpackStruct(size int) (Structure, error) {
+	tag, err := u.read(1)
+	if err != nil {
+		return Structure{}, err
+	}
+	fields, err := u.unpackList(size)
+	if err != nil {
+		return Structure{}, err
+	}
+	return Structure{Tag: tag[0], Fields: fields}, nil
+}
+
+func (u *unpacker) read(n int) ([]byte, error) {
+	if u.pos+n > len(u.buf) {
+		return nil, errTruncated
+	}
+	raw := u.buf[u.pos : u.pos+n]
+	u.pos += n
+	return raw, nil
+}
+
+func toAnySlice(values []string) []any {
+	result := make([]any, len(values))
+	for i, value := range values {
+		result[i] = value
+	}
+	return result
+}
+
+func appendUint16(buf []byte, v uint16) []byte {
+	return append(buf, byte(v>>8), byte(v))
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	return appendUint32(appendUint32(buf, uint32(v>>32)), uint32(v))
+}
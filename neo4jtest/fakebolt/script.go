@@ -0,0 +1,123 @@
+package fakebolt
+
+import "strings"
+
+// Script maps the text of each query the server accepts to the response it sends back
+// Queries are matched after collapsing whitespace, so multi-line queries can be scripted as-is
+type Script map[string]Response
+
+// Response is what the server answers to a single query
+type Response struct {
+	// Keys lists the returned columns
+	Keys []string
+	// Records holds the rows, one value per key
+	Records [][]any
+	// Counters are reported in the result summary, e.g. "nodes-created" or "indexes-added"
+	Counters map[string]int
+	// Failure, when set, makes the server reject the query
+	Failure *Failure
+	// Plan, when set, is reported in the result summary: as the profile of PROFILE queries, as the plan of the others
+	Plan *Plan
+}
+
+// Failure is a Neo4j error, as sent by the server
+type Failure struct {
+	Code    string
+	Message string
+}
+
+// Plan is an operator of an execution plan, and the operators it gets its rows from
+type Plan struct {
+	// Operator is the operator type, e.g. "NodeIndexSeek@neo4j"
+	Operator    string
+	Identifiers []string
+	// Arguments hold the details of the operator, e.g. "EstimatedRows" or "Details"
+	Arguments map[string]any
+	// DbHits and Rows are only reported for PROFILE queries
+	DbHits   int64
+	Rows     int64
+	Children []Plan
+}
+
+// Node is the Bolt 4 representation of a node
+type Node struct {
+	ID     int64
+	Labels []string
+	Props  map[string]any
+}
+
+// Relationship is the Bolt 4 representation of a relationship
+type Relationship struct {
+	ID      int64
+	StartID int64
+	EndID   int64
+	Type    string
+	Props   map[string]any
+}
+
+// builtins are answered even when not scripted
+var builtins = Script{
+	// sent by the driver's VerifyConnectivity
+	"RETURN 1 AS n": {Keys: []string{"n"}, Records: [][]any{{1}}},
+}
+
+func (script Script) lookup(query string) (Response, bool) {
+	normalized := normalizeQuery(query)
+	for _, candidates := range []Script{script, builtins} {
+		for scriptedQuery, response := range candidates {
+			if normalizeQuery(scriptedQuery) == normalized {
+				return response, true
+			}
+		}
+	}
+	return Response{}, false
+}
+
+func normalizeQuery(query string) string {
+	return strings.Join(strings.Fields(query), " ")
+}
+
+// statementType tells the driver what kind of query ran: read, write or schema write
+func (response Response) statementType() string {
+	statementType := "r"
+	for counter, value := range response.Counters {
+		if value == 0 {
+			continue
+		}
+		if strings.HasPrefix(counter, "indexes-") || strings.HasPrefix(counter, "constraints-") {
+			return "s"
+		}
+		statementType = "w"
+	}
+	return statementType
+}
+
+func (response Response) stats() map[string]any {
+	stats := make(map[string]any, len(response.Counters))
+	for counter, value := range response.Counters {
+		stats[counter] = value
+	}
+	return stats
+}
+
+func (plan Plan) metadata(profiled bool) map[string]any {
+	children := make([]any, len(plan.Children))
+	for i, child := range plan.Children {
+		children[i] = child.metadata(profiled)
+	}
+	arguments := plan.Arguments
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	metadata := map[string]any{
+		"operatorType": plan.Operator,
+		"identifiers":  plan.Identifiers,
+		"args":         arguments,
+		"children":     children,
+	}
+	if profiled {
+		metadata["dbHits"] = plan.DbHits
+		metadata["rows"] = plan.Rows
+	}
+	return metadata
+}
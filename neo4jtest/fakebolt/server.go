@@ -0,0 +1,507 @@
+// Package fakebolt provides an in-process server speaking enough Bolt 4.4 for the Neo4j Go driver
+// It answers queries from a Script instead of running them, so tests can run without Docker
+package fakebolt
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// ServerAgent is the agent the fake server reports, i.e. the Neo4j version it pretends to be
+const ServerAgent = "Neo4j/4.4.0"
+
+const defaultDatabase = "neo4j"
+
+// Bolt message tags
+const (
+	msgHello    byte = 0x01
+	msgGoodbye  byte = 0x02
+	msgReset    byte = 0x0F
+	msgRun      byte = 0x10
+	msgBegin    byte = 0x11
+	msgCommit   byte = 0x12
+	msgRollback byte = 0x13
+	msgDiscard  byte = 0x2F
+	msgPull     byte = 0x3F
+	msgRoute    byte = 0x66
+	msgSuccess  byte = 0x70
+	msgRecord   byte = 0x71
+	msgIgnored  byte = 0x7E
+	msgFailure  byte = 0x7F
+)
+
+// Server is a fake Neo4j server listening on a local TCP port
+type Server struct {
+	script   Script
+	username string
+	password string
+	tls      *tls.Config
+	listener net.Listener
+
+	mutex       sync.Mutex
+	connections map[net.Conn]struct{}
+	received    []Query
+	bookmarks   int
+	closed      bool
+	group       sync.WaitGroup
+}
+
+// Query is a query received by the server
+type Query struct {
+	Text   string
+	Params map[string]any
+}
+
+// Option customizes the Server
+type Option func(*Server)
+
+// WithAuth makes the server reject connections that do not present these credentials
+func WithAuth(username, password string) Option {
+	return func(server *Server) {
+		server.username = username
+		server.password = password
+	}
+}
+
+// WithTLS makes the server only accept encrypted connections, BoltURI then uses the neo4j+s scheme
+func WithTLS(config *tls.Config) Option {
+	return func(server *Server) {
+		server.tls = config
+	}
+}
+
+// Start listens on a random local port and serves the given script until Close is called
+func Start(script Script, options ...Option) (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("could not listen: %w", err)
+	}
+	server := &Server{
+		script:      script,
+		listener:    listener,
+		connections: map[net.Conn]struct{}{},
+	}
+	for _, option := range options {
+		option(server)
+	}
+	if server.tls != nil {
+		server.listener = tls.NewListener(listener, server.tls)
+	}
+	server.group.Add(1)
+	go server.accept()
+	return server, nil
+}
+
+// Address returns the host:port the server listens on
+func (s *Server) Address() string {
+	return s.listener.Addr().String()
+}
+
+// BoltURI returns the routing URI (neo4j://, or neo4j+s:// WithTLS) the driver should connect to
+func (s *Server) BoltURI() string {
+	if s.tls != nil {
+		return fmt.Sprintf("neo4j+s://%s", s.Address())
+	}
+	return fmt.Sprintf("neo4j://%s", s.Address())
+}
+
+// HTTPURI is empty, the fake server only speaks Bolt
+func (s *Server) HTTPURI() string {
+	return ""
+}
+
+// AuthToken returns the token matching the configured credentials, if any
+func (s *Server) AuthToken() neo4j.AuthToken {
+	if s.username == "" {
+		return neo4j.NoAuth()
+	}
+	return neo4j.BasicAuth(s.username, s.password, "")
+}
+
+// Credentials returns the credentials set with WithAuth, if any
+func (s *Server) Credentials() (string, string) {
+	return s.username, s.password
+}
+
+// Received returns the queries run so far, in order
+func (s *Server) Received() []Query {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]Query(nil), s.received...)
+}
+
+// Diagnostics lists the queries received so far, to spot the ones the script does not answer as expected
+func (s *Server) Diagnostics(context.Context) string {
+	var report strings.Builder
+	fmt.Fprintf(&report, "=== fake Neo4j server %s ===\n", s.BoltURI())
+	for _, query := range s.Received() {
+		fmt.Fprintf(&report, "%s %v\n", query.Text, query.Params)
+	}
+	return report.String()
+}
+
+// Terminate closes the server, it exists so the server can be used in place of a container
+func (s *Server) Terminate(context.Context) error {
+	return s.Close()
+}
+
+// Close stops listening, closes open connections and waits for them to be released
+func (s *Server) Close() error {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil
+	}
+	s.closed = true
+	err := s.listener.Close()
+	for conn := range s.connections {
+		_ = conn.Close()
+	}
+	s.mutex.Unlock()
+	s.group.Wait()
+	return err
+}
+
+func (s *Server) accept() {
+	defer s.group.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		if s.closed {
+			s.mutex.Unlock()
+			_ = conn.Close()
+			return
+		}
+		s.connections[conn] = struct{}{}
+		s.group.Add(1)
+		s.mutex.Unlock()
+		go s.serve(conn)
+	}
+}
+
+func (s *Server) serve(conn net.Conn) {
+	defer s.group.Done()
+	defer func() {
+		s.mutex.Lock()
+		delete(s.connections, conn)
+		s.mutex.Unlock()
+		_ = conn.Close()
+	}()
+	if err := handshake(conn); err != nil {
+		return
+	}
+	client := &connection{server: s, conn: conn, streams: map[int64]*stream{}}
+	for {
+		tag, fields, err := client.readMessage()
+		if err != nil {
+			return
+		}
+		if err := client.handle(tag, fields); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) record(query Query) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.received = append(s.received, query)
+}
+
+func (s *Server) nextBookmark() string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.bookmarks++
+	return fmt.Sprintf("fakebolt:%d", s.bookmarks)
+}
+
+var errGoodbye = errors.New("client said goodbye")
+
+var boltMagic = []byte{0x60, 0x60, 0xB0, 0x17}
+
+// handshake accepts the client if one of its proposed version ranges includes Bolt 4.4
+func handshake(conn net.Conn) error {
+	request := make([]byte, 20)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return err
+	}
+	if !bytes.Equal(request[:4], boltMagic) {
+		return fmt.Errorf("unexpected magic preamble %X", request[:4])
+	}
+	for i := 4; i < len(request); i += 4 {
+		back, minor, major := request[i+1], request[i+2], request[i+3]
+		if major == 4 && minor >= 4 && minor-back <= 4 {
+			_, err := conn.Write([]byte{0, 0, 4, 4})
+			return err
+		}
+	}
+	_, _ = conn.Write([]byte{0, 0, 0, 0})
+	return errors.New("no supported protocol version proposed")
+}
+
+type stream struct {
+	query    string
+	response Response
+	pending  [][]any
+}
+
+// connection holds the state of a single Bolt connection
+type connection struct {
+	server  *Server
+	conn    net.Conn
+	failed  bool
+	inTx    bool
+	streams map[int64]*stream
+	lastQid int64
+}
+
+func (c *connection) handle(tag byte, fields []any) error {
+	if c.failed && tag != msgReset && tag != msgGoodbye {
+		return c.writeMessage(msgIgnored)
+	}
+	switch tag {
+	case msgHello:
+		return c.hello(mapField(fields, 0))
+	case msgGoodbye:
+		return errGoodbye
+	case msgReset:
+		c.failed = false
+		c.inTx = false
+		c.streams = map[int64]*stream{}
+		return c.success(map[string]any{})
+	case msgRoute:
+		return c.route()
+	case msgBegin:
+		c.inTx = true
+		c.streams = map[int64]*stream{}
+		return c.success(map[string]any{})
+	case msgRun:
+		query, _ := field(fields, 0).(string)
+		return c.run(query, mapField(fields, 1))
+	case msgPull:
+		return c.pull(mapField(fields, 0))
+	case msgDiscard:
+		return c.discard(mapField(fields, 0))
+	case msgCommit:
+		c.inTx = false
+		return c.success(map[string]any{"bookmark": c.server.nextBookmark()})
+	case msgRollback:
+		c.inTx = false
+		return c.success(map[string]any{})
+	}
+	return c.failure(Failure{
+		Code:    "Neo.ClientError.Request.Invalid",
+		Message: fmt.Sprintf("fakebolt does not support message 0x%X", tag),
+	})
+}
+
+func (c *connection) hello(extra map[string]any) error {
+	if c.server.username != "" &&
+		(extra["principal"] != c.server.username || extra["credentials"] != c.server.password) {
+		if err := c.failure(Failure{
+			Code:    "Neo.ClientError.Security.Unauthorized",
+			Message: "The client is unauthorized due to authentication failure.",
+		}); err != nil {
+			return err
+		}
+		return errors.New("authentication failure")
+	}
+	return c.success(map[string]any{
+		"server":        ServerAgent,
+		"connection_id": fmt.Sprintf("fakebolt-%p", c),
+	})
+}
+
+// route advertises this server as the only router, reader and writer
+func (c *connection) route() error {
+	addresses := []any{c.server.Address()}
+	return c.success(map[string]any{
+		"rt": map[string]any{
+			"ttl": 300,
+			"db":  defaultDatabase,
+			"servers": []any{
+				map[string]any{"role": "ROUTE", "addresses": addresses},
+				map[string]any{"role": "READ", "addresses": addresses},
+				map[string]any{"role": "WRITE", "addresses": addresses},
+			},
+		},
+	})
+}
+
+func (c *connection) run(query string, params map[string]any) error {
+	c.server.record(Query{Text: query, Params: params})
+	response, found := c.server.script.lookup(query)
+	if !found {
+		return c.failure(Failure{
+			Code:    "Neo.ClientError.Statement.SyntaxError",
+			Message: fmt.Sprintf("fakebolt: query is not scripted: %s", normalizeQuery(query)),
+		})
+	}
+	if response.Failure != nil {
+		return c.failure(*response.Failure)
+	}
+	if !c.inTx {
+		c.streams = map[int64]*stream{}
+	}
+	c.lastQid++
+	c.streams[c.lastQid] = &stream{query: query, response: response, pending: response.Records}
+	keys := make([]any, len(response.Keys))
+	for i, key := range response.Keys {
+		keys[i] = key
+	}
+	metadata := map[string]any{"fields": keys, "t_first": 0}
+	if c.inTx {
+		metadata["qid"] = c.lastQid
+	}
+	return c.success(metadata)
+}
+
+func (c *connection) pull(extra map[string]any) error {
+	qid, current := c.currentStream(extra)
+	if current == nil {
+		return c.success(map[string]any{"has_more": false})
+	}
+	n := len(current.pending)
+	if requested, ok := extra["n"].(int64); ok && requested >= 0 && int(requested) < n {
+		n = int(requested)
+	}
+	for _, values := range current.pending[:n] {
+		if err := c.writeMessage(msgRecord, values); err != nil {
+			return err
+		}
+	}
+	current.pending = current.pending[n:]
+	if len(current.pending) > 0 {
+		return c.success(map[string]any{"has_more": true})
+	}
+	delete(c.streams, qid)
+	return c.success(c.summary(current))
+}
+
+func (c *connection) discard(extra map[string]any) error {
+	qid, current := c.currentStream(extra)
+	if current == nil {
+		return c.success(map[string]any{"has_more": false})
+	}
+	delete(c.streams, qid)
+	return c.success(c.summary(current))
+}
+
+func (c *connection) currentStream(extra map[string]any) (int64, *stream) {
+	qid := c.lastQid
+	if requested, ok := extra["qid"].(int64); ok && requested >= 0 {
+		qid = requested
+	}
+	return qid, c.streams[qid]
+}
+
+func (c *connection) summary(current *stream) map[string]any {
+	response := current.response
+	metadata := map[string]any{
+		"type":   response.statementType(),
+		"db":     defaultDatabase,
+		"t_last": 0,
+	}
+	if len(response.Counters) > 0 {
+		metadata["stats"] = response.stats()
+	}
+	if response.Plan != nil {
+		if profiled := strings.HasPrefix(strings.ToUpper(normalizeQuery(current.query)), "PROFILE "); profiled {
+			metadata["profile"] = response.Plan.metadata(true)
+		} else {
+			metadata["plan"] = response.Plan.metadata(false)
+		}
+	}
+	if !c.inTx {
+		metadata["bookmark"] = c.server.nextBookmark()
+	}
+	return metadata
+}
+
+func (c *connection) success(metadata map[string]any) error {
+	return c.writeMessage(msgSuccess, metadata)
+}
+
+func (c *connection) failure(failure Failure) error {
+	c.failed = true
+	return c.writeMessage(msgFailure, map[string]any{"code": failure.Code, "message": failure.Message})
+}
+
+// readMessage reads chunks until the end-of-message marker and decodes the resulting structure
+func (c *connection) readMessage() (byte, []any, error) {
+	var message []byte
+	header := make([]byte, 2)
+	for {
+		if _, err := io.ReadFull(c.conn, header); err != nil {
+			return 0, nil, err
+		}
+		size := int(header[0])<<8 | int(header[1])
+		if size == 0 {
+			if len(message) == 0 { // NOOP chunk
+				continue
+			}
+			break
+		}
+		chunk := make([]byte, size)
+		if _, err := io.ReadFull(c.conn, chunk); err != nil {
+			return 0, nil, err
+		}
+		message = append(message, chunk...)
+	}
+	decoder := &unpacker{buf: message}
+	value, err := decoder.unpack()
+	if err != nil {
+		return 0, nil, err
+	}
+	structure, ok := value.(Structure)
+	if !ok {
+		return 0, nil, fmt.Errorf("expected message structure, got %T", value)
+	}
+	return structure.Tag, structure.Fields, nil
+}
+
+// writeMessage encodes the message structure and sends it in chunks of at most 65535 bytes
+func (c *connection) writeMessage(tag byte, fields ...any) error {
+	encoder := &packer{}
+	encoder.packStruct(tag, fields...)
+	if encoder.err != nil {
+		return encoder.err
+	}
+	var out []byte
+	for message := encoder.buf; len(message) > 0; {
+		size := len(message)
+		if size > 0xFFFF {
+			size = 0xFFFF
+		}
+		out = append(out, byte(size>>8), byte(size))
+		out = append(out, message[:size]...)
+		message = message[size:]
+	}
+	out = append(out, 0, 0)
+	_, err := c.conn.Write(out)
+	return err
+}
+
+func field(fields []any, index int) any {
+	if index >= len(fields) {
+		return nil
+	}
+	return fields[index]
+}
+
+func mapField(fields []any, index int) map[string]any {
+	entries, _ := field(fields, index).(map[string]any)
+	return entries
+}
@@ -0,0 +1,285 @@
+package fakebolt_test
+
+import (
+	"errors"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+const username = "neo4j"
+const password = "s3cr3t"
+
+func TestFakeBoltServer(outer *testing.T) {
+	server, err := fakebolt.Start(fakebolt.Script{
+		"RETURN REDUCE(sum=0, power IN $powersOfTwo | sum+power) AS answer": {
+			Keys:    []string{"answer"},
+			Records: [][]any{{42}},
+		},
+		"CALL { RETURN 42 AS answer } IN TRANSACTIONS RETURN answer": {
+			Keys:    []string{"answer"},
+			Records: [][]any{{42}},
+		},
+		"MATCH (p:Person) RETURN p ORDER BY p.name ASC": {
+			Keys: []string{"p"},
+			Records: [][]any{
+				{fakebolt.Node{ID: 1, Labels: []string{"Person"}, Props: map[string]any{"name": "Eric"}}},
+				{fakebolt.Node{ID: 2, Labels: []string{"Person"}, Props: map[string]any{"name": "Florent"}}},
+				{fakebolt.Node{ID: 3, Labels: []string{"Person"}, Props: map[string]any{"name": "Nikita"}}},
+			},
+		},
+		"CREATE INDEX FOR (p:Person) ON (p.name)": {
+			Counters: map[string]int{"indexes-added": 1},
+		},
+		`MERGE (eric:Person {name: "Eric"})
+MERGE (gogm:Project {name: "GoGM"})
+MERGE (eric)-[:WORKS_ON]->(gogm)`: {
+			Counters: map[string]int{"nodes-created": 2, "relationships-created": 1, "properties-set": 2, "labels-added": 2},
+		},
+		"PROFILE MATCH (p:Person {name: $name}) RETURN p": {
+			Keys:    []string{"p"},
+			Records: [][]any{{fakebolt.Node{ID: 1, Labels: []string{"Person"}, Props: map[string]any{"name": "Eric"}}}},
+			Plan: &fakebolt.Plan{
+				Operator: "ProduceResults@neo4j", Identifiers: []string{"p"}, DbHits: 0, Rows: 1,
+				Children: []fakebolt.Plan{
+					{Operator: "NodeIndexSeek@neo4j", Identifiers: []string{"p"}, DbHits: 2, Rows: 1},
+				},
+			},
+		},
+		"RETURN 1/0": {
+			Failure: &fakebolt.Failure{Code: "Neo.ClientError.Statement.ArithmeticError", Message: "/ by zero"},
+		},
+	}, fakebolt.WithAuth(username, password))
+	if err != nil {
+		outer.Fatalf("Could not start fake server: %v", err)
+	}
+	defer func() {
+		if err := server.Close(); err != nil {
+			outer.Errorf("Could not close fake server: %v", err)
+		}
+	}()
+	driver, err := neo4j.NewDriver(server.BoltURI(), server.AuthToken())
+	if err != nil {
+		outer.Fatalf("Could not create driver: %v", err)
+	}
+	defer func() {
+		if err := driver.Close(); err != nil {
+			outer.Errorf("Could not close driver: %v", err)
+		}
+	}()
+
+	outer.Run("verifies connectivity", func(t *testing.T) {
+		if err := driver.VerifyConnectivity(); err != nil {
+			t.Errorf("Expected driver to connect, but did not: %v", err)
+		}
+	})
+	outer.Run("answers read transactions and records parameters", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer closeSession(t, session)
+
+		answer, err := session.ReadTransaction(func(tx neo4j.Transaction) (any, error) {
+			result, err := tx.Run(
+				"RETURN REDUCE(sum=0, power IN $powersOfTwo | sum+power) AS answer",
+				map[string]any{"powersOfTwo": []int{2, 8, 32}})
+			if err != nil {
+				return nil, err
+			}
+			record, err := result.Single()
+			if err != nil {
+				return nil, err
+			}
+			answer, _ := record.Get("answer")
+			return answer, nil
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if answer != int64(42) {
+			t.Errorf("Expected 42, got: %v", answer)
+		}
+		received := server.Received()
+		params := received[len(received)-1].Params
+		expected := []any{int64(2), int64(8), int64(32)}
+		if !reflect.DeepEqual(params["powersOfTwo"], expected) {
+			t.Errorf("Expected parameters %v, got: %v", expected, params["powersOfTwo"])
+		}
+	})
+	outer.Run("answers auto-commit queries", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer closeSession(t, session)
+
+		result, err := session.Run("CALL { RETURN 42 AS answer } IN TRANSACTIONS RETURN answer", nil)
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		record, err := result.Single()
+		if err != nil {
+			t.Fatalf("Expected single record, got: %v", err)
+		}
+		if answer := record.Values[0]; answer != int64(42) {
+			t.Errorf("Expected 42, got: %v", answer)
+		}
+	})
+	outer.Run("streams nodes in batches", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{FetchSize: 2})
+		defer closeSession(t, session)
+
+		names, err := session.ReadTransaction(func(tx neo4j.Transaction) (any, error) {
+			result, err := tx.Run("MATCH (p:Person) RETURN p ORDER BY p.name ASC", nil)
+			if err != nil {
+				return nil, err
+			}
+			var names []string
+			for result.Next() {
+				person := result.Record().Values[0].(neo4j.Node)
+				names = append(names, person.Props["name"].(string))
+			}
+			return names, result.Err()
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := []string{"Eric", "Florent", "Nikita"}
+		if !reflect.DeepEqual(names, expected) {
+			t.Errorf("Expected %v, got: %v", expected, names)
+		}
+	})
+	outer.Run("reports write counters", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer closeSession(t, session)
+
+		summary, err := session.WriteTransaction(func(tx neo4j.Transaction) (any, error) {
+			result, err := tx.Run(`
+MERGE (eric:Person {name: "Eric"})
+MERGE (gogm:Project {name: "GoGM"})
+MERGE (eric)-[:WORKS_ON]->(gogm)
+`, nil)
+			if err != nil {
+				return nil, err
+			}
+			return result.Consume()
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		counters := summary.(neo4j.ResultSummary).Counters()
+		if counters.NodesCreated() != 2 || counters.RelationshipsCreated() != 1 {
+			t.Errorf("Expected 2 nodes and 1 relationship created, got: %d and %d",
+				counters.NodesCreated(), counters.RelationshipsCreated())
+		}
+		if queryType := summary.(neo4j.ResultSummary).StatementType(); queryType != neo4j.StatementTypeWriteOnly {
+			t.Errorf("Expected write statement type, got: %v", queryType)
+		}
+	})
+	outer.Run("reports schema counters", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer closeSession(t, session)
+
+		summary, err := session.WriteTransaction(func(tx neo4j.Transaction) (any, error) {
+			result, err := tx.Run("CREATE INDEX FOR (p:Person) ON (p.name)", nil)
+			if err != nil {
+				return nil, err
+			}
+			return result.Consume()
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if added := summary.(neo4j.ResultSummary).Counters().IndexesAdded(); added != 1 {
+			t.Errorf("Expected 1 index added, got: %d", added)
+		}
+	})
+	outer.Run("reports profiled plans", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer closeSession(t, session)
+
+		summary, err := session.ReadTransaction(func(tx neo4j.Transaction) (any, error) {
+			result, err := tx.Run("PROFILE MATCH (p:Person {name: $name}) RETURN p", map[string]any{"name": "Eric"})
+			if err != nil {
+				return nil, err
+			}
+			return result.Consume()
+		})
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		profile := summary.(neo4j.ResultSummary).Profile()
+		if profile == nil || profile.Operator() != "ProduceResults@neo4j" || len(profile.Children()) != 1 {
+			t.Fatalf("Expected the scripted profile, got: %v", profile)
+		}
+		if seek := profile.Children()[0]; seek.Operator() != "NodeIndexSeek@neo4j" || seek.DbHits() != 2 || seek.Records() != 1 {
+			t.Errorf("Expected the index seek with 2 db hits and 1 row, got: %v", seek)
+		}
+		if plan := summary.(neo4j.ResultSummary).Plan(); plan != nil {
+			t.Errorf("Expected no plan, got: %v", plan)
+		}
+	})
+	outer.Run("fails scripted failures and recovers", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer closeSession(t, session)
+
+		_, err := session.ReadTransaction(func(tx neo4j.Transaction) (any, error) {
+			result, err := tx.Run("RETURN 1/0", nil)
+			if err != nil {
+				return nil, err
+			}
+			return result.Consume()
+		})
+
+		var neo4jErr *neo4j.Neo4jError
+		if !errors.As(err, &neo4jErr) || neo4jErr.Code != "Neo.ClientError.Statement.ArithmeticError" {
+			t.Errorf("Expected arithmetic error, got: %v", err)
+		}
+		result, err := session.Run("CALL { RETURN 42 AS answer } IN TRANSACTIONS RETURN answer", nil)
+		if err != nil {
+			t.Fatalf("Expected session to recover, got: %v", err)
+		}
+		if _, err := result.Single(); err != nil {
+			t.Errorf("Expected single record after recovery, got: %v", err)
+		}
+	})
+	outer.Run("rejects queries that are not scripted", func(t *testing.T) {
+		session := driver.NewSession(neo4j.SessionConfig{})
+		defer closeSession(t, session)
+
+		result, err := session.Run("MATCH (n) RETURN n", nil)
+		if err == nil {
+			_, err = result.Consume()
+		}
+
+		var neo4jErr *neo4j.Neo4jError
+		if !errors.As(err, &neo4jErr) || neo4jErr.Code != "Neo.ClientError.Statement.SyntaxError" {
+			t.Errorf("Expected syntax error, got: %v", err)
+		}
+	})
+	outer.Run("rejects invalid credentials", func(t *testing.T) {
+		driver, err := neo4j.NewDriver(server.BoltURI(), neo4j.BasicAuth(username, "wrong", ""))
+		if err != nil {
+			t.Fatalf("Could not create driver: %v", err)
+		}
+		defer func() {
+			if err := driver.Close(); err != nil {
+				t.Errorf("Could not close driver: %v", err)
+			}
+		}()
+
+		err = driver.VerifyConnectivity()
+
+		if !neo4j.IsNeo4jError(err) || err.(*neo4j.Neo4jError).Code != "Neo.ClientError.Security.Unauthorized" {
+			t.Errorf("Expected authentication failure, got: %v", err)
+		}
+	})
+}
+
+func closeSession(t *testing.T, session neo4j.Session) {
+	if err := session.Close(); err != nil {
+		t.Errorf("Could not close session: %v", err)
+	}
+}
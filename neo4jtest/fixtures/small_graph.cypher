@@ -0,0 +1,17 @@
+// The small graph of persons working on projects related to topics, shared by the workshop modules
+// Each module creates the indexes itself, e.g. with the migrations of graph in the driver module, or GoGM
+
+MERGE (neo4j:Topic {name: "Neo4j"})
+MERGE (goDriver:Project {name: "Go Driver"})
+MERGE (gogm:Project {name: "GoGM"})
+MERGE (album:MusicProject {name: "TBD"})
+MERGE (eric:Person {name: "Eric"})
+MERGE (nikita:Person {name: "Nikita"})
+MERGE (florent:Person {name: "Florent"})
+MERGE (john:Person {name: "John"})
+MERGE (gogm)-[:RELATES_TO]->(neo4j)
+MERGE (eric)-[:WORKS_ON]->(gogm)
+MERGE (nikita)-[:WORKS_ON]->(gogm)
+MERGE (goDriver)-[:RELATES_TO]->(neo4j)
+MERGE (florent)-[:WORKS_ON]->(goDriver)
+MERGE (john)-[:WORKS_ON]->(album);
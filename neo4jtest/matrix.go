@@ -0,0 +1,198 @@
+package neo4jtest
+
+import (
+	"crypto/x509"
+	"fmt"
+	"graphconnect/neo4jtest/fakebolt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// VersionsEnvVar overrides the versions Matrix runs against, as a comma-separated list, e.g. "4.4,5"
+const VersionsEnvVar = "NEO4J_TEST_VERSIONS"
+
+// Version is a Neo4j server version, e.g. 4.4.12
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion parses versions such as "5", "4.4", "4.4.12", "4.4-enterprise" or the "Neo4j/4.4.12" server agent
+func ParseVersion(version string) (Version, error) {
+	text := strings.TrimPrefix(version, "Neo4j/")
+	if end := strings.IndexFunc(text, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); end >= 0 {
+		text = text[:end]
+	}
+	var numbers [3]int
+	parts := strings.Split(text, ".")
+	if len(parts) > len(numbers) {
+		return Version{}, fmt.Errorf("invalid Neo4j version %q", version)
+	}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid Neo4j version %q", version)
+		}
+		numbers[i] = number
+	}
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+func (version Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch)
+}
+
+// Compare returns -1, 0 or 1 when the version is respectively before, the same as or after the other one
+func (version Version) Compare(other Version) int {
+	for _, difference := range []int{version.Major - other.Major, version.Minor - other.Minor, version.Patch - other.Patch} {
+		switch {
+		case difference < 0:
+			return -1
+		case difference > 0:
+			return 1
+		}
+	}
+	return 0
+}
+
+// AtLeast reports whether the version is the given one or a later one, e.g. AtLeast("4.4")
+// It panics if the given version cannot be parsed, which is a mistake in the test
+func (version Version) AtLeast(minimum string) bool {
+	parsed, err := ParseVersion(minimum)
+	if err != nil {
+		panic(err)
+	}
+	return version.Compare(parsed) >= 0
+}
+
+// VersionedServer is a server of the version matrix, along with the version it reports
+type VersionedServer struct {
+	Neo4jServer
+	Version Version
+}
+
+// SkipUnless skips the test unless the server version is at least the given one
+// Use it to document when a behavior was introduced, e.g. SkipUnless(t, "4.4") for CALL {} IN TRANSACTIONS
+func (server VersionedServer) SkipUnless(t testing.TB, minimum string) {
+	t.Helper()
+	if !server.Version.AtLeast(minimum) {
+		t.Skipf("Requires Neo4j %s or later, server runs %s", minimum, server.Version)
+	}
+}
+
+// SkipFrom skips the test when the server version is the given one or a later one
+// Use it to document when a behavior was removed, e.g. SkipFrom(t, "5.0") for USING PERIODIC COMMIT
+func (server VersionedServer) SkipFrom(t testing.TB, version string) {
+	t.Helper()
+	if server.Version.AtLeast(version) {
+		t.Skipf("Requires a Neo4j version before %s, server runs %s", version, server.Version)
+	}
+}
+
+// RootCAs returns the certificate authority of the server, if any
+func (server VersionedServer) RootCAs() *x509.CertPool {
+	if authority, ok := server.Neo4jServer.(certificateAuthority); ok {
+		return authority.RootCAs()
+	}
+	return nil
+}
+
+type matrixServer struct {
+	server  Neo4jServer
+	edition string
+	err     error
+}
+
+// Matrix runs the body once per version, as subtests named after the version, e.g. "Neo4j 4.4"
+// VersionsEnvVar overrides the versions, each server is shared by the tests of the package and isolated like with Isolate
+// The fake and external backends do not start servers, the body then runs once, against the shared server
+func Matrix(t *testing.T, versions []string, body func(t *testing.T, server VersionedServer)) {
+	t.Helper()
+	if override := os.Getenv(VersionsEnvVar); override != "" {
+		versions = strings.Split(override, ",")
+	}
+	if SelectedBackend() != ContainerBackend {
+		server := Isolate(t)
+		version, err := detectVersion(server)
+		if err != nil {
+			t.Fatalf("Could not detect server version: %v", err)
+		}
+		t.Run(fmt.Sprintf("Neo4j %d.%d", version.Major, version.Minor), func(t *testing.T) {
+			body(t, VersionedServer{Neo4jServer: server, Version: version})
+		})
+		return
+	}
+	for _, version := range versions {
+		version := strings.TrimSpace(version)
+		t.Run("Neo4j "+version, func(t *testing.T) {
+			server, edition := versionServer(t, version)
+			isolated := isolate(t, server, edition)
+			detected, err := detectVersion(isolated)
+			if err != nil {
+				t.Fatalf("Could not detect server version: %v", err)
+			}
+			body(t, VersionedServer{Neo4jServer: isolated, Version: detected})
+		})
+	}
+}
+
+// versionServer starts the shared server of the version on first use and returns it along with its edition
+// The server started by Main is reused when it already runs that version
+func versionServer(t testing.TB, version string) (Neo4jServer, string) {
+	t.Helper()
+	shared.Lock()
+	mainVersion := newContainerConfiguration(shared.options).Neo4jVersion
+	shared.Unlock()
+	if version == mainVersion {
+		return sharedServer(t)
+	}
+	shared.Lock()
+	defer shared.Unlock()
+	if !shared.running {
+		t.Fatal("The Neo4j version matrix is only available when TestMain calls neo4jtest.Main")
+	}
+	if shared.matrix == nil {
+		shared.matrix = map[string]*matrixServer{}
+	}
+	versioned, found := shared.matrix[version]
+	if !found {
+		versioned = &matrixServer{}
+		options := append(append([]Option(nil), shared.options...), WithVersion(version))
+		versioned.server, versioned.edition, versioned.err = startShared(options)
+		shared.matrix[version] = versioned
+	}
+	if versioned.err != nil {
+		t.Fatalf("Could not start Neo4j %s server: %v", version, versioned.err)
+	}
+	return versioned.server, versioned.edition
+}
+
+// detectVersion reads the version of the server agent, e.g. "Neo4j/4.4.12"
+func detectVersion(server Neo4jServer) (Version, error) {
+	if _, fake := server.(*fakebolt.Server); fake {
+		// fake servers only answer scripted queries
+		return ParseVersion(fakebolt.ServerAgent)
+	}
+	var agent string
+	err := withSession(server, "", func(session neo4j.Session) error {
+		result, err := session.Run("RETURN 1 AS n", nil)
+		if err != nil {
+			return err
+		}
+		summary, err := result.Consume()
+		if err != nil {
+			return err
+		}
+		agent = summary.Server().Version()
+		return nil
+	})
+	if err != nil {
+		return Version{}, err
+	}
+	return ParseVersion(agent)
+}
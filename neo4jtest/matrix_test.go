@@ -0,0 +1,104 @@
+package neo4jtest
+
+import (
+	"fmt"
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+)
+
+func TestMatrix(outer *testing.T) {
+	outer.Run("parses versions", func(t *testing.T) {
+		for text, expected := range map[string]Version{
+			"5":              {Major: 5},
+			"4.4":            {Major: 4, Minor: 4},
+			"4.4.12":         {Major: 4, Minor: 4, Patch: 12},
+			"4.4-enterprise": {Major: 4, Minor: 4},
+			"Neo4j/5.3.0":    {Major: 5, Minor: 3},
+		} {
+			version, err := ParseVersion(text)
+
+			if err != nil {
+				t.Errorf("Expected nil error for %q, got: %v", text, err)
+			}
+			if version != expected {
+				t.Errorf("Expected %v for %q, got: %v", expected, text, version)
+			}
+		}
+	})
+	outer.Run("rejects invalid versions", func(t *testing.T) {
+		for _, text := range []string{"", "latest", "4.4.1.2"} {
+			if _, err := ParseVersion(text); err == nil {
+				t.Errorf("Expected error for %q, got nil", text)
+			}
+		}
+	})
+	outer.Run("compares versions", func(t *testing.T) {
+		version := Version{Major: 4, Minor: 4, Patch: 12}
+
+		for minimum, expected := range map[string]bool{"4": true, "4.4": true, "4.4.12": true, "4.4.13": false, "5.0": false} {
+			if atLeast := version.AtLeast(minimum); atLeast != expected {
+				t.Errorf("Expected AtLeast(%q) to be %t, got: %t", minimum, expected, atLeast)
+			}
+		}
+	})
+	outer.Run("skips tests depending on the server version", func(t *testing.T) {
+		server := VersionedServer{Version: Version{Major: 4, Minor: 4}}
+		skipped := map[string]bool{}
+		for _, check := range []struct {
+			name string
+			skip func(testing.TB, string)
+			arg  string
+		}{
+			{"unless 4.3", server.SkipUnless, "4.3"},
+			{"unless 5.0", server.SkipUnless, "5.0"},
+			{"from 4.4", server.SkipFrom, "4.4"},
+			{"from 5.0", server.SkipFrom, "5.0"},
+		} {
+			tb := &recordingTB{TB: t}
+			check.skip(tb, check.arg)
+			skipped[check.name] = tb.skipped
+		}
+
+		expected := map[string]bool{"unless 4.3": false, "unless 5.0": true, "from 4.4": true, "from 5.0": false}
+		if !reflect.DeepEqual(skipped, expected) {
+			t.Errorf("Expected %v, got: %v", expected, skipped)
+		}
+	})
+	outer.Run("runs once against fake servers", func(t *testing.T) {
+		t.Setenv(BackendEnvVar, string(FakeBackend))
+		useSharedServer(t, startFakeServer(t, fakebolt.Script{}), "community")
+		var runs []string
+
+		Matrix(t, []string{"4.4", "5"}, func(t *testing.T, server VersionedServer) {
+			runs = append(runs, fmt.Sprintf("%s %s", t.Name(), server.Version))
+		})
+
+		expected := []string{"TestMatrix/runs_once_against_fake_servers/Neo4j_4.4 4.4.0"}
+		if !reflect.DeepEqual(runs, expected) {
+			t.Errorf("Expected %v, got: %v", expected, runs)
+		}
+	})
+	outer.Run("runs once per version", func(t *testing.T) {
+		t.Setenv(BackendEnvVar, string(ContainerBackend))
+		t.Setenv(VersionsEnvVar, "4.4, 4.3")
+		useSharedServer(t, startFakeServer(t, fakebolt.Script{}), "community")
+		shared.matrix = map[string]*matrixServer{
+			"4.3": {server: startFakeServer(t, fakebolt.Script{}), edition: "enterprise"},
+			"4.4": {server: startFakeServer(t, fakebolt.Script{}), edition: "enterprise"},
+		}
+		var runs []string
+
+		Matrix(t, []string{"5"}, func(t *testing.T, server VersionedServer) {
+			runs = append(runs, t.Name())
+		})
+
+		expected := []string{
+			"TestMatrix/runs_once_per_version/Neo4j_4.4",
+			"TestMatrix/runs_once_per_version/Neo4j_4.3",
+		}
+		if !reflect.DeepEqual(runs, expected) {
+			t.Errorf("Expected %v, got: %v", expected, runs)
+		}
+	})
+}
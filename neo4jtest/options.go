@@ -0,0 +1,90 @@
+package neo4jtest
+
+import (
+	"fmt"
+	"graphconnect/neo4jtest/fakebolt"
+	"strconv"
+	"strings"
+)
+
+// ContainerConfiguration gathers the settings applied by each Option
+type ContainerConfiguration struct {
+	Neo4jVersion string
+	// Enterprise selects the Enterprise Edition image, even when Neo4jVersion does not end with "-enterprise"
+	Enterprise bool
+	Username   string
+	Password   string
+	Plugins    []Plugin
+	// Settings are neo4j.conf settings, e.g. "dbms.transaction.timeout"
+	Settings        map[string]string
+	HeapInitialSize MemorySize
+	HeapMaxSize     MemorySize
+	PageCacheSize   MemorySize
+	// TLS requires encrypted Bolt connections
+	TLS bool
+	// Script is only used by the fake backend
+	Script fakebolt.Script
+}
+
+// Option customizes the ContainerConfiguration
+type Option func(*ContainerConfiguration)
+
+// WithVersion selects the Neo4j Docker image tag, e.g. "4.4" or "4.4-enterprise"
+func WithVersion(version string) Option {
+	return func(config *ContainerConfiguration) {
+		config.Neo4jVersion = version
+	}
+}
+
+// WithEnterpriseEdition selects the Enterprise Edition image of the Neo4j version
+// The license agreement is accepted on your behalf, see https://neo4j.com/licensing/
+func WithEnterpriseEdition() Option {
+	return func(config *ContainerConfiguration) {
+		config.Enterprise = true
+	}
+}
+
+// WithAuth sets the credentials of the initial user
+func WithAuth(username, password string) Option {
+	return func(config *ContainerConfiguration) {
+		config.Username = username
+		config.Password = password
+	}
+}
+
+// WithScript sets the queries the fake backend answers
+func WithScript(script fakebolt.Script) Option {
+	return func(config *ContainerConfiguration) {
+		config.Script = script
+	}
+}
+
+func newContainerConfiguration(options []Option) ContainerConfiguration {
+	config := ContainerConfiguration{
+		Neo4jVersion: defaultNeo4jVersion,
+		Username:     defaultUsername,
+		Password:     defaultPassword,
+	}
+	for _, option := range options {
+		option(&config)
+	}
+	return config
+}
+
+func (config ContainerConfiguration) neo4jAuthEnvVar() string {
+	return fmt.Sprintf("%s/%s", config.Username, config.Password)
+}
+
+// majorVersion parses the major version of the Docker image tag, e.g. 4 for "4.4-enterprise"
+func (config ContainerConfiguration) majorVersion() int {
+	major, _ := strconv.Atoi(strings.SplitN(config.Neo4jVersion, ".", 2)[0])
+	return major
+}
+
+// imageTag returns the Docker image tag of the Neo4j version, with the "-enterprise" suffix if needed
+func (config ContainerConfiguration) imageTag() string {
+	if config.Enterprise && !strings.HasSuffix(config.Neo4jVersion, "-enterprise") {
+		return config.Neo4jVersion + "-enterprise"
+	}
+	return config.Neo4jVersion
+}
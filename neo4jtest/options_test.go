@@ -0,0 +1,40 @@
+package neo4jtest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestContainerConfiguration(outer *testing.T) {
+	outer.Run("defaults to Neo4j 4.4 with the workshop credentials", func(t *testing.T) {
+		config := newContainerConfiguration(nil)
+
+		expected := ContainerConfiguration{Neo4jVersion: "4.4", Username: "neo4j", Password: "s3cr3t"}
+		if !reflect.DeepEqual(config, expected) {
+			t.Errorf("Expected %v, got: %v", expected, config)
+		}
+	})
+	outer.Run("applies options in order", func(t *testing.T) {
+		config := newContainerConfiguration([]Option{
+			WithVersion("4.3"),
+			WithAuth("admin", "changeme"),
+			WithVersion("4.4-enterprise"),
+		})
+
+		if config.Neo4jVersion != "4.4-enterprise" {
+			t.Errorf("Expected last version to win, got: %s", config.Neo4jVersion)
+		}
+		if auth := config.neo4jAuthEnvVar(); auth != "admin/changeme" {
+			t.Errorf("Expected NEO4J_AUTH to be admin/changeme, got: %s", auth)
+		}
+	})
+	outer.Run("selects the enterprise image", func(t *testing.T) {
+		for _, version := range []string{"4.4", "4.4-enterprise"} {
+			config := newContainerConfiguration([]Option{WithVersion(version), WithEnterpriseEdition()})
+
+			if tag := config.imageTag(); tag != "4.4-enterprise" {
+				t.Errorf("Expected 4.4-enterprise image for version %s, got: %s", version, tag)
+			}
+		}
+	})
+}
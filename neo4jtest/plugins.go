@@ -0,0 +1,193 @@
+package neo4jtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+// PluginCacheEnvVar overrides the directory where plugin jars are cached, per Neo4j version
+const PluginCacheEnvVar = "NEO4J_TEST_PLUGIN_CACHE"
+
+const pluginsDirectory = "/plugins"
+const pluginRegistrationTimeout = 2 * time.Minute
+
+// Plugin is a Neo4j server plugin
+// It is installed from JarPath when set, from the plugin cache when the jar is there, or else downloaded by the container
+type Plugin struct {
+	// Name is the plugin name known to the Neo4j Docker image, e.g. "apoc"
+	Name string
+	// ProcedurePrefix is shared by all the plugin procedures, e.g. "apoc."
+	ProcedurePrefix string
+	// JarPath is the optional path of a local jar file
+	JarPath string
+}
+
+// APOC is the Awesome Procedures On Cypher library
+var APOC = Plugin{Name: "apoc", ProcedurePrefix: "apoc."}
+
+// GraphDataScience is the Graph Data Science library
+var GraphDataScience = Plugin{Name: "graph-data-science", ProcedurePrefix: "gds."}
+
+// FromJar returns a copy of the plugin installed from the given local jar file
+func (plugin Plugin) FromJar(path string) Plugin {
+	plugin.JarPath = path
+	return plugin
+}
+
+// WithPlugins installs the plugins, their procedures are unrestricted and registered by the time the container starts
+func WithPlugins(plugins ...Plugin) Option {
+	return func(config *ContainerConfiguration) {
+		config.Plugins = append(config.Plugins, plugins...)
+	}
+}
+
+// configurePlugins mounts the local or cached jars and lets the container download the other plugins
+func configurePlugins(request *testcontainers.ContainerRequest, config ContainerConfiguration) error {
+	if len(config.Plugins) == 0 {
+		return nil
+	}
+	var downloads []string
+	var prefixes []string
+	for _, plugin := range config.Plugins {
+		prefixes = append(prefixes, plugin.ProcedurePrefix+"*")
+		jar, err := plugin.localJar(config.Neo4jVersion)
+		if err != nil {
+			return err
+		}
+		if jar == "" {
+			downloads = append(downloads, plugin.Name)
+			continue
+		}
+		target := testcontainers.ContainerMountTarget(fmt.Sprintf("%s/%s.jar", pluginsDirectory, plugin.Name))
+		request.Mounts = append(request.Mounts, testcontainers.BindMount(jar, target))
+	}
+	// keep the procedures unrestricted with WithSetting, if any
+	unrestricted := settingEnvVar("dbms.security.procedures.unrestricted")
+	if existing := request.Env[unrestricted]; existing != "" {
+		prefixes = append([]string{existing}, prefixes...)
+	}
+	request.Env[unrestricted] = strings.Join(prefixes, ",")
+	if len(downloads) > 0 {
+		names, err := json.Marshal(downloads)
+		if err != nil {
+			return err
+		}
+		request.Env[pluginsEnvVar(config.majorVersion())] = string(names)
+	}
+	return nil
+}
+
+// localJar returns the absolute path of the plugin jar on this machine, or an empty string if it must be downloaded
+func (plugin Plugin) localJar(neo4jVersion string) (string, error) {
+	if plugin.JarPath != "" {
+		path, err := filepath.Abs(plugin.JarPath)
+		if err != nil {
+			return "", err
+		}
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("could not find jar of plugin %s: %w", plugin.Name, err)
+		}
+		return path, nil
+	}
+	cached := plugin.cachedJar(neo4jVersion)
+	if _, err := os.Stat(cached); err != nil {
+		return "", nil
+	}
+	return cached, nil
+}
+
+func (plugin Plugin) cachedJar(neo4jVersion string) string {
+	return filepath.Join(pluginCacheDirectory(), neo4jVersion, plugin.Name+".jar")
+}
+
+func pluginCacheDirectory() string {
+	if directory := os.Getenv(PluginCacheEnvVar); directory != "" {
+		return directory
+	}
+	directory, err := os.UserCacheDir()
+	if err != nil {
+		directory = os.TempDir()
+	}
+	return filepath.Join(directory, "neo4jtest", "plugins")
+}
+
+// pluginsEnvVar is the environment variable the Neo4j Docker image reads the plugins to download from
+func pluginsEnvVar(majorVersion int) string {
+	if majorVersion >= 5 {
+		return "NEO4J_PLUGINS"
+	}
+	return "NEO4JLABS_PLUGINS"
+}
+
+// cacheDownloadedPlugins copies the jars downloaded by the container to the plugin cache, on a best-effort basis
+func cacheDownloadedPlugins(ctx context.Context, container testcontainers.Container, config ContainerConfiguration) {
+	for _, plugin := range config.Plugins {
+		if jar, err := plugin.localJar(config.Neo4jVersion); err != nil || jar != "" {
+			continue
+		}
+		for _, path := range []string{
+			fmt.Sprintf("%s/%s.jar", pluginsDirectory, plugin.Name),
+			fmt.Sprintf("/var/lib/neo4j/plugins/%s.jar", plugin.Name),
+		} {
+			if err := copyFromContainer(ctx, container, path, plugin.cachedJar(config.Neo4jVersion)); err == nil {
+				break
+			}
+		}
+	}
+}
+
+func copyFromContainer(ctx context.Context, container testcontainers.Container, source string, target string) error {
+	reader, err := container.CopyFileFromContainer(ctx, source)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	// write to a temporary file first, so that a partial copy is never picked up from the cache
+	partial := target + ".partial"
+	file, err := os.Create(partial)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, reader); err != nil {
+		_ = file.Close()
+		_ = os.Remove(partial)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(partial, target)
+}
+
+// waitForPlugins polls the server until each plugin has registered at least one procedure
+func waitForPlugins(ctx context.Context, server Neo4jServer, plugins []Plugin) error {
+	ctx, cancel := context.WithTimeout(ctx, pluginRegistrationTimeout)
+	defer cancel()
+	for _, plugin := range plugins {
+		for {
+			count, err := runSingle(server,
+				"SHOW PROCEDURES YIELD name WHERE name STARTS WITH $prefix RETURN count(*)",
+				map[string]any{"prefix": plugin.ProcedurePrefix})
+			if err == nil && count.(int64) > 0 {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("procedures of plugin %s are not registered (last error: %v): %w", plugin.Name, err, ctx.Err())
+			case <-time.After(500 * time.Millisecond):
+			}
+		}
+	}
+	return nil
+}
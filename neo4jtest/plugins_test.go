@@ -0,0 +1,116 @@
+package neo4jtest
+
+import (
+	"context"
+	"graphconnect/neo4jtest/fakebolt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestPlugins(outer *testing.T) {
+	outer.Run("lets the container download plugins that are not cached", func(t *testing.T) {
+		t.Setenv(PluginCacheEnvVar, t.TempDir())
+		request := testcontainers.ContainerRequest{Env: map[string]string{}}
+
+		err := configurePlugins(&request, newContainerConfiguration([]Option{WithPlugins(APOC, GraphDataScience)}))
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := map[string]string{
+			"NEO4JLABS_PLUGINS":                           `["apoc","graph-data-science"]`,
+			"NEO4J_dbms_security_procedures_unrestricted": "apoc.*,gds.*",
+		}
+		if !reflect.DeepEqual(request.Env, expected) {
+			t.Errorf("Expected %v, got: %v", expected, request.Env)
+		}
+		if len(request.Mounts) > 0 {
+			t.Errorf("Expected no mount, got: %v", request.Mounts)
+		}
+	})
+	outer.Run("uses the Neo4j 5 plugin variable", func(t *testing.T) {
+		t.Setenv(PluginCacheEnvVar, t.TempDir())
+		request := testcontainers.ContainerRequest{Env: map[string]string{}}
+
+		err := configurePlugins(&request, newContainerConfiguration([]Option{WithVersion("5.3"), WithPlugins(APOC)}))
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if plugins := request.Env["NEO4J_PLUGINS"]; plugins != `["apoc"]` {
+			t.Errorf(`Expected NEO4J_PLUGINS to be ["apoc"], got: %q`, plugins)
+		}
+	})
+	outer.Run("mounts local and cached jars", func(t *testing.T) {
+		cache := t.TempDir()
+		t.Setenv(PluginCacheEnvVar, cache)
+		cachedJar := createFile(t, filepath.Join(cache, "4.4", "apoc.jar"))
+		localJar := createFile(t, filepath.Join(t.TempDir(), "neo4j-graph-data-science-2.2.5.jar"))
+		request := testcontainers.ContainerRequest{Env: map[string]string{}}
+
+		err := configurePlugins(&request, newContainerConfiguration([]Option{
+			WithPlugins(APOC, GraphDataScience.FromJar(localJar)),
+		}))
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := testcontainers.ContainerMounts{
+			testcontainers.BindMount(cachedJar, "/plugins/apoc.jar"),
+			testcontainers.BindMount(localJar, "/plugins/graph-data-science.jar"),
+		}
+		if !reflect.DeepEqual(request.Mounts, expected) {
+			t.Errorf("Expected %v, got: %v", expected, request.Mounts)
+		}
+		if _, found := request.Env["NEO4JLABS_PLUGINS"]; found {
+			t.Errorf("Expected no plugin to be downloaded, got: %v", request.Env)
+		}
+	})
+	outer.Run("fails when a local jar is missing", func(t *testing.T) {
+		request := testcontainers.ContainerRequest{Env: map[string]string{}}
+
+		err := configurePlugins(&request, newContainerConfiguration([]Option{
+			WithPlugins(APOC.FromJar(filepath.Join(t.TempDir(), "missing.jar"))),
+		}))
+
+		if err == nil {
+			t.Errorf("Expected missing jar error, got nil")
+		}
+	})
+	outer.Run("waits until plugin procedures are registered", func(t *testing.T) {
+		server, err := fakebolt.Start(fakebolt.Script{
+			"SHOW PROCEDURES YIELD name WHERE name STARTS WITH $prefix RETURN count(*)": {
+				Keys:    []string{"count(*)"},
+				Records: [][]any{{1}},
+			},
+		})
+		if err != nil {
+			t.Fatalf("Could not start fake server: %v", err)
+		}
+		defer server.Close()
+
+		err = waitForPlugins(context.Background(), server, []Plugin{APOC, GraphDataScience})
+
+		if err != nil {
+			t.Errorf("Expected nil error, got: %v", err)
+		}
+		received := server.Received()
+		if prefix := received[len(received)-1].Params["prefix"]; prefix != "gds." {
+			t.Errorf(`Expected last check to be about "gds.", got: %v`, prefix)
+		}
+	})
+}
+
+func createFile(t *testing.T, path string) string {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatalf("Could not create directory: %v", err)
+	}
+	if err := os.WriteFile(path, nil, 0o644); err != nil {
+		t.Fatalf("Could not create file: %v", err)
+	}
+	return path
+}
@@ -0,0 +1,141 @@
+package neo4jtest
+
+import (
+	"embed"
+	"fmt"
+	"graphconnect/neo4jtest/cypher"
+	"io/fs"
+	"sort"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+//go:embed fixtures/*.cypher
+var embeddedFixtures embed.FS
+
+// Fixtures are the Cypher fixtures shared by the workshop modules, e.g. "small_graph.cypher"
+var Fixtures fs.FS = mustSub(embeddedFixtures, "fixtures")
+
+// SeedSummary reports what a Cypher file changed
+type SeedSummary struct {
+	File             string
+	SchemaStatements int
+	DataStatements   int
+	Counters         Counters
+}
+
+func (summary SeedSummary) String() string {
+	return fmt.Sprintf("%s: %d schema and %d data statements, %s",
+		summary.File, summary.SchemaStatements, summary.DataStatements, summary.Counters)
+}
+
+// Seed runs the Cypher files of fsys matching the patterns against the server and logs their summaries
+// Use os.DirFS("testdata") for the files of the test package, or Fixtures for the shared ones
+func Seed(t testing.TB, server Neo4jServer, fsys fs.FS, patterns ...string) []SeedSummary {
+	t.Helper()
+	var summaries []SeedSummary
+	driver, err := NewDriver(server, "")
+	if err == nil {
+		summaries, err = LoadSeed(driver, "", fsys, patterns...)
+		if closeErr := driver.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	if err != nil {
+		t.Fatalf("Could not seed database: %v", err)
+	}
+	for _, summary := range summaries {
+		t.Logf("Seeded %s", summary)
+	}
+	return summaries
+}
+
+// LoadSeed runs the Cypher files of fsys matching the glob patterns, in lexical order
+// Each file is split into statements, its schema statements run in a first transaction and its data statements in a second
+func LoadSeed(driver neo4j.Driver, database string, fsys fs.FS, patterns ...string) (summaries []SeedSummary, err error) {
+	files, err := matchFiles(fsys, patterns)
+	if err != nil {
+		return nil, err
+	}
+	session := driver.NewSession(neo4j.SessionConfig{DatabaseName: database, AccessMode: neo4j.AccessModeWrite})
+	defer func() {
+		if closeErr := session.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	for _, file := range files {
+		content, err := fs.ReadFile(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+		schema, data := cypher.Partition(cypher.Split(string(content)))
+		summary := SeedSummary{File: file, SchemaStatements: len(schema), DataStatements: len(data)}
+		// note: with Neo4j, you cannot mix schema and data operations, hence the separate transactions
+		for _, statements := range [][]string{schema, data} {
+			if len(statements) == 0 {
+				continue
+			}
+			results, err := session.WriteTransaction(runStatements(statements))
+			if err != nil {
+				return nil, fmt.Errorf("could not seed %s: %w", file, err)
+			}
+			for _, result := range results.([]neo4j.ResultSummary) {
+				summary.Counters.Add(result.Counters())
+			}
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// SplitStatements splits a Cypher script into statements, see cypher.Split
+func SplitStatements(script string) []string {
+	return cypher.Split(script)
+}
+
+func runStatements(statements []string) neo4j.TransactionWork {
+	return func(tx neo4j.Transaction) (any, error) {
+		summaries := make([]neo4j.ResultSummary, len(statements))
+		for i, statement := range statements {
+			result, err := tx.Run(statement, nil)
+			if err != nil {
+				return nil, err
+			}
+			if summaries[i], err = result.Consume(); err != nil {
+				return nil, err
+			}
+		}
+		return summaries, nil
+	}
+}
+
+func matchFiles(fsys fs.FS, patterns []string) ([]string, error) {
+	unique := map[string]struct{}{}
+	for _, pattern := range patterns {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no Cypher file matches %s", pattern)
+		}
+		for _, match := range matches {
+			unique[match] = struct{}{}
+		}
+	}
+	files := make([]string, 0, len(unique))
+	for file := range unique {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func mustSub(fsys fs.FS, directory string) fs.FS {
+	sub, err := fs.Sub(fsys, directory)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,59 @@
+package neo4jtest
+
+import (
+	"graphconnect/neo4jtest/fakebolt"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSeed(outer *testing.T) {
+	outer.Run("reports the counters of each file", func(t *testing.T) {
+		server := startFakeServer(t, fakebolt.Script{
+			"CREATE INDEX FOR (t:Topic) ON (t.name)": {Counters: map[string]int{"indexes-added": 1}},
+			"MERGE (neo4j:Topic {name: \"Neo4j\"})":  {Counters: map[string]int{"nodes-created": 1, "labels-added": 1, "properties-set": 1}},
+			"MERGE (eric:Person {name: \"Eric\"})":   {Counters: map[string]int{"nodes-created": 1, "labels-added": 1, "properties-set": 1}},
+			"MATCH (n) SET n.seeded = true RETURN n": {Counters: map[string]int{"properties-set": 2}},
+		})
+		driver, err := NewDriver(server, "")
+		if err != nil {
+			t.Fatalf("Could not create driver: %v", err)
+		}
+		defer driver.Close()
+		fsys := fstest.MapFS{
+			"2_data.cypher":   {Data: []byte("MERGE (eric:Person {name: \"Eric\"});\nMATCH (n) SET n.seeded = true RETURN n;")},
+			"1_topics.cypher": {Data: []byte("MERGE (neo4j:Topic {name: \"Neo4j\"});\nCREATE INDEX FOR (t:Topic) ON (t.name);")},
+			"README.md":       {Data: []byte("not a Cypher file")},
+		}
+
+		summaries, err := LoadSeed(driver, "", fsys, "*.cypher")
+
+		if err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		expected := []SeedSummary{
+			{File: "1_topics.cypher", SchemaStatements: 1, DataStatements: 1,
+				Counters: Counters{NodesCreated: 1, LabelsAdded: 1, PropertiesSet: 1, IndexesAdded: 1}},
+			{File: "2_data.cypher", DataStatements: 2,
+				Counters: Counters{NodesCreated: 1, LabelsAdded: 1, PropertiesSet: 3}},
+		}
+		if !reflect.DeepEqual(summaries, expected) {
+			t.Errorf("Expected %v, got: %v", expected, summaries)
+		}
+		if queries := receivedQueries(server); queries[0] != "CREATE INDEX FOR (t:Topic) ON (t.name)" {
+			t.Errorf("Expected schema statements to run first, got: %v", queries)
+		}
+	})
+	outer.Run("fails when a pattern matches no file", func(t *testing.T) {
+		_, err := LoadSeed(nil, "", fstest.MapFS{}, "missing.cypher")
+
+		if err == nil {
+			t.Errorf("Expected missing file error, got nil")
+		}
+	})
+	outer.Run("embeds the shared fixtures", func(t *testing.T) {
+		if _, err := matchFiles(Fixtures, []string{"small_graph.cypher"}); err != nil {
+			t.Errorf("Expected small graph fixture, got: %v", err)
+		}
+	})
+}
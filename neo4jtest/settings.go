@@ -0,0 +1,107 @@
+package neo4jtest
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MemorySize is a size in bytes, formatted the way neo4j.conf expects it
+type MemorySize int64
+
+const (
+	Kilobyte MemorySize = 1 << (10 * (iota + 1))
+	Megabyte
+	Gigabyte
+)
+
+// Megabytes returns the MemorySize of n megabytes
+func Megabytes(n int64) MemorySize {
+	return MemorySize(n) * Megabyte
+}
+
+// Gigabytes returns the MemorySize of n gigabytes
+func Gigabytes(n int64) MemorySize {
+	return MemorySize(n) * Gigabyte
+}
+
+// String formats the size with the largest unit dividing it, e.g. "512m" or "2g"
+func (size MemorySize) String() string {
+	for _, unit := range []struct {
+		size   MemorySize
+		suffix string
+	}{{Gigabyte, "g"}, {Megabyte, "m"}, {Kilobyte, "k"}} {
+		if size >= unit.size && size%unit.size == 0 {
+			return fmt.Sprintf("%d%s", size/unit.size, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%d", int64(size))
+}
+
+// WithSetting sets a neo4j.conf setting, e.g. WithSetting("dbms.transaction.timeout", "5s")
+func WithSetting(key, value string) Option {
+	return func(config *ContainerConfiguration) {
+		if config.Settings == nil {
+			config.Settings = map[string]string{}
+		}
+		config.Settings[key] = value
+	}
+}
+
+// WithSettings sets several neo4j.conf settings at once
+func WithSettings(settings map[string]string) Option {
+	return func(config *ContainerConfiguration) {
+		for key, value := range settings {
+			WithSetting(key, value)(config)
+		}
+	}
+}
+
+// WithHeapSize sets the initial and maximum JVM heap size
+// The setting names depend on the Neo4j version, they are resolved when the container starts
+func WithHeapSize(initial, max MemorySize) Option {
+	return func(config *ContainerConfiguration) {
+		config.HeapInitialSize = initial
+		config.HeapMaxSize = max
+	}
+}
+
+// WithPageCacheSize sets the size of the page cache, which caches the store files
+func WithPageCacheSize(size MemorySize) Option {
+	return func(config *ContainerConfiguration) {
+		config.PageCacheSize = size
+	}
+}
+
+// settingsEnv translates the settings to environment variables, explicit settings take precedence over memory sizes
+func (config ContainerConfiguration) settingsEnv() map[string]string {
+	// memory settings moved from the dbms to the server namespace in Neo4j 5
+	namespace := "dbms"
+	if config.majorVersion() >= 5 {
+		namespace = "server"
+	}
+	settings := map[string]string{}
+	for key, size := range map[string]MemorySize{
+		namespace + ".memory.heap.initial_size": config.HeapInitialSize,
+		namespace + ".memory.heap.max_size":     config.HeapMaxSize,
+		namespace + ".memory.pagecache.size":    config.PageCacheSize,
+	} {
+		if size > 0 {
+			settings[key] = size.String()
+		}
+	}
+	for key, value := range config.Settings {
+		settings[key] = value
+	}
+	env := make(map[string]string, len(settings))
+	for key, value := range settings {
+		env[settingEnvVar(key)] = value
+	}
+	return env
+}
+
+// settingEnvVar translates a neo4j.conf setting to the environment variable convention of the Neo4j Docker image
+// dots become underscores and underscores are doubled, e.g. dbms.memory.heap.max_size is NEO4J_dbms_memory_heap_max__size
+func settingEnvVar(key string) string {
+	escaped := strings.ReplaceAll(key, "_", "__")
+	return "NEO4J_" + strings.ReplaceAll(escaped, ".", "_")
+}
@@ -0,0 +1,87 @@
+package neo4jtest
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+)
+
+func TestSettings(outer *testing.T) {
+	outer.Run("translates settings to environment variables", func(t *testing.T) {
+		config := newContainerConfiguration([]Option{
+			WithSetting("dbms.transaction.timeout", "5s"),
+			WithSettings(map[string]string{"db.tx_log.rotation.retention_policy": "1 files"}),
+		})
+
+		expected := map[string]string{
+			"NEO4J_dbms_transaction_timeout":              "5s",
+			"NEO4J_db_tx__log_rotation_retention__policy": "1 files",
+		}
+		if env := config.settingsEnv(); !reflect.DeepEqual(env, expected) {
+			t.Errorf("Expected %v, got: %v", expected, env)
+		}
+	})
+	outer.Run("uses the memory settings of the Neo4j version", func(t *testing.T) {
+		for version, expected := range map[string]map[string]string{
+			"4.4": {
+				"NEO4J_dbms_memory_heap_initial__size": "512m",
+				"NEO4J_dbms_memory_heap_max__size":     "1g",
+				"NEO4J_dbms_memory_pagecache_size":     "256m",
+			},
+			"5.3-enterprise": {
+				"NEO4J_server_memory_heap_initial__size": "512m",
+				"NEO4J_server_memory_heap_max__size":     "1g",
+				"NEO4J_server_memory_pagecache_size":     "256m",
+			},
+		} {
+			config := newContainerConfiguration([]Option{
+				WithHeapSize(Megabytes(512), Gigabytes(1)),
+				WithPageCacheSize(Megabytes(256)),
+				WithVersion(version),
+			})
+
+			if env := config.settingsEnv(); !reflect.DeepEqual(env, expected) {
+				t.Errorf("Expected %v for version %s, got: %v", expected, version, env)
+			}
+		}
+	})
+	outer.Run("prefers explicit settings over memory sizes", func(t *testing.T) {
+		config := newContainerConfiguration([]Option{
+			WithSetting("dbms.memory.pagecache.size", "10%"),
+			WithPageCacheSize(Megabytes(256)),
+		})
+
+		if size := config.settingsEnv()["NEO4J_dbms_memory_pagecache_size"]; size != "10%" {
+			t.Errorf("Expected page cache size 10%%, got: %s", size)
+		}
+	})
+	outer.Run("formats memory sizes", func(t *testing.T) {
+		for size, expected := range map[MemorySize]string{
+			Gigabytes(2):                "2g",
+			Megabytes(1536):             "1536m",
+			Kilobyte * 3:                "3k",
+			MemorySize(1000):            "1000",
+			Gigabytes(1) + Megabytes(1): "1025m",
+		} {
+			if formatted := size.String(); formatted != expected {
+				t.Errorf("Expected %s, got: %s", expected, formatted)
+			}
+		}
+	})
+	outer.Run("keeps unrestricted procedures alongside plugins", func(t *testing.T) {
+		t.Setenv(PluginCacheEnvVar, t.TempDir())
+		config := newContainerConfiguration([]Option{
+			WithSetting("dbms.security.procedures.unrestricted", "my.procedures.*"),
+			WithPlugins(APOC),
+		})
+		request := testcontainers.ContainerRequest{Env: config.settingsEnv()}
+
+		if err := configurePlugins(&request, config); err != nil {
+			t.Fatalf("Expected nil error, got: %v", err)
+		}
+		if unrestricted := request.Env["NEO4J_dbms_security_procedures_unrestricted"]; unrestricted != "my.procedures.*,apoc.*" {
+			t.Errorf("Expected my.procedures.*,apoc.*, got: %s", unrestricted)
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package neo4jtest
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"graphconnect/neo4jtest/fakebolt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+// AllowWipeEnvVar must be set to "yes" before the data of an external server can be wiped
+const AllowWipeEnvVar = "NEO4J_TEST_ALLOW_WIPE"
+
+const isolatedPassword = "s3cr3t"
+
+var shared struct {
+	sync.Mutex
+	running bool
+	options []Option
+	server  Neo4jServer
+	err     error
+	started bool
+	edition string
+	// matrix holds the servers started by Matrix, per version
+	matrix map[string]*matrixServer
+}
+
+var isolatedCount int64
+
+// Main runs the tests of the package and stops the server they share, if any was started
+// The shared server is only started by the first test calling Isolate or Wipe, with the given options
+// Call it from TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		os.Exit(neo4jtest.Main(m, neo4jtest.WithVersion("4.4")))
+//	}
+func Main(m *testing.M, options ...Option) int {
+	shared.Lock()
+	shared.running = true
+	shared.options = options
+	shared.Unlock()
+
+	return stopSharedServers(m.Run())
+}
+
+// stopSharedServers terminates the servers started by the tests and returns the exit code of the tests
+// It fails the exit code when a server cannot be terminated
+func stopSharedServers(code int) int {
+	shared.Lock()
+	defer shared.Unlock()
+	shared.running = false
+	servers := []Neo4jServer{shared.server}
+	for _, versioned := range shared.matrix {
+		servers = append(servers, versioned.server)
+	}
+	shared.matrix = nil
+	for _, server := range servers {
+		// servers that failed to start are not recorded
+		if server == nil {
+			continue
+		}
+		if err := server.Terminate(context.Background()); err != nil {
+			fmt.Fprintf(os.Stderr, "Could not stop shared Neo4j server: %v\n", err)
+			if code == 0 {
+				code = 1
+			}
+		}
+	}
+	return code
+}
+
+// Isolate returns a server whose default database is only used by the calling test
+// On enterprise servers, a fresh database is created along with a user whose home is that database
+// Both are dropped when the test completes
+// Otherwise, the shared server is wiped, see Wipe
+func Isolate(t testing.TB) Neo4jServer {
+	t.Helper()
+	server, edition := sharedServer(t)
+	return isolate(t, server, edition)
+}
+
+func isolate(t testing.TB, server Neo4jServer, edition string) Neo4jServer {
+	t.Helper()
+	if _, fake := server.(*fakebolt.Server); fake {
+		LogOnFailure(t, server)
+		return server
+	}
+	if edition != "enterprise" {
+		return wipeServer(t, server)
+	}
+	LogOnFailure(t, server)
+	isolated, err := createIsolatedDatabase(server, atomic.AddInt64(&isolatedCount, 1))
+	if err != nil {
+		t.Fatalf("Could not create isolated database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := dropIsolatedDatabase(server, isolated); err != nil {
+			t.Errorf("Could not drop isolated database %s: %v", isolated.database, err)
+		}
+	})
+	return isolated
+}
+
+// Wipe deletes all data, indexes and constraints of the shared server's default database and returns the server
+// Use it instead of Isolate when the test needs to use the default database, e.g. when it sets its name explicitly
+// External servers are only wiped when AllowWipeEnvVar is set to "yes"
+func Wipe(t testing.TB) Neo4jServer {
+	t.Helper()
+	server, _ := sharedServer(t)
+	return wipeServer(t, server)
+}
+
+func wipeServer(t testing.TB, server Neo4jServer) Neo4jServer {
+	t.Helper()
+	LogOnFailure(t, server)
+	if _, fake := server.(*fakebolt.Server); fake {
+		return server
+	}
+	if _, external := server.(*externalServer); external && os.Getenv(AllowWipeEnvVar) != "yes" {
+		t.Fatalf("Refusing to wipe external server %s, set %s=yes to allow it", server.BoltURI(), AllowWipeEnvVar)
+	}
+	if err := wipe(server); err != nil {
+		t.Fatalf("Could not wipe shared server: %v", err)
+	}
+	return server
+}
+
+// Pristine starts a server dedicated to the calling test, for tests that cannot share it
+// The options are applied after the ones given to Main and the server is terminated when the test completes
+func Pristine(t testing.TB, options ...Option) Neo4jServer {
+	t.Helper()
+	shared.Lock()
+	allOptions := append(append([]Option(nil), shared.options...), options...)
+	shared.Unlock()
+	server, err := Start(context.Background(), allOptions...)
+	if err != nil {
+		t.Fatalf("Could not start pristine Neo4j server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := server.Terminate(context.Background()); err != nil {
+			t.Errorf("Could not stop pristine Neo4j server: %v", err)
+		}
+	})
+	// registered last so that it runs before the server is terminated
+	LogOnFailure(t, server)
+	return server
+}
+
+// sharedServer starts the shared server on first use and returns it along with its edition
+func sharedServer(t testing.TB) (Neo4jServer, string) {
+	t.Helper()
+	shared.Lock()
+	defer shared.Unlock()
+	if !shared.running {
+		t.Fatal("The shared Neo4j server is only available when TestMain calls neo4jtest.Main")
+	}
+	if !shared.started {
+		shared.started = true
+		shared.server, shared.edition, shared.err = startShared(shared.options)
+	}
+	if shared.err != nil {
+		t.Fatalf("Could not start shared Neo4j server: %v", shared.err)
+	}
+	return shared.server, shared.edition
+}
+
+// startShared starts a server and detects its edition
+// The server is only nil when it did not start, it is returned along with the error when its edition is unknown
+// ... so that Main still terminates it
+func startShared(options []Option) (Neo4jServer, string, error) {
+	server, err := Start(context.Background(), options...)
+	if err != nil {
+		return nil, "", err
+	}
+	edition, err := detectEdition(server)
+	return server, edition, err
+}
+
+func detectEdition(server Neo4jServer) (string, error) {
+	if _, fake := server.(*fakebolt.Server); fake {
+		return "", nil
+	}
+	edition, err := runSingle(server, "CALL dbms.components() YIELD edition RETURN edition", nil)
+	if err != nil {
+		return "", fmt.Errorf("could not detect server edition: %w", err)
+	}
+	return edition.(string), nil
+}
+
+// isolatedServer shares the server it was created on, only its credentials differ
+type isolatedServer struct {
+	Neo4jServer
+	database string
+	username string
+}
+
+func (server *isolatedServer) AuthToken() neo4j.AuthToken {
+	return neo4j.BasicAuth(server.username, isolatedPassword, "")
+}
+
+func (server *isolatedServer) Credentials() (string, string) {
+	return server.username, isolatedPassword
+}
+
+// RootCAs returns the certificate authority of the server it was created on, if any
+func (server *isolatedServer) RootCAs() *x509.CertPool {
+	if authority, ok := server.Neo4jServer.(certificateAuthority); ok {
+		return authority.RootCAs()
+	}
+	return nil
+}
+
+// Terminate is a no-op, the isolated database is dropped when the test completes
+func (server *isolatedServer) Terminate(context.Context) error {
+	return nil
+}
+
+func createIsolatedDatabase(server Neo4jServer, id int64) (*isolatedServer, error) {
+	isolated := &isolatedServer{
+		Neo4jServer: server,
+		database:    fmt.Sprintf("test-%d", id),
+		username:    fmt.Sprintf("test_%d", id),
+	}
+	return isolated, runAll(server, "system", []string{
+		fmt.Sprintf("CREATE DATABASE `%s` WAIT", isolated.database),
+		fmt.Sprintf("CREATE USER %s SET PLAINTEXT PASSWORD '%s' CHANGE NOT REQUIRED SET HOME DATABASE `%s`",
+			isolated.username, isolatedPassword, isolated.database),
+		fmt.Sprintf("GRANT ROLE admin TO %s", isolated.username),
+	})
+}
+
+func dropIsolatedDatabase(server Neo4jServer, isolated *isolatedServer) error {
+	return runAll(server, "system", []string{
+		fmt.Sprintf("DROP USER %s IF EXISTS", isolated.username),
+		fmt.Sprintf("DROP DATABASE `%s` IF EXISTS WAIT", isolated.database),
+	})
+}
+
+// wipe drops constraints before indexes, since dropping a constraint also drops its backing index
+// note: with Neo4j, you cannot mix schema and data operations, hence the separate transactions
+func wipe(server Neo4jServer) error {
+	if err := runAll(server, "", []string{"MATCH (n) DETACH DELETE n"}); err != nil {
+		return err
+	}
+	constraints, err := collectNames(server, "", "SHOW CONSTRAINTS YIELD name RETURN name")
+	if err != nil {
+		return err
+	}
+	if err := runAll(server, "", prefixAll("DROP CONSTRAINT ", constraints)); err != nil {
+		return err
+	}
+	// token lookup indexes exist by default and are not created by tests
+	indexes, err := collectNames(server, "", "SHOW INDEXES YIELD name, type WHERE type <> 'LOOKUP' RETURN name")
+	if err != nil {
+		return err
+	}
+	return runAll(server, "", prefixAll("DROP INDEX ", indexes))
+}
+
+func prefixAll(prefix string, names []string) []string {
+	queries := make([]string, len(names))
+	for i, name := range names {
+		queries[i] = fmt.Sprintf("%s`%s`", prefix, name)
+	}
+	return queries
+}
+
+// runAll runs each query as an auto-commit query, since administration commands cannot run in transaction functions
+func runAll(server Neo4jServer, database string, queries []string) error {
+	return withSession(server, database, func(session neo4j.Session) error {
+		for _, query := range queries {
+			result, err := session.Run(query, nil)
+			if err != nil {
+				return fmt.Errorf("could not run %q: %w", query, err)
+			}
+			if _, err := result.Consume(); err != nil {
+				return fmt.Errorf("could not run %q: %w", query, err)
+			}
+		}
+		return nil
+	})
+}
+
+func collectNames(server Neo4jServer, database string, query string) ([]string, error) {
+	var names []string
+	err := withSession(server, database, func(session neo4j.Session) error {
+		result, err := session.Run(query, nil)
+		if err != nil {
+			return err
+		}
+		for result.Next() {
+			names = append(names, result.Record().Values[0].(string))
+		}
+		return result.Err()
+	})
+	return names, err
+}
+
+func runSingle(server Neo4jServer, query string, params map[string]any) (any, error) {
+	var value any
+	err := withSession(server, "", func(session neo4j.Session) error {
+		result, err := session.Run(query, params)
+		if err != nil {
+			return err
+		}
+		record, err := result.Single()
+		if err != nil {
+			return err
+		}
+		value = record.Values[0]
+		return nil
+	})
+	return value, err
+}
+
+func withSession(server Neo4jServer, database string, work func(neo4j.Session) error) (err error) {
+	driver, err := NewDriver(server, "")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := driver.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	session := driver.NewSession(neo4j.SessionConfig{DatabaseName: database, AccessMode: neo4j.AccessModeWrite})
+	defer func() {
+		if closeErr := session.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return work(session)
+}
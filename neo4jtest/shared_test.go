@@ -0,0 +1,211 @@
+package neo4jtest
+
+import (
+	"fmt"
+	"graphconnect/neo4jtest/fakebolt"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSharedServer(outer *testing.T) {
+	outer.Run("isolates tests in fresh databases on enterprise servers", func(t *testing.T) {
+		server := startFakeServer(t, fakebolt.Script{
+			"CREATE DATABASE `test-1` WAIT": {},
+			"CREATE USER test_1 SET PLAINTEXT PASSWORD 's3cr3t' CHANGE NOT REQUIRED SET HOME DATABASE `test-1`": {},
+			"GRANT ROLE admin TO test_1":            {},
+			"DROP USER test_1 IF EXISTS":            {},
+			"DROP DATABASE `test-1` IF EXISTS WAIT": {},
+		})
+		useSharedServer(t, &externalServer{uri: server.BoltURI()}, "enterprise")
+
+		t.Run("isolated test", func(t *testing.T) {
+			isolated := Isolate(t)
+
+			if username, _ := isolated.Credentials(); username != "test_1" {
+				t.Errorf("Expected isolated user test_1, got: %s", username)
+			}
+		})
+
+		expected := []string{
+			"CREATE DATABASE `test-1` WAIT",
+			"CREATE USER test_1 SET PLAINTEXT PASSWORD 's3cr3t' CHANGE NOT REQUIRED SET HOME DATABASE `test-1`",
+			"GRANT ROLE admin TO test_1",
+			"DROP USER test_1 IF EXISTS",
+			"DROP DATABASE `test-1` IF EXISTS WAIT",
+		}
+		if queries := receivedQueries(server); !reflect.DeepEqual(queries, expected) {
+			t.Errorf("Expected %v, got: %v", expected, queries)
+		}
+	})
+	outer.Run("wipes data, constraints and indexes otherwise", func(t *testing.T) {
+		t.Setenv(AllowWipeEnvVar, "yes")
+		server := startFakeServer(t, fakebolt.Script{
+			"MATCH (n) DETACH DELETE n": {Counters: map[string]int{"nodes-deleted": 8}},
+			"SHOW CONSTRAINTS YIELD name RETURN name": {
+				Keys:    []string{"name"},
+				Records: [][]any{{"person_name"}},
+			},
+			"DROP CONSTRAINT `person_name`": {Counters: map[string]int{"constraints-removed": 1}},
+			"SHOW INDEXES YIELD name, type WHERE type <> 'LOOKUP' RETURN name": {
+				Keys:    []string{"name"},
+				Records: [][]any{{"topic_name"}, {"project_name"}},
+			},
+			"DROP INDEX `topic_name`":   {Counters: map[string]int{"indexes-removed": 1}},
+			"DROP INDEX `project_name`": {Counters: map[string]int{"indexes-removed": 1}},
+		})
+		useSharedServer(t, &externalServer{uri: server.BoltURI()}, "community")
+
+		Isolate(t)
+
+		expected := []string{
+			"MATCH (n) DETACH DELETE n",
+			"SHOW CONSTRAINTS YIELD name RETURN name",
+			"DROP CONSTRAINT `person_name`",
+			"SHOW INDEXES YIELD name, type WHERE type <> 'LOOKUP' RETURN name",
+			"DROP INDEX `topic_name`",
+			"DROP INDEX `project_name`",
+		}
+		if queries := receivedQueries(server); !reflect.DeepEqual(queries, expected) {
+			t.Errorf("Expected %v, got: %v", expected, queries)
+		}
+	})
+	outer.Run("shares fake servers as-is", func(t *testing.T) {
+		server := startFakeServer(t, fakebolt.Script{})
+		useSharedServer(t, server, "")
+
+		if isolated := Isolate(t); isolated != server {
+			t.Errorf("Expected fake server to be shared, got: %v", isolated)
+		}
+		if queries := receivedQueries(server); len(queries) > 0 {
+			t.Errorf("Expected no query to run, got: %v", queries)
+		}
+	})
+}
+
+func TestStopSharedServers(outer *testing.T) {
+	outer.Run("reports the start errors of the shared server", func(t *testing.T) {
+		t.Setenv(BackendEnvVar, string(ContainerBackend))
+		useSharedServer(t, nil, "")
+		shared.Lock()
+		previous := shared.options
+		t.Cleanup(func() {
+			shared.Lock()
+			defer shared.Unlock()
+			shared.options, shared.err = previous, nil
+		})
+		shared.started = false
+		// the missing jar fails the start before Docker is reached
+		shared.options = []Option{WithPlugins(Plugin{Name: "missing", JarPath: "testdata/missing.jar"})}
+		shared.Unlock()
+		recorder := &fatalRecorder{TB: t}
+
+		server, _ := sharedServer(recorder)
+		code := stopSharedServers(0)
+
+		if server != nil || len(recorder.fatals) != 1 || !strings.Contains(recorder.fatals[0], "Could not start shared Neo4j server") {
+			t.Errorf("Expected the start error and no server, got: %v and %#v", recorder.fatals, server)
+		}
+		if code != 0 {
+			t.Errorf("Expected exit code 0, got: %d", code)
+		}
+	})
+	outer.Run("reports the start errors of the matrix servers", func(t *testing.T) {
+		t.Setenv(BackendEnvVar, string(ContainerBackend))
+		useSharedServer(t, nil, "")
+		shared.Lock()
+		previous := shared.options
+		t.Cleanup(func() {
+			shared.Lock()
+			defer shared.Unlock()
+			shared.options = previous
+		})
+		// the missing jar fails the start before Docker is reached
+		shared.options = []Option{WithPlugins(Plugin{Name: "missing", JarPath: "testdata/missing.jar"})}
+		shared.Unlock()
+		recorder := &fatalRecorder{TB: t}
+
+		server, _ := versionServer(recorder, "4.3")
+		versioned := shared.matrix["4.3"]
+		code := stopSharedServers(0)
+
+		if server != nil || len(recorder.fatals) != 1 || !strings.Contains(recorder.fatals[0], "Could not start Neo4j 4.3 server") {
+			t.Errorf("Expected the start error and no server, got: %v and %#v", recorder.fatals, server)
+		}
+		if versioned == nil || versioned.server != nil {
+			t.Errorf("Expected the start error to be recorded without server, got: %#v", versioned)
+		}
+		if code != 0 {
+			t.Errorf("Expected exit code 0, got: %d", code)
+		}
+	})
+	outer.Run("terminates the shared servers", func(t *testing.T) {
+		server, err := fakebolt.Start(fakebolt.Script{})
+		if err != nil {
+			t.Fatalf("Could not start fake server: %v", err)
+		}
+		useSharedServer(t, server, "")
+
+		code := stopSharedServers(0)
+
+		if code != 0 {
+			t.Errorf("Expected exit code 0, got: %d", code)
+		}
+		if conn, err := net.Dial("tcp", server.Address()); err == nil {
+			_ = conn.Close()
+			t.Errorf("Expected the shared server to be terminated")
+		}
+	})
+}
+
+// fatalRecorder records the fatal errors of a test instead of stopping it
+type fatalRecorder struct {
+	testing.TB
+	fatals []string
+}
+
+func (recorder *fatalRecorder) Fatal(args ...any) {
+	recorder.fatals = append(recorder.fatals, fmt.Sprint(args...))
+}
+
+func (recorder *fatalRecorder) Fatalf(format string, args ...any) {
+	recorder.fatals = append(recorder.fatals, fmt.Sprintf(format, args...))
+}
+
+func startFakeServer(t *testing.T, script fakebolt.Script) *fakebolt.Server {
+	server, err := fakebolt.Start(script)
+	if err != nil {
+		t.Fatalf("Could not start fake server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := server.Close(); err != nil {
+			t.Errorf("Could not close fake server: %v", err)
+		}
+	})
+	return server
+}
+
+// useSharedServer makes the server available as if Main had started it
+func useSharedServer(t *testing.T, server Neo4jServer, edition string) {
+	shared.Lock()
+	defer shared.Unlock()
+	shared.running, shared.started = true, true
+	shared.server, shared.edition, shared.err = server, edition, nil
+	isolatedCount = 0
+	t.Cleanup(func() {
+		shared.Lock()
+		defer shared.Unlock()
+		shared.running, shared.started = false, false
+		shared.server, shared.edition = nil, ""
+		shared.matrix = nil
+	})
+}
+
+func receivedQueries(server *fakebolt.Server) []string {
+	var queries []string
+	for _, query := range server.Received() {
+		queries = append(queries, query.Text)
+	}
+	return queries
+}
@@ -0,0 +1,227 @@
+package neo4jtest
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+)
+
+const certificatesDirectory = "/ssl/bolt"
+const certificateValidity = 24 * time.Hour
+
+// WithTLS makes the container require encrypted Bolt connections
+// A certificate authority and a server certificate are generated when the container starts,
+// the authority is available with Neo4jContainer.CACertificate and BoltURI uses the neo4j+s scheme
+func WithTLS() Option {
+	return func(config *ContainerConfiguration) {
+		config.TLS = true
+	}
+}
+
+// NewDriver creates a driver connected to the server with the given URI scheme, e.g. "neo4j+s" or "neo4j+ssc"
+// The scheme of the server BoltURI is kept when empty
+// With a verifying scheme (+s), the certificate authority of a server started WithTLS is trusted
+// The configurers run last, e.g. TrustRootCAs trusts other authorities
+func NewDriver(server Neo4jServer, scheme string, configurers ...func(*neo4j.Config)) (neo4j.Driver, error) {
+	uri, rootCAs, err := driverTarget(server, scheme)
+	if err != nil {
+		return nil, err
+	}
+	if rootCAs != nil {
+		configurers = append([]func(*neo4j.Config){func(config *neo4j.Config) {
+			config.RootCAs = rootCAs
+		}}, configurers...)
+	}
+	return neo4j.NewDriver(uri, server.AuthToken(), configurers...)
+}
+
+// driverTarget returns the URI of the server with the given scheme, along with the authority to trust, if any
+func driverTarget(server Neo4jServer, scheme string) (string, *x509.CertPool, error) {
+	uri, err := url.Parse(server.BoltURI())
+	if err != nil {
+		return "", nil, fmt.Errorf("could not parse Bolt URI: %w", err)
+	}
+	if scheme != "" {
+		uri.Scheme = scheme
+	}
+	if authority, ok := server.(certificateAuthority); ok && strings.HasSuffix(uri.Scheme, "+s") {
+		return uri.String(), authority.RootCAs(), nil
+	}
+	return uri.String(), nil, nil
+}
+
+// TrustRootCAs makes the driver trust the PEM-encoded certificate authorities instead of the system ones
+// It panics if no certificate can be parsed, which is a mistake in the test
+func TrustRootCAs(pemCertificates ...[]byte) func(*neo4j.Config) {
+	rootCAs := parseRootCAs(pemCertificates)
+	return func(config *neo4j.Config) {
+		config.RootCAs = rootCAs
+	}
+}
+
+func parseRootCAs(pemCertificates [][]byte) *x509.CertPool {
+	rootCAs := x509.NewCertPool()
+	for _, certificates := range pemCertificates {
+		if !rootCAs.AppendCertsFromPEM(certificates) {
+			panic("neo4jtest: could not parse PEM certificates")
+		}
+	}
+	return rootCAs
+}
+
+// certificateAuthority is implemented by servers whose certificate is signed by their own authority
+type certificateAuthority interface {
+	RootCAs() *x509.CertPool
+}
+
+// containerTLS holds the certificates generated for a container, written to a directory bind-mounted in the container
+type containerTLS struct {
+	caCertificate     []byte
+	serverCertificate []byte
+	serverKey         []byte
+	directory         string
+}
+
+// newContainerTLS generates a certificate authority and a server certificate valid for the local hosts
+func newContainerTLS() (*containerTLS, error) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	caTemplate := certificateTemplate("neo4jtest CA")
+	caTemplate.IsCA = true
+	caTemplate.BasicConstraintsValid = true
+	caTemplate.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not create CA certificate: %w", err)
+	}
+	serverKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	serverTemplate := certificateTemplate("localhost")
+	serverTemplate.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment
+	serverTemplate.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	for _, host := range serverHosts() {
+		if ip := net.ParseIP(host); ip != nil {
+			serverTemplate.IPAddresses = append(serverTemplate.IPAddresses, ip)
+		} else {
+			serverTemplate.DNSNames = append(serverTemplate.DNSNames, host)
+		}
+	}
+	serverDER, err := x509.CreateCertificate(rand.Reader, serverTemplate, caTemplate, &serverKey.PublicKey, caKey)
+	if err != nil {
+		return nil, fmt.Errorf("could not create server certificate: %w", err)
+	}
+	// Neo4j reads PKCS #8 private keys
+	serverKeyDER, err := x509.MarshalPKCS8PrivateKey(serverKey)
+	if err != nil {
+		return nil, err
+	}
+	result := &containerTLS{
+		caCertificate:     pemEncode("CERTIFICATE", caDER),
+		serverCertificate: pemEncode("CERTIFICATE", serverDER),
+		serverKey:         pemEncode("PRIVATE KEY", serverKeyDER),
+	}
+	if result.directory, err = os.MkdirTemp("", "neo4jtest-tls-"); err != nil {
+		return nil, err
+	}
+	// the container runs as another user, hence the permissive modes of these throwaway files
+	if err := os.Chmod(result.directory, 0o755); err != nil {
+		return nil, result.removeAfter(err)
+	}
+	for path, content := range map[string][]byte{
+		"public.crt":     result.serverCertificate,
+		"private.key":    result.serverKey,
+		"trusted/ca.crt": result.caCertificate,
+	} {
+		if err := writeFile(filepath.Join(result.directory, path), content); err != nil {
+			return nil, result.removeAfter(err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Join(result.directory, "revoked"), 0o755); err != nil {
+		return nil, result.removeAfter(err)
+	}
+	return result, nil
+}
+
+// tlsSettings returns the settings enabling the Bolt SSL policy on top of the configured ones
+func (config ContainerConfiguration) tlsSettings() map[string]string {
+	settings := map[string]string{
+		"dbms.ssl.policy.bolt.enabled":            "true",
+		"dbms.ssl.policy.bolt.base_directory":     certificatesDirectory,
+		"dbms.ssl.policy.bolt.private_key":        "private.key",
+		"dbms.ssl.policy.bolt.public_certificate": "public.crt",
+		"dbms.ssl.policy.bolt.client_auth":        "NONE",
+		"dbms.connector.bolt.tls_level":           "REQUIRED",
+	}
+	if config.majorVersion() >= 5 {
+		delete(settings, "dbms.connector.bolt.tls_level")
+		settings["server.bolt.tls_level"] = "REQUIRED"
+	}
+	for key, value := range config.Settings {
+		settings[key] = value
+	}
+	return settings
+}
+
+func (tls *containerTLS) rootCAs() *x509.CertPool {
+	rootCAs := x509.NewCertPool()
+	rootCAs.AppendCertsFromPEM(tls.caCertificate)
+	return rootCAs
+}
+
+func (tls *containerTLS) remove() error {
+	return os.RemoveAll(tls.directory)
+}
+
+func (tls *containerTLS) removeAfter(cause error) error {
+	if err := tls.remove(); err != nil {
+		return fmt.Errorf("%v (and could not remove certificates: %w)", cause, err)
+	}
+	return cause
+}
+
+func certificateTemplate(commonName string) *x509.Certificate {
+	serialNumber, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	return &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: commonName},
+		// tolerate clock skews between the host and the Docker VM
+		NotBefore: time.Now().Add(-time.Hour),
+		NotAfter:  time.Now().Add(certificateValidity),
+	}
+}
+
+// serverHosts returns the hosts the tests may connect to, including the remote Docker host if any
+func serverHosts() []string {
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if dockerHost, err := url.Parse(os.Getenv("DOCKER_HOST")); err == nil && dockerHost.Scheme == "tcp" {
+		hosts = append(hosts, dockerHost.Hostname())
+	}
+	return hosts
+}
+
+func pemEncode(blockType string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
+
+func writeFile(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0o644)
+}
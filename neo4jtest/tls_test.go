@@ -0,0 +1,166 @@
+package neo4jtest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"graphconnect/neo4jtest/fakebolt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v4/neo4j"
+	neo4j5 "github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestTLS(outer *testing.T) {
+	outer.Run("generates a server certificate signed by the certificate authority", func(t *testing.T) {
+		certificates := generateCertificates(t)
+
+		for _, path := range []string{"public.crt", "private.key", "trusted/ca.crt", "revoked"} {
+			if _, err := os.Stat(filepath.Join(certificates.directory, path)); err != nil {
+				t.Errorf("Expected %s to be written, got: %v", path, err)
+			}
+		}
+		block, _ := pem.Decode(certificates.serverCertificate)
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			t.Fatalf("Could not parse server certificate: %v", err)
+		}
+		for _, host := range []string{"localhost", "127.0.0.1"} {
+			if _, err := certificate.Verify(x509.VerifyOptions{DNSName: host, Roots: certificates.rootCAs()}); err != nil {
+				t.Errorf("Expected certificate to be valid for %s, got: %v", host, err)
+			}
+		}
+	})
+	outer.Run("enables the Bolt SSL policy", func(t *testing.T) {
+		for version, tlsLevelKey := range map[string]string{
+			"4.4": "dbms.connector.bolt.tls_level",
+			"5.3": "server.bolt.tls_level",
+		} {
+			config := newContainerConfiguration([]Option{WithVersion(version), WithTLS()})
+
+			settings := config.tlsSettings()
+
+			expected := map[string]string{
+				"dbms.ssl.policy.bolt.enabled":            "true",
+				"dbms.ssl.policy.bolt.base_directory":     "/ssl/bolt",
+				"dbms.ssl.policy.bolt.private_key":        "private.key",
+				"dbms.ssl.policy.bolt.public_certificate": "public.crt",
+				"dbms.ssl.policy.bolt.client_auth":        "NONE",
+				tlsLevelKey:                               "REQUIRED",
+			}
+			if !reflect.DeepEqual(settings, expected) {
+				t.Errorf("Expected %v for version %s, got: %v", expected, version, settings)
+			}
+		}
+	})
+	outer.Run("connects with neo4j+s, neo4j+ssc and custom root CAs", func(t *testing.T) {
+		certificates := generateCertificates(t)
+		server := startTLSServer(t, certificates)
+		other := generateCertificates(t)
+
+		for _, scenario := range []struct {
+			name        string
+			server      Neo4jServer
+			scheme      string
+			configurers []func(*neo4j.Config)
+			valid       bool
+		}{
+			{"trusts the server authority", server, "", nil, true},
+			{"skips verification of self-signed certificates", &externalServer{uri: server.BoltURI()}, "neo4j+ssc", nil, true},
+			{"trusts custom root CAs", &externalServer{uri: server.BoltURI()}, "", []func(*neo4j.Config){TrustRootCAs(certificates.caCertificate)}, true},
+			{"rejects unknown authorities", server, "", []func(*neo4j.Config){TrustRootCAs(other.caCertificate)}, false},
+			{"rejects plain text", server, "neo4j", nil, false},
+		} {
+			driver, err := NewDriver(scenario.server, scenario.scheme, scenario.configurers...)
+			if err != nil {
+				t.Fatalf("Could not create driver: %v", err)
+			}
+			err = driver.VerifyConnectivity()
+			_ = driver.Close()
+
+			if scenario.valid && err != nil {
+				t.Errorf("Expected driver to connect when it %s, got: %v", scenario.name, err)
+			}
+			if !scenario.valid && err == nil {
+				t.Errorf("Expected driver not to connect when it %s, got nil", scenario.name)
+			}
+		}
+	})
+	outer.Run("connects 5.x drivers with neo4j+s and custom root CAs", func(t *testing.T) {
+		certificates := generateCertificates(t)
+		server := startTLSServer(t, certificates)
+		other := generateCertificates(t)
+		ctx := context.Background()
+
+		for _, scenario := range []struct {
+			name        string
+			server      Neo4jServer
+			scheme      string
+			configurers []func(*neo4j5.Config)
+			valid       bool
+		}{
+			{"trusts the server authority", server, "", nil, true},
+			{"trusts custom root CAs", &externalServer{uri: server.BoltURI()}, "", []func(*neo4j5.Config){TrustRootCAsWithContext(certificates.caCertificate)}, true},
+			{"rejects unknown authorities", server, "", []func(*neo4j5.Config){TrustRootCAsWithContext(other.caCertificate)}, false},
+			{"rejects plain text", server, "neo4j", nil, false},
+		} {
+			driver, err := NewDriverWithContext(scenario.server, scenario.scheme, scenario.configurers...)
+			if err != nil {
+				t.Fatalf("Could not create driver: %v", err)
+			}
+			err = driver.VerifyConnectivity(ctx)
+			_ = driver.Close(ctx)
+
+			if scenario.valid && err != nil {
+				t.Errorf("Expected driver to connect when it %s, got: %v", scenario.name, err)
+			}
+			if !scenario.valid && err == nil {
+				t.Errorf("Expected driver not to connect when it %s, got nil", scenario.name)
+			}
+		}
+	})
+}
+
+func generateCertificates(t *testing.T) *containerTLS {
+	certificates, err := newContainerTLS()
+	if err != nil {
+		t.Fatalf("Could not generate certificates: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := certificates.remove(); err != nil {
+			t.Errorf("Could not remove certificates: %v", err)
+		}
+	})
+	return certificates
+}
+
+// tlsServer is a fake server presenting the generated server certificate
+type tlsServer struct {
+	*fakebolt.Server
+	certificates *containerTLS
+}
+
+func (server *tlsServer) RootCAs() *x509.CertPool {
+	return server.certificates.rootCAs()
+}
+
+func startTLSServer(t *testing.T, certificates *containerTLS) *tlsServer {
+	keyPair, err := tls.X509KeyPair(certificates.serverCertificate, certificates.serverKey)
+	if err != nil {
+		t.Fatalf("Could not load key pair: %v", err)
+	}
+	server, err := fakebolt.Start(fakebolt.Script{}, fakebolt.WithTLS(&tls.Config{Certificates: []tls.Certificate{keyPair}}))
+	if err != nil {
+		t.Fatalf("Could not start fake server: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := server.Close(); err != nil {
+			t.Errorf("Could not close fake server: %v", err)
+		}
+	})
+	return &tlsServer{Server: server, certificates: certificates}
+}